@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"desktop-ai-tools/config"
+	"desktop-ai-tools/models"
+	"desktop-ai-tools/openapi"
+	"desktop-ai-tools/utils"
+)
+
+// settingsData 设置接口返回的数据
+type settingsData struct {
+	config.SettingsView
+	APIBaseURL      string   `json:"api_base_url"`
+	APIPort         int      `json:"api_port"`
+	RestartRequired []string `json:"restart_required,omitempty"` // 仅更新设置时返回，需要重启才能生效的配置项
+}
+
+// toolsData 工具发现和刷新接口返回的数据
+type toolsData struct {
+	Tools      []models.MCPTool `json:"tools"`
+	ToolsCount int              `json:"tools_count"`
+}
+
+// apiOperations 所有HTTP接口的文档，新增路由时需要同步添加，否则 TestRoutesDocumented 会失败
+var apiOperations = []openapi.Operation{
+	{Method: http.MethodPost, Path: "/api/hello", Tag: "system", Summary: "问候测试", Body: HelloRequest{}, Response: HelloResponse{}},
+	{Method: http.MethodGet, Path: "/api/health", Tag: "system", Summary: "健康检查", Response: map[string]string{}, Public: true},
+	{Method: http.MethodGet, Path: "/api/openapi.json", Tag: "system", Summary: "获取 OpenAPI 文档", Produces: "application/json", Public: true},
+	{Method: http.MethodGet, Path: "/api/events", Tag: "system", Summary: "通过SSE订阅应用内事件，可按主题过滤", Query: models.EventStreamRequest{}, Produces: "text/event-stream"},
+	{Method: http.MethodGet, Path: "/metrics", Tag: "system", Summary: "Prometheus 格式的运行指标，默认需要令牌，server.metrics_public 为true时无需令牌", Produces: "text/plain"},
+	{Method: http.MethodGet, Path: "/api/logs", Tag: "system", Summary: "查询应用日志，follow=true 时通过SSE推送", Query: models.AppLogListRequest{}, Response: models.AppLogListResponse{}},
+	{Method: http.MethodGet, Path: "/api/test-error", Tag: "system", Summary: "测试错误处理", Query: struct {
+		Type string `form:"type" binding:"omitempty,oneof=400 404 500"`
+	}{}, Response: map[string]string{}},
+	{Method: http.MethodGet, Path: "/api/test-panic", Tag: "system", Summary: "测试panic处理"},
+
+	// MCP Server
+	{Method: http.MethodGet, Path: "/api/mcp-servers", Tag: "mcp-servers", Summary: "查询服务器列表", Query: models.MCPServerListRequest{}, Response: models.MCPServerListResponse{}},
+	{Method: http.MethodPost, Path: "/api/mcp-servers", Tag: "mcp-servers", Summary: "创建服务器", Body: models.MCPServerCreateRequest{}, Response: models.MCPServer{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/mcp-servers/:id", Tag: "mcp-servers", Summary: "获取服务器详情", Response: models.MCPServer{}},
+	{Method: http.MethodPut, Path: "/api/mcp-servers/:id", Tag: "mcp-servers", Summary: "更新服务器", Body: models.MCPServerUpdateRequest{}, Response: models.MCPServer{}},
+	{Method: http.MethodDelete, Path: "/api/mcp-servers/:id", Tag: "mcp-servers", Summary: "删除服务器"},
+	{Method: http.MethodPut, Path: "/api/mcp-servers/:id/status", Tag: "mcp-servers", Summary: "更新服务器状态", Body: models.MCPServerStatusUpdateRequest{}},
+	{Method: http.MethodPut, Path: "/api/mcp-servers/:id/toggle", Tag: "mcp-servers", Summary: "切换服务器启用状态", Response: models.MCPServer{}},
+	{Method: http.MethodPut, Path: "/api/mcp-servers/:id/sampling-policy", Tag: "mcp-servers", Summary: "更新服务器采样策略，SSE服务器无法发起采样请求，只能设置为拒绝或默认", Body: models.SamplingPolicyUpdateRequest{}},
+	{Method: http.MethodPut, Path: "/api/mcp-servers/:id/log-level", Tag: "mcp-servers", Summary: "更新服务器日志级别", Body: models.MCPServerLogLevelRequest{}},
+	{Method: http.MethodGet, Path: "/api/mcp-servers/:id/logs", Tag: "mcp-servers", Summary: "查询服务器日志，follow=true 时通过SSE推送", Query: models.MCPServerLogListRequest{}, Response: models.MCPServerLogListResponse{}},
+	{Method: http.MethodDelete, Path: "/api/mcp-servers/:id/logs", Tag: "mcp-servers", Summary: "清空服务器日志"},
+	{Method: http.MethodGet, Path: "/api/mcp-servers/:id/traffic", Tag: "mcp-servers", Summary: "查询抓取的JSON-RPC流量，follow=true 时通过SSE推送", Query: models.MCPTrafficListRequest{}, Response: models.MCPTrafficListResponse{}},
+	{Method: http.MethodPut, Path: "/api/mcp-servers/:id/traffic/capture", Tag: "mcp-servers", Summary: "开启或关闭JSON-RPC流量抓取", Body: models.MCPTrafficCaptureRequest{}, Response: models.MCPTrafficStatus{}},
+	{Method: http.MethodGet, Path: "/api/mcp-servers/:id/traffic/export", Tag: "mcp-servers", Summary: "以JSONL格式导出抓取的流量", Query: models.MCPTrafficListRequest{}, Produces: "application/x-ndjson"},
+	{Method: http.MethodDelete, Path: "/api/mcp-servers/:id/traffic", Tag: "mcp-servers", Summary: "清空抓取的流量", Response: map[string]int{}},
+	{Method: http.MethodGet, Path: "/api/mcp-servers/tags", Tag: "mcp-servers", Summary: "获取服务器标签", Response: []string{}},
+	{Method: http.MethodPost, Path: "/api/mcp-servers/:id/discover-tools", Tag: "mcp-servers", Summary: "发现服务器工具", Response: toolsData{}},
+	{Method: http.MethodGet, Path: "/api/mcp-servers/:id/cache", Tag: "mcp-servers", Summary: "获取工具结果缓存统计", Response: models.ToolCacheStats{}},
+	{Method: http.MethodDelete, Path: "/api/mcp-servers/:id/cache", Tag: "mcp-servers", Summary: "清除工具结果缓存", Query: struct {
+		Tool string `form:"tool"` // 为空时清除服务器所有工具的缓存
+	}{}, Response: map[string]int{}},
+	{Method: http.MethodGet, Path: "/api/mcp-servers/import/sources", Tag: "mcp-servers", Summary: "检测可导入的客户端配置", Response: []models.ClientConfigSource{}},
+	{Method: http.MethodPost, Path: "/api/mcp-servers/import/preview", Tag: "mcp-servers", Summary: "预览导入", Body: models.MCPServerImportRequest{}, Response: models.MCPServerImportResponse{}},
+	{Method: http.MethodPost, Path: "/api/mcp-servers/import", Tag: "mcp-servers", Summary: "从客户端配置导入服务器", Body: models.MCPServerImportRequest{}, Response: models.MCPServerImportResponse{}},
+	{Method: http.MethodPost, Path: "/api/mcp-servers/export", Tag: "mcp-servers", Summary: "导出为客户端配置", Body: models.MCPServerExportRequest{}, Response: models.MCPServerExportResponse{}},
+
+	// MCP Tools
+	{Method: http.MethodGet, Path: "/api/mcp-tools", Tag: "mcp-tools", Summary: "查询工具列表", Query: models.MCPToolListRequest{}, Response: models.MCPToolListResponse{}},
+	{Method: http.MethodPut, Path: "/api/mcp-tools/:id", Tag: "mcp-tools", Summary: "更新工具", Body: models.MCPToolUpdateRequest{}},
+	{Method: http.MethodPut, Path: "/api/mcp-tools/batch", Tag: "mcp-tools", Summary: "批量更新工具", Body: models.MCPToolBatchUpdateRequest{}},
+	{Method: http.MethodGet, Path: "/api/mcp-tools/categories", Tag: "mcp-tools", Summary: "获取工具分类", Query: struct {
+		ServerID uint `form:"server_id"`
+	}{}, Response: []string{}},
+	{Method: http.MethodPost, Path: "/api/mcp-tools/refresh/:serverID", Tag: "mcp-tools", Summary: "刷新服务器工具", Response: toolsData{}},
+	{Method: http.MethodPost, Path: "/api/mcp-tools/:id/call", Tag: "mcp-tools", Summary: "调用工具", Body: models.MCPToolCallRequest{}, Response: models.MCPToolCallResponse{}},
+	{Method: http.MethodPut, Path: "/api/mcp-tools/:id/tags", Tag: "mcp-tools", Summary: "设置工具标签", Body: models.TagAssignRequest{}, Response: models.MCPTool{}},
+
+	// 采样
+	{Method: http.MethodGet, Path: "/api/sampling/config", Tag: "sampling", Summary: "获取采样配置", Response: models.SamplingConfig{}},
+	{Method: http.MethodPut, Path: "/api/sampling/config", Tag: "sampling", Summary: "更新采样配置", Body: models.SamplingConfigUpdateRequest{}, Response: models.SamplingConfig{}},
+	{Method: http.MethodGet, Path: "/api/sampling/logs", Tag: "sampling", Summary: "查询采样日志", Query: models.SamplingLogListRequest{}, Response: models.SamplingLogListResponse{}},
+	{Method: http.MethodGet, Path: "/api/sampling/pending", Tag: "sampling", Summary: "获取待确认的采样请求", Response: []models.SamplingPendingRequest{}},
+	{Method: http.MethodPost, Path: "/api/sampling/pending/:id", Tag: "sampling", Summary: "确认或拒绝采样请求", Body: models.SamplingDecisionRequest{}, Params: map[string]string{"id": "string"}},
+
+	// 信息收集
+	{Method: http.MethodGet, Path: "/api/elicitations/pending", Tag: "elicitations", Summary: "获取待处理的信息收集请求", Response: []models.ElicitationPendingRequest{}},
+	{Method: http.MethodPost, Path: "/api/elicitations/:id/respond", Tag: "elicitations", Summary: "响应信息收集请求", Body: models.ElicitationRespondRequest{}, Params: map[string]string{"id": "string"}},
+
+	// 工作区根目录
+	{Method: http.MethodGet, Path: "/api/roots", Tag: "roots", Summary: "查询当前工作区的根目录列表，根目录只提供给 streamable_http 和 stdio 服务器，unsupported_servers 列出无法接收根目录的SSE服务器", Query: models.WorkspaceRootListRequest{}, Response: models.WorkspaceRootListResponse{}},
+	{Method: http.MethodPost, Path: "/api/roots", Tag: "roots", Summary: "创建根目录", Body: models.WorkspaceRootCreateRequest{}, Response: models.WorkspaceRoot{}, Status: http.StatusCreated},
+	{Method: http.MethodPut, Path: "/api/roots/:id", Tag: "roots", Summary: "更新根目录", Body: models.WorkspaceRootUpdateRequest{}, Response: models.WorkspaceRoot{}},
+	{Method: http.MethodDelete, Path: "/api/roots/:id", Tag: "roots", Summary: "删除根目录"},
+
+	// 备份与恢复
+	{Method: http.MethodGet, Path: "/api/backups", Tag: "backups", Summary: "查询备份列表", Response: []models.BackupInfo{}},
+	{Method: http.MethodPost, Path: "/api/backups", Tag: "backups", Summary: "创建备份，包含数据库中的配置和历史记录，不包含配置文件（见清单的 excludes）", Body: models.BackupCreateRequest{}, Response: models.BackupInfo{}, Status: http.StatusCreated},
+	{Method: http.MethodPost, Path: "/api/backups/upload", Tag: "backups", Summary: "上传备份文件", FormFile: "file", Response: models.BackupInfo{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/backups/schedule", Tag: "backups", Summary: "获取定时备份设置", Response: models.BackupSchedule{}},
+	{Method: http.MethodPut, Path: "/api/backups/schedule", Tag: "backups", Summary: "更新定时备份设置", Body: models.BackupScheduleUpdateRequest{}, Response: models.BackupSchedule{}},
+	{Method: http.MethodGet, Path: "/api/backups/:name/download", Tag: "backups", Summary: "下载备份文件", Produces: "application/octet-stream"},
+	{Method: http.MethodPost, Path: "/api/backups/:name/restore", Tag: "backups", Summary: "从备份恢复，覆盖模式同时恢复历史记录和审计日志，配置文件保持不变", Body: models.BackupRestoreRequest{}, Response: models.BackupRestoreResult{}},
+	{Method: http.MethodDelete, Path: "/api/backups/:name", Tag: "backups", Summary: "删除备份"},
+
+	// 标签
+	{Method: http.MethodGet, Path: "/api/tags", Tag: "tags", Summary: "查询当前工作区使用的标签和未使用的标签，数量只统计当前工作区", Query: models.TagListRequest{}, Response: []models.Tag{}},
+	{Method: http.MethodPost, Path: "/api/tags/merge", Tag: "tags", Summary: "合并标签", Body: models.TagMergeRequest{}, Response: models.Tag{}},
+	{Method: http.MethodPut, Path: "/api/tags/:id", Tag: "tags", Summary: "重命名标签", Body: models.TagRenameRequest{}, Response: models.Tag{}},
+	{Method: http.MethodDelete, Path: "/api/tags/:id", Tag: "tags", Summary: "删除标签"},
+
+	// 工作区
+	{Method: http.MethodGet, Path: "/api/workspaces", Tag: "workspaces", Summary: "查询工作区列表", Response: []models.Workspace{}},
+	{Method: http.MethodPost, Path: "/api/workspaces", Tag: "workspaces", Summary: "创建工作区", Body: models.WorkspaceCreateRequest{}, Response: models.Workspace{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/workspaces/active", Tag: "workspaces", Summary: "获取当前工作区", Response: models.Workspace{}},
+	{Method: http.MethodPut, Path: "/api/workspaces/:id", Tag: "workspaces", Summary: "更新工作区", Body: models.WorkspaceUpdateRequest{}, Response: models.Workspace{}},
+	{Method: http.MethodDelete, Path: "/api/workspaces/:id", Tag: "workspaces", Summary: "删除工作区"},
+	{Method: http.MethodPost, Path: "/api/workspaces/:id/activate", Tag: "workspaces", Summary: "切换到工作区", Response: models.Workspace{}},
+	{Method: http.MethodPost, Path: "/api/workspaces/:id/clone", Tag: "workspaces", Summary: "复制工作区", Body: models.WorkspaceCloneRequest{}, Response: models.Workspace{}, Status: http.StatusCreated},
+
+	// 审计日志
+	{Method: http.MethodGet, Path: "/api/audit", Tag: "audit", Summary: "查询审计日志", Query: models.AuditLogListRequest{}, Response: models.AuditLogListResponse{}},
+
+	// 命名密钥
+	{Method: http.MethodGet, Path: "/api/secrets", Tag: "secrets", Summary: "查询密钥列表", Response: []models.Secret{}},
+	{Method: http.MethodPost, Path: "/api/secrets", Tag: "secrets", Summary: "创建密钥", Body: models.SecretCreateRequest{}, Response: models.Secret{}, Status: http.StatusCreated},
+	{Method: http.MethodPut, Path: "/api/secrets/:name", Tag: "secrets", Summary: "更新密钥", Body: models.SecretUpdateRequest{}, Response: models.Secret{}},
+	{Method: http.MethodDelete, Path: "/api/secrets/:name", Tag: "secrets", Summary: "删除密钥", Query: struct {
+		Force bool `form:"force"` // 为true时即使被服务器引用也删除
+	}{}},
+	{Method: http.MethodGet, Path: "/api/secrets/:name/usage", Tag: "secrets", Summary: "查询密钥被引用的位置", Response: models.SecretUsage{}},
+
+	// 应用设置
+	{Method: http.MethodGet, Path: "/api/settings", Tag: "settings", Summary: "获取应用设置", Response: settingsData{}},
+	{Method: http.MethodPut, Path: "/api/settings", Tag: "settings", Summary: "更新应用设置，请求体只需包含需要修改的配置项", Body: config.Config{}, Response: settingsData{}},
+
+	// 安全
+	{Method: http.MethodPost, Path: "/api/security/rotate-key", Tag: "security", Summary: "轮换主密钥", Response: models.KeyRotationResult{}},
+}
+
+var (
+	apiDocOnce sync.Once
+	apiDoc     *openapi.Document
+)
+
+// handleOpenAPI 返回根据路由文档生成的 OpenAPI 文档
+func (a *App) handleOpenAPI(c *gin.Context) {
+	apiDocOnce.Do(func() {
+		apiDoc = openapi.Build(openapi.Info{
+			Title:       "Desktop AI Tools API",
+			Description: "MCP 服务器与工具管理接口，除健康检查外需要通过 Authorization: Bearer <token> 携带API令牌",
+			Version:     "1.0.0",
+		}, utils.APIResponse{}, apiOperations)
+	})
+	c.JSON(http.StatusOK, apiDoc)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"desktop-ai-tools/config"
+)
+
+// TestRoutesDocumented 测试所有注册的路由都有文档，且文档中没有已删除的路由
+func TestRoutesDocumented(t *testing.T) {
+	cfg, err := config.Load([]string{"-config", filepath.Join(t.TempDir(), "config.json"), "-mode=test"})
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	a := &App{config: cfg, apiToken: "test-token"}
+	a.setupRouter()
+
+	documented := map[string]bool{}
+	for _, op := range apiOperations {
+		if documented[op.Key()] {
+			t.Fatalf("路由文档重复: %s", op.Key())
+		}
+		documented[op.Key()] = true
+	}
+
+	registered := map[string]bool{}
+	for _, route := range a.router.Routes() {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		if !documented[key] {
+			t.Errorf("路由缺少文档，请在 apiOperations 中添加: %s", key)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			t.Errorf("文档中的路由未注册: %s", key)
+		}
+	}
+
+	// 接口文档无需令牌即可访问
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("获取接口文档失败: %d %s", w.Code, w.Body.String())
+	}
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("解析接口文档失败: %v", err)
+	}
+	if _, ok := doc.Paths["/api/mcp-servers/{id}"]["put"]; !ok {
+		t.Fatalf("接口文档缺少路径参数形式的路由: %v", doc.Paths)
+	}
+}
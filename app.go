@@ -3,13 +3,25 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/config"
 	"desktop-ai-tools/database"
+	"desktop-ai-tools/events"
+	"desktop-ai-tools/i18n"
+	"desktop-ai-tools/logging"
+	"desktop-ai-tools/metrics"
 	"desktop-ai-tools/middleware"
 	"desktop-ai-tools/models"
 	"desktop-ai-tools/services"
@@ -19,11 +31,40 @@ import (
 // App struct
 type App struct {
 	ctx              context.Context
+	config           *config.Manager
 	router           *gin.Engine
+	server           *http.Server
+	apiAddr          string
+	apiToken         string // 每次启动随机生成的API令牌，前端通过Wails绑定获取
 	mcpServerService *services.MCPServerService
 	mcpToolService   *services.MCPToolService
+	samplingService  *services.SamplingService
+	rootsService     *services.RootsService
+	elicitService    *services.ElicitationService
+	serverLogService *services.ServerLogService
+	trafficService   *services.TrafficService
+	clientFactory    *services.MCPClientFactory
+	importService    *services.ImportService
+	exportService    *services.ExportService
+	backupService    *services.BackupService
+	secretKeyService *services.SecretKeyService
+	secretService    *services.SecretService
+	tagService       *services.TagService
+	workspaceService *services.WorkspaceService
+	auditService     *services.AuditService
+	eventBus         *events.Bus
+	stopEvents       func()        // 停止向桌面界面推送事件
+	shuttingDown     chan struct{} // 应用退出时关闭，SSE等长连接据此结束
 }
 
+// shutdownTimeout 应用退出时等待请求和工具调用结束的最长时间
+const shutdownTimeout = 10 * time.Second
+
+var (
+	appLog = logging.For(logging.SubsystemApp)
+	apiLog = logging.For(logging.SubsystemAPI)
+)
+
 // HelloRequest 请求结构体
 type HelloRequest struct {
 	Message string `json:"message" binding:"required"`
@@ -32,55 +73,185 @@ type HelloRequest struct {
 // HelloResponse 响应结构体
 type HelloResponse struct {
 	Response string `json:"response"`
-	Success  bool   `json:"success"`
 }
 
 // NewApp creates a new App application struct
-func NewApp() *App {
-	app := &App{}
+func NewApp(cfg *config.Manager) *App {
+	app := &App{config: cfg, shuttingDown: make(chan struct{})}
+	settings := cfg.Get()
+
+	token, err := middleware.GenerateAPIToken()
+	if err != nil {
+		panic(err)
+	}
+	app.apiToken = token
+
+	initLogging(settings)
 
 	// 初始化数据库
-	if err := database.InitDatabase(); err != nil {
-		fmt.Printf("数据库初始化失败: %v\n", err)
+	if err := database.InitDatabase(&settings); err != nil {
+		appLog.Error("数据库初始化失败", "error", err)
 		panic(err)
 	}
 
 	// 初始化种子数据
 	if err := database.SeedData(); err != nil {
-		fmt.Printf("种子数据初始化失败: %v\n", err)
+		appLog.Error("种子数据初始化失败", "error", err)
+	}
+
+	// 加密升级前以明文保存的敏感字段
+	if count, err := services.NewSecretKeyService(database.GetDB()).EncryptPlaintext(); err != nil {
+		appLog.Error("加密敏感字段失败", "error", err)
+	} else if count > 0 {
+		appLog.Info("已加密明文保存的敏感字段", "records", count)
 	}
 
 	// 初始化服务
 	app.mcpServerService = services.NewMCPServerService()
-	app.mcpToolService = services.NewMCPToolService(database.GetDB())
+	app.samplingService = services.NewSamplingService(database.GetDB())
+	app.rootsService = services.NewRootsService(database.GetDB())
+	app.elicitService = services.NewElicitationService(database.GetDB())
+	app.serverLogService = services.NewServerLogService(database.GetDB())
+	app.secretService = services.NewSecretService(database.GetDB())
+	app.trafficService = services.NewTrafficService(0)
+	app.clientFactory = services.NewMCPClientFactory(app.samplingService, app.elicitService, app.rootsService, app.serverLogService, app.secretService, app.trafficService)
+	app.mcpToolService = services.NewMCPToolService(database.GetDB(), app.clientFactory)
+	app.importService = services.NewImportService(database.GetDB(), app.mcpServerService)
+	app.exportService = services.NewExportService(database.GetDB())
+	app.backupService = services.NewBackupService(database.GetDB(), "")
+	app.secretKeyService = services.NewSecretKeyService(database.GetDB())
+	app.tagService = services.NewTagService(database.GetDB())
+	app.workspaceService = services.NewWorkspaceService(database.GetDB())
+	app.auditService = services.NewAuditService(database.GetDB())
+
+	// 各服务的事件统一发布到事件总线，再推送给桌面界面和 /api/events 的订阅者
+	app.eventBus = events.NewBus()
+	app.mcpServerService.SetNotifier(app.eventBus.Publish)
+	app.mcpToolService.SetNotifier(app.eventBus.Publish)
+	app.clientFactory.SetNotifier(app.eventBus.Publish)
+	app.samplingService.SetNotifier(app.eventBus.Publish)
+	app.elicitService.SetNotifier(app.eventBus.Publish)
+	app.workspaceService.OnSwitch(func(workspace *models.Workspace) {
+		app.eventBus.Publish(events.TopicWorkspaceSwitched, workspace)
+	})
+
+	// 抓取指标时读取服务器状态和队列长度
+	metrics.SetStateSource(metrics.StateSource{
+		Servers: app.serverStates,
+		Queues:  app.queueDepths,
+	})
+
+	// 应用可在运行时修改的配置，并在配置变更时重新应用
+	app.applySettings(settings)
+	cfg.OnChange(app.applySettings)
 
 	app.setupRouter()
 	return app
 }
 
+// initLogging 按配置初始化应用日志，日志文件无法写入时只输出到控制台
+func initLogging(settings config.Config) {
+	dir, err := settings.LogDir()
+	if err == nil {
+		err = logging.Init(logging.Options{
+			Dir:        dir,
+			MaxSize:    int64(settings.Log.MaxSizeMB) << 20,
+			MaxFiles:   settings.Log.MaxFiles,
+			Level:      settings.Log.Level,
+			Subsystems: settings.Log.Subsystems,
+			Console:    os.Stdout,
+		})
+	}
+	if err != nil {
+		appLog.Error("初始化日志文件失败，日志只输出到控制台", "error", err)
+	}
+}
+
+// applySettings 应用无需重启即可生效的配置
+func (a *App) applySettings(settings config.Config) {
+	if err := logging.SetLevels(settings.Log.Level, settings.Log.Subsystems); err != nil {
+		appLog.Error("设置日志级别失败", "error", err)
+	}
+	database.SetLogLevel(settings.Database.LogLevel)
+	a.mcpToolService.SetCacheConfig(time.Duration(settings.MCP.ToolCacheTTL)*time.Second, settings.MCP.ToolCacheMaxEntries)
+	a.elicitService.SetTimeout(time.Duration(settings.MCP.ElicitationTimeout) * time.Second)
+}
+
+// serverStates 读取所有服务器的状态和已连接会话数，供指标输出
+func (a *App) serverStates() ([]metrics.ServerState, error) {
+	servers, err := a.mcpServerService.ListStates()
+	if err != nil {
+		return nil, err
+	}
+	states := make([]metrics.ServerState, 0, len(servers))
+	for _, server := range servers {
+		states = append(states, metrics.ServerState{
+			ID:       server.ID,
+			Name:     server.Name,
+			Status:   server.Status,
+			Enabled:  server.IsEnabled,
+			Sessions: len(a.clientFactory.Sessions(server.ID)),
+		})
+	}
+	return states, nil
+}
+
+// queueDepths 读取等待用户处理的请求数和正在执行的工具调用数，供指标输出
+func (a *App) queueDepths() map[string]int {
+	return map[string]int{
+		"sampling":    len(a.samplingService.GetPending()),
+		"elicitation": len(a.elicitService.GetPending()),
+		"tool_calls":  a.mcpToolService.ActiveCalls(),
+	}
+}
+
 // setupRouter 设置Gin路由
 func (a *App) setupRouter() {
-	// 设置Gin为开发模式（用于测试详细错误信息）
-	gin.SetMode(gin.DebugMode)
+	// 设置Gin运行模式（debug模式下输出详细错误信息）
+	gin.SetMode(a.config.Get().Server.Mode)
+	gin.DebugPrintFunc = func(format string, values ...any) {
+		apiLog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
 
-	a.router = gin.Default()
+	// 请求日志和panic恢复由下面的中间件处理，写入应用日志而不是控制台
+	a.router = gin.New()
 
 	// 添加错误处理中间件
+	a.router.Use(middleware.RequestID())
+	a.router.Use(middleware.Metrics())
+	a.router.Use(middleware.RequestLogger())
 	a.router.Use(middleware.ErrorHandler())
 	a.router.Use(middleware.LogErrors())
+	a.router.Use(middleware.AuditSource())
+	a.router.Use(middleware.Locale(func() string { return a.config.Get().UI.Language }))
+
+	// 配置CORS，允许的来源从配置中实时读取
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOriginFunc = a.allowOrigin
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.RequestSourceHeader, middleware.RequestIDHeader}
+	corsConfig.ExposeHeaders = []string{middleware.RequestIDHeader}
+	a.router.Use(cors.New(corsConfig))
+
+	// 除健康检查和接口文档外的接口都需要携带本次启动生成的API令牌，
+	// 设置允许时 /metrics 也无需令牌，便于 Prometheus 抓取
+	publicPaths := []string{"/api/health", "/api/openapi.json"}
+	if a.config.Get().Server.MetricsPublic {
+		publicPaths = append(publicPaths, "/metrics")
+	}
+	a.router.Use(middleware.APIToken(a.apiToken, publicPaths...))
 
-	// 配置CORS
-	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	a.router.Use(cors.New(config))
+	// Prometheus 格式的指标
+	a.router.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	// 设置API路由
 	api := a.router.Group("/api")
 	{
 		api.POST("/hello", a.handleHello)
 		api.GET("/health", a.handleHealth)
+		api.GET("/openapi.json", a.handleOpenAPI)
+		api.GET("/events", a.handleEvents)
+		api.GET("/logs", a.handleGetAppLogs)
 
 		// 测试错误处理的端点
 		api.GET("/test-error", a.handleTestError)
@@ -96,10 +267,30 @@ func (a *App) setupRouter() {
 			mcpServers.DELETE("/:id", a.handleDeleteMCPServer)
 			mcpServers.PUT("/:id/status", a.handleUpdateMCPServerStatus)
 			mcpServers.PUT("/:id/toggle", a.handleToggleMCPServer)
+			mcpServers.PUT("/:id/sampling-policy", a.handleUpdateSamplingPolicy)
+			mcpServers.PUT("/:id/log-level", a.handleUpdateServerLogLevel)
+			mcpServers.GET("/:id/logs", a.handleGetServerLogs)
+			mcpServers.DELETE("/:id/logs", a.handleClearServerLogs)
+			mcpServers.GET("/:id/traffic", a.handleGetTraffic)
+			mcpServers.PUT("/:id/traffic/capture", a.handleSetTrafficCapture)
+			mcpServers.GET("/:id/traffic/export", a.handleExportTraffic)
+			mcpServers.DELETE("/:id/traffic", a.handleClearTraffic)
 			mcpServers.GET("/tags", a.handleGetMCPServerTags)
 
 			// 工具发现路由
 			mcpServers.POST("/:id/discover-tools", a.handleDiscoverTools)
+
+			// 工具结果缓存路由
+			mcpServers.GET("/:id/cache", a.handleGetToolCache)
+			mcpServers.DELETE("/:id/cache", a.handlePurgeToolCache)
+
+			// 从客户端配置文件导入
+			mcpServers.GET("/import/sources", a.handleGetImportSources)
+			mcpServers.POST("/import/preview", a.handlePreviewImport)
+			mcpServers.POST("/import", a.handleImportMCPServers)
+
+			// 导出为客户端配置
+			mcpServers.POST("/export", a.handleExportMCPServers)
 		}
 
 		// MCP Tools 相关路由
@@ -110,6 +301,94 @@ func (a *App) setupRouter() {
 			mcpTools.PUT("/batch", a.handleBatchUpdateMCPTools)
 			mcpTools.GET("/categories", a.handleGetMCPToolCategories)
 			mcpTools.POST("/refresh/:serverID", a.handleRefreshTools)
+			mcpTools.POST("/:id/call", a.handleCallMCPTool)
+			mcpTools.PUT("/:id/tags", a.handleUpdateToolTags)
+		}
+
+		// 采样（sampling/createMessage）相关路由
+		sampling := api.Group("/sampling")
+		{
+			sampling.GET("/config", a.handleGetSamplingConfig)
+			sampling.PUT("/config", a.handleUpdateSamplingConfig)
+			sampling.GET("/logs", a.handleGetSamplingLogs)
+			sampling.GET("/pending", a.handleGetPendingSampling)
+			sampling.POST("/pending/:id", a.handleDecideSampling)
+		}
+
+		// 信息收集（elicitation）相关路由
+		elicitations := api.Group("/elicitations")
+		{
+			elicitations.GET("/pending", a.handleGetPendingElicitations)
+			elicitations.POST("/:id/respond", a.handleRespondElicitation)
+		}
+
+		// 工作区根目录（roots）相关路由
+		roots := api.Group("/roots")
+		{
+			roots.GET("", a.handleGetRoots)
+			roots.POST("", a.handleCreateRoot)
+			roots.PUT("/:id", a.handleUpdateRoot)
+			roots.DELETE("/:id", a.handleDeleteRoot)
+		}
+
+		// 备份与恢复相关路由
+		backups := api.Group("/backups")
+		{
+			backups.GET("", a.handleListBackups)
+			backups.POST("", a.handleCreateBackup)
+			backups.POST("/upload", a.handleUploadBackup)
+			backups.GET("/schedule", a.handleGetBackupSchedule)
+			backups.PUT("/schedule", a.handleUpdateBackupSchedule)
+			backups.GET("/:name/download", a.handleDownloadBackup)
+			backups.POST("/:name/restore", a.handleRestoreBackup)
+			backups.DELETE("/:name", a.handleDeleteBackup)
+		}
+
+		// 标签相关路由
+		tags := api.Group("/tags")
+		{
+			tags.GET("", a.handleGetTags)
+			tags.POST("/merge", a.handleMergeTags)
+			tags.PUT("/:id", a.handleRenameTag)
+			tags.DELETE("/:id", a.handleDeleteTag)
+		}
+
+		// 工作区相关路由
+		workspaces := api.Group("/workspaces")
+		{
+			workspaces.GET("", a.handleGetWorkspaces)
+			workspaces.POST("", a.handleCreateWorkspace)
+			workspaces.GET("/active", a.handleGetActiveWorkspace)
+			workspaces.PUT("/:id", a.handleUpdateWorkspace)
+			workspaces.DELETE("/:id", a.handleDeleteWorkspace)
+			workspaces.POST("/:id/activate", a.handleActivateWorkspace)
+			workspaces.POST("/:id/clone", a.handleCloneWorkspace)
+		}
+
+		// 审计日志相关路由
+		api.GET("/audit", a.handleGetAuditLogs)
+
+		// 命名密钥相关路由
+		secrets := api.Group("/secrets")
+		{
+			secrets.GET("", a.handleGetSecrets)
+			secrets.POST("", a.handleCreateSecret)
+			secrets.PUT("/:name", a.handleUpdateSecret)
+			secrets.DELETE("/:name", a.handleDeleteSecret)
+			secrets.GET("/:name/usage", a.handleGetSecretUsage)
+		}
+
+		// 应用设置相关路由
+		settings := api.Group("/settings")
+		{
+			settings.GET("", a.handleGetSettings)
+			settings.PUT("", a.handleUpdateSettings)
+		}
+
+		// 安全相关路由
+		sec := api.Group("/security")
+		{
+			sec.POST("/rotate-key", a.handleRotateSecretKey)
 		}
 	}
 }
@@ -119,40 +398,325 @@ func (a *App) setupRouter() {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
-	// 启动Gin服务器
+	// 事件总线中的事件通过Wails事件推送到前端，事件名即主题
+	eventCh, stopEvents := a.eventBus.Subscribe()
+	a.stopEvents = stopEvents
+	go func() {
+		for event := range eventCh {
+			runtime.EventsEmit(a.ctx, string(event.Topic), event.Data)
+		}
+	}()
+
+	// 启动定时自动备份
+	a.backupService.StartScheduler()
+
+	// 关闭HTTP API时不监听任何端口，桌面界面只通过Wails绑定访问
+	if !a.config.Get().Server.Enabled {
+		apiLog.Info("HTTP API服务已关闭，仅提供Wails绑定")
+		return
+	}
+
+	// 启动Gin服务器，先同步监听端口，确保前端加载时已能获取实际端口
+	listener, err := a.listen()
+	if err != nil {
+		apiLog.Error("API服务启动失败", "error", err)
+		return
+	}
+	a.apiAddr = listener.Addr().String()
+	a.server = &http.Server{Handler: a.router}
+	apiLog.Info("API服务已启动", "base_url", a.GetAPIBaseURL())
+	if !a.config.Get().Server.IsLoopback() {
+		apiLog.Warn("API服务监听在非本机地址，局域网内的其他设备可以访问", "addr", a.apiAddr)
+	}
+
 	go func() {
-		if err := a.router.Run(":8080"); err != nil {
-			fmt.Printf("Failed to start server: %v\n", err)
+		if err := a.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			apiLog.Error("API服务异常退出", "error", err)
 		}
 	}()
 }
 
+// beforeClose 关闭窗口前调用，仍有工具调用在运行时请用户确认，返回true时取消关闭
+func (a *App) beforeClose(ctx context.Context) bool {
+	running := a.mcpToolService.ActiveCalls()
+	if running == 0 {
+		return false
+	}
+
+	locale := a.uiLocale()
+	answer, err := runtime.MessageDialog(ctx, runtime.MessageDialogOptions{
+		Type:    runtime.QuestionDialog,
+		Title:   i18n.Message(locale, "shutdown_confirm_title"),
+		Message: i18n.Message(locale, "shutdown_calls_running", running),
+	})
+	if err != nil {
+		appLog.Error("显示退出确认对话框失败", "error", err)
+		return false
+	}
+	return answer != "Yes"
+}
+
+// shutdown 应用退出时调用，依次停止接收请求、等待进行中的请求和任务、关闭MCP会话和数据库
+func (a *App) shutdown(ctx context.Context) {
+	close(a.shuttingDown)
+	if a.stopEvents != nil {
+		a.stopEvents()
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// 停止监听并等待进行中的HTTP请求完成
+	if a.server != nil {
+		if err := a.server.Shutdown(drainCtx); err != nil {
+			apiLog.Warn("关闭API服务超时", "error", err)
+		}
+	}
+
+	// 等待通过Wails绑定发起的工具调用结束
+	if err := a.mcpToolService.WaitCalls(drainCtx); err != nil {
+		appLog.Warn("仍有工具调用未结束，强制退出", "active_calls", a.mcpToolService.ActiveCalls())
+	}
+
+	// 停止定时任务，等待正在执行的自动备份完成
+	a.backupService.StopScheduler()
+
+	// 关闭所有MCP会话，stdio服务器的子进程随之结束
+	a.clientFactory.CloseAll()
+
+	if err := database.CloseDatabase(); err != nil {
+		appLog.Error("关闭数据库失败", "error", err)
+	}
+	appLog.Info("应用已退出")
+	logging.Close()
+}
+
+// listen 监听配置的地址，端口被占用且允许回退时改用系统分配的空闲端口
+func (a *App) listen() (net.Listener, error) {
+	settings := a.config.Get().Server
+	addr := net.JoinHostPort(settings.Host, strconv.Itoa(settings.Port))
+
+	listener, err := net.Listen("tcp", addr)
+	if err == nil || !settings.PortFallback || settings.Port == 0 {
+		return listener, err
+	}
+
+	apiLog.Warn("端口不可用，改用空闲端口", "port", settings.Port, "error", err)
+	return net.Listen("tcp", net.JoinHostPort(settings.Host, "0"))
+}
+
+// allowOrigin 判断请求来源是否在跨域白名单中
+func (a *App) allowOrigin(origin string) bool {
+	for _, allowed := range a.config.Get().CORS.AllowOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	apiLog.Warn("拒绝跨域请求，来源不在白名单中", "origin", origin)
+	return false
+}
+
+// GetAPIBaseURL 获取内置API服务的实际地址，供前端确定请求地址，HTTP API关闭时返回空字符串
+func (a *App) GetAPIBaseURL() string {
+	if a.apiAddr == "" {
+		return ""
+	}
+	host, port, err := net.SplitHostPort(a.apiAddr)
+	if err != nil {
+		return ""
+	}
+	// 监听所有地址时前端通过本机回环地址访问
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port) + "/api"
+}
+
+// GetAPIToken 获取本次启动生成的API令牌，前端请求时通过 Authorization 请求头携带
+func (a *App) GetAPIToken() string {
+	return a.apiToken
+}
+
+// GetAPIPort 获取内置API服务实际监听的端口，服务未启动时返回0
+func (a *App) GetAPIPort() int {
+	_, port, err := net.SplitHostPort(a.apiAddr)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(port)
+	return n
+}
+
+// settingsResponse 设置接口的响应数据
+func (a *App) settingsResponse(restartRequired []string) gin.H {
+	view := a.config.View()
+	data := gin.H{
+		"config":       view.Config,
+		"file":         view.File,
+		"config_file":  view.ConfigFile,
+		"overridden":   view.Overridden,
+		"api_base_url": a.GetAPIBaseURL(),
+		"api_port":     a.GetAPIPort(),
+	}
+	if restartRequired != nil {
+		data["restart_required"] = restartRequired
+	}
+	return data
+}
+
+// handleGetSettings 获取应用设置
+func (a *App) handleGetSettings(c *gin.Context) {
+	utils.SuccessResponse(c, a.settingsResponse(nil), i18n.T(c, "settings_loaded"))
+}
+
+// handleUpdateSettings 更新应用设置，请求体为需要修改的配置项（与配置文件结构相同）
+func (a *App) handleUpdateSettings(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	restartRequired, err := a.config.Update(body)
+	if err != nil {
+		_ = c.Error(apperrors.Validation("invalid_settings", "更新应用设置失败").Wrap(err))
+		return
+	}
+
+	message := i18n.T(c, "settings_updated")
+	if len(restartRequired) > 0 {
+		message = i18n.T(c, "settings_updated_restart")
+	}
+	utils.SuccessResponse(c, a.settingsResponse(restartRequired), message)
+}
+
 // handleHello 处理Hello请求的API接口
 func (a *App) handleHello(c *gin.Context) {
 	var req HelloRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidRequest(err))
 		return
 	}
 
 	// 处理Hello逻辑
 	response := fmt.Sprintf("Hello from backend! You sent: %s", req.Message)
 
-	c.JSON(http.StatusOK, HelloResponse{
-		Response: response,
-		Success:  true,
-	})
+	utils.SuccessResponse(c, HelloResponse{Response: response})
 }
 
 // handleHealth 健康检查接口
 func (a *App) handleHealth(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "ok",
-		"message": "Server is running",
+	utils.SuccessResponse(c, gin.H{"status": "ok"}, i18n.T(c, "server_running"))
+}
+
+// handleEvents 通过SSE推送应用内事件，topics 参数按主题过滤
+func (a *App) handleEvents(c *gin.Context) {
+	var req models.EventStreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	var filters []string
+	for _, value := range req.Topics {
+		for _, filter := range strings.Split(value, ",") {
+			if filter = strings.TrimSpace(filter); filter == "" {
+				continue
+			}
+			if !events.ValidFilter(filter) {
+				_ = c.Error(apperrors.Newf(apperrors.KindValidation, "invalid_event_topic", "无效的事件主题: %s", filter))
+				return
+			}
+			filters = append(filters, filter)
+		}
+	}
+
+	eventCh, cancel := a.eventBus.Subscribe(filters...)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-eventCh:
+			c.SSEvent(string(event.Topic), event)
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().Format(time.RFC3339))
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		case <-a.shuttingDown:
+			return false
+		}
+	})
+}
+
+// handleGetAppLogs 查询应用日志，follow=true 时通过SSE持续推送新日志
+func (a *App) handleGetAppLogs(c *gin.Context) {
+	var req models.AppLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	if req.Follow || c.GetHeader("Accept") == "text/event-stream" {
+		a.streamAppLogs(c, &req)
+		return
+	}
+
+	response, err := a.queryAppLogs(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, response)
+}
+
+// queryAppLogs 从日志文件中查询应用日志
+func (a *App) queryAppLogs(req *models.AppLogListRequest) (*models.AppLogListResponse, error) {
+	logs, total, err := logging.Query(req.Filter(), req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AppLogListResponse{Total: total, Logs: logs, Subsystems: logging.Subsystems()}, nil
+}
+
+// streamAppLogs 通过SSE实时推送应用日志
+func (a *App) streamAppLogs(c *gin.Context, req *models.AppLogListRequest) {
+	logs, cancel := logging.Subscribe(req.Filter())
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case entry, ok := <-logs:
+			if !ok {
+				return false
+			}
+			c.SSEvent("log", entry)
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().Format(time.RFC3339))
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		case <-a.shuttingDown:
+			return false
+		}
 	})
 }
 
@@ -160,10 +724,7 @@ func (a *App) handleHealth(c *gin.Context) {
 func (a *App) handleGetMCPServers(c *gin.Context) {
 	var req models.MCPServerListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid query parameters",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidRequest(err))
 		return
 	}
 
@@ -183,44 +744,28 @@ func (a *App) handleGetMCPServers(c *gin.Context) {
 
 	result, err := a.mcpServerService.GetList(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   err.Error(),
-			"success": false,
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    result,
-	})
+	utils.SuccessResponse(c, result)
 }
 
 // handleCreateMCPServer 创建MCP服务器
 func (a *App) handleCreateMCPServer(c *gin.Context) {
 	var req models.MCPServerCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidRequest(err))
 		return
 	}
 
-	server, err := a.mcpServerService.Create(&req)
+	server, err := a.mcpServerService.WithContext(c.Request.Context()).Create(&req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   err.Error(),
-			"success": false,
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"data":    server,
-		"message": "MCP服务器创建成功",
-	})
+	utils.CreatedResponse(c, server, i18n.T(c, "server_created"))
 }
 
 // handleGetMCPServer 获取单个MCP服务器
@@ -228,26 +773,17 @@ func (a *App) handleGetMCPServer(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid server ID",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidID(idStr))
 		return
 	}
 
 	server, err := a.mcpServerService.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   err.Error(),
-			"success": false,
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    server,
-	})
+	utils.SuccessResponse(c, server)
 }
 
 // handleUpdateMCPServer 更新MCP服务器
@@ -255,36 +791,23 @@ func (a *App) handleUpdateMCPServer(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid server ID",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidID(idStr))
 		return
 	}
 
 	var req models.MCPServerUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidRequest(err))
 		return
 	}
 
-	server, err := a.mcpServerService.Update(uint(id), &req)
+	server, err := a.mcpServerService.WithContext(c.Request.Context()).Update(uint(id), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   err.Error(),
-			"success": false,
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    server,
-		"message": "MCP服务器更新成功",
-	})
+	utils.SuccessResponse(c, server, i18n.T(c, "server_updated"))
 }
 
 // handleDeleteMCPServer 删除MCP服务器
@@ -292,26 +815,17 @@ func (a *App) handleDeleteMCPServer(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid server ID",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidID(idStr))
 		return
 	}
 
-	err = a.mcpServerService.Delete(uint(id))
+	err = a.mcpServerService.WithContext(c.Request.Context()).Delete(uint(id))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   err.Error(),
-			"success": false,
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "MCP服务器删除成功",
-	})
+	utils.SuccessResponse(c, nil, i18n.T(c, "server_deleted"))
 }
 
 // handleUpdateMCPServerStatus 更新MCP服务器状态
@@ -319,37 +833,23 @@ func (a *App) handleUpdateMCPServerStatus(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid server ID",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidID(idStr))
 		return
 	}
 
-	var req struct {
-		Status string `json:"status" binding:"required,oneof=active inactive error"`
-	}
+	var req models.MCPServerStatusUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidRequest(err))
 		return
 	}
 
-	err = a.mcpServerService.UpdateStatus(uint(id), req.Status)
+	err = a.mcpServerService.WithContext(c.Request.Context()).UpdateStatus(uint(id), req.Status)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   err.Error(),
-			"success": false,
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "服务器状态更新成功",
-	})
+	utils.SuccessResponse(c, nil, i18n.T(c, "server_status_updated"))
 }
 
 // handleToggleMCPServer 切换MCP服务器启用状态
@@ -357,44 +857,84 @@ func (a *App) handleToggleMCPServer(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid server ID",
-			"success": false,
-		})
+		_ = c.Error(apperrors.InvalidID(idStr))
 		return
 	}
 
-	server, err := a.mcpServerService.ToggleEnabled(uint(id))
+	server, err := a.mcpServerService.WithContext(c.Request.Context()).ToggleEnabled(uint(id))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   err.Error(),
-			"success": false,
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    server,
-		"message": "服务器状态切换成功",
-	})
+	utils.SuccessResponse(c, server, i18n.T(c, "server_toggled"))
 }
 
 // handleGetMCPServerTags 获取所有标签
 func (a *App) handleGetMCPServerTags(c *gin.Context) {
 	tags, err := a.mcpServerService.GetTags()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   err.Error(),
-			"success": false,
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    tags,
-	})
+	utils.SuccessResponse(c, tags)
+}
+
+// handleGetImportSources 获取本机检测到的客户端配置文件
+func (a *App) handleGetImportSources(c *gin.Context) {
+	utils.SuccessResponse(c, a.importService.DetectSources())
+}
+
+// handlePreviewImport 预览从客户端配置文件导入的结果
+func (a *App) handlePreviewImport(c *gin.Context) {
+	var req models.MCPServerImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	result, err := a.importService.Preview(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, result)
+}
+
+// handleImportMCPServers 从客户端配置文件导入MCP服务器
+func (a *App) handleImportMCPServers(c *gin.Context) {
+	var req models.MCPServerImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	result, err := a.importService.Import(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, i18n.T(c, "import_completed", result.Created, result.Overwritten, result.Skipped, result.Failed))
+}
+
+// handleExportMCPServers 将MCP服务器导出为客户端配置
+func (a *App) handleExportMCPServers(c *gin.Context) {
+	var req models.MCPServerExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	result, err := a.exportService.Export(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, result)
 }
 
 // Greet returns a greeting for the given name
@@ -402,60 +942,72 @@ func (a *App) Greet(name string) string {
 	return fmt.Sprintf("Hello %s, It's show time!", name)
 }
 
+// ListBackups 列出所有备份（Wails绑定方法）
+func (a *App) ListBackups() ([]models.BackupInfo, error) {
+	return a.backupService.List()
+}
+
+// CreateBackup 创建备份（Wails绑定方法）
+func (a *App) CreateBackup(req models.BackupCreateRequest) (*models.BackupInfo, error) {
+	return a.backupService.Create(&req)
+}
+
+// RestoreBackup 从备份恢复数据（Wails绑定方法）
+func (a *App) RestoreBackup(name string, req models.BackupRestoreRequest) (*models.BackupRestoreResult, error) {
+	return a.backupService.Restore(name, &req)
+}
+
+// DeleteBackup 删除备份（Wails绑定方法）
+func (a *App) DeleteBackup(name string) error {
+	return a.backupService.Delete(name)
+}
+
+// GetBackupSchedule 获取自动备份设置（Wails绑定方法）
+func (a *App) GetBackupSchedule() (*models.BackupSchedule, error) {
+	return a.backupService.GetSchedule()
+}
+
+// UpdateBackupSchedule 更新自动备份设置（Wails绑定方法）
+func (a *App) UpdateBackupSchedule(req models.BackupScheduleUpdateRequest) (*models.BackupSchedule, error) {
+	if req.IntervalHours < 1 || req.Keep < 1 {
+		return nil, fmt.Errorf("备份间隔和保留数量必须大于0")
+	}
+	return a.backupService.UpdateSchedule(&req)
+}
+
 // handleDiscoverTools 处理工具发现请求
 func (a *App) handleDiscoverTools(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "无效的服务器ID",
-		})
+		_ = c.Error(apperrors.InvalidID(idStr))
 		return
 	}
 
-	response, err := a.mcpToolService.DiscoverTools(uint(id))
+	response, err := a.mcpToolService.WithContext(c.Request.Context()).DiscoverTools(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": fmt.Sprintf("工具发现失败: %v", err),
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	if response.Success {
-		c.JSON(http.StatusOK, response)
-	} else {
-		c.JSON(http.StatusBadRequest, response)
-	}
+	utils.SuccessResponse(c, gin.H{"tools": response.Tools, "tools_count": len(response.Tools)}, i18n.T(c, "tools_discovered", len(response.Tools)))
 }
 
 // handleGetMCPTools 处理获取工具列表请求
 func (a *App) handleGetMCPTools(c *gin.Context) {
 	var req models.MCPToolListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "请求参数错误",
-			"error":   err.Error(),
-		})
+		_ = c.Error(apperrors.InvalidRequest(err))
 		return
 	}
 
 	response, err := a.mcpToolService.GetToolsByServer(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "获取工具列表失败",
-			"error":   err.Error(),
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    response,
-	})
+	utils.SuccessResponse(c, response)
 }
 
 // handleUpdateMCPTool 处理更新工具请求
@@ -463,63 +1015,38 @@ func (a *App) handleUpdateMCPTool(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "无效的工具ID",
-		})
+		_ = c.Error(apperrors.InvalidID(idStr))
 		return
 	}
 
 	var req models.MCPToolUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "请求参数错误",
-			"error":   err.Error(),
-		})
+		_ = c.Error(apperrors.InvalidRequest(err))
 		return
 	}
 
-	if err := a.mcpToolService.UpdateTool(uint(id), &req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "更新工具失败",
-			"error":   err.Error(),
-		})
+	if err := a.mcpToolService.WithContext(c.Request.Context()).UpdateTool(uint(id), &req); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "工具更新成功",
-	})
+	utils.SuccessResponse(c, nil, i18n.T(c, "tool_updated"))
 }
 
 // handleBatchUpdateMCPTools 处理批量更新工具请求
 func (a *App) handleBatchUpdateMCPTools(c *gin.Context) {
 	var req models.MCPToolBatchUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "请求参数错误",
-			"error":   err.Error(),
-		})
+		_ = c.Error(apperrors.InvalidRequest(err))
 		return
 	}
 
-	if err := a.mcpToolService.BatchUpdateTools(&req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "批量更新工具失败",
-			"error":   err.Error(),
-		})
+	if err := a.mcpToolService.WithContext(c.Request.Context()).BatchUpdateTools(&req); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": fmt.Sprintf("成功更新 %d 个工具", len(req.ToolIDs)),
-	})
+	utils.SuccessResponse(c, nil, i18n.T(c, "tools_updated", len(req.ToolIDs)))
 }
 
 // handleGetMCPToolCategories 处理获取工具分类请求
@@ -529,10 +1056,7 @@ func (a *App) handleGetMCPToolCategories(c *gin.Context) {
 	if serverIDStr != "" {
 		id, err := strconv.ParseUint(serverIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"message": "无效的服务器ID",
-			})
+			_ = c.Error(apperrors.InvalidID(serverIDStr))
 			return
 		}
 		serverID = uint(id)
@@ -540,18 +1064,11 @@ func (a *App) handleGetMCPToolCategories(c *gin.Context) {
 
 	categories, err := a.mcpToolService.GetToolCategories(serverID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "获取工具分类失败",
-			"error":   err.Error(),
-		})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    categories,
-	})
+	utils.SuccessResponse(c, categories)
 }
 
 // handleRefreshTools 处理刷新指定服务器的工具列表
@@ -559,22 +1076,914 @@ func (a *App) handleRefreshTools(c *gin.Context) {
 	serverIDStr := c.Param("serverID")
 	serverID, err := strconv.ParseUint(serverIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "无效的服务器ID",
-		})
+		_ = c.Error(apperrors.InvalidID(serverIDStr))
 		return
 	}
-	fmt.Printf("刷新服务器 %d 的工具列表\n", uint(serverID))
-	response, err := a.mcpToolService.RefreshAllTools(uint(serverID))
+	response, err := a.mcpToolService.WithContext(c.Request.Context()).RefreshAllTools(uint(serverID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "刷新工具失败",
-			"details": err.Error(),
-		})
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"tools": response.Tools, "tools_count": len(response.Tools)}, i18n.T(c, "tools_refreshed", len(response.Tools)))
+}
+
+// handleCallMCPTool 处理工具调用请求
+func (a *App) handleCallMCPTool(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	var req models.MCPToolCallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	response, err := a.mcpToolService.WithContext(c.Request.Context()).CallTool(uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, response)
+}
+
+// handleGetToolCache 获取服务器的工具结果缓存
+func (a *App) handleGetToolCache(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	utils.SuccessResponse(c, a.mcpToolService.GetCacheStats(uint(id)))
+}
+
+// handlePurgeToolCache 清除服务器的工具结果缓存，可通过 tool 参数指定单个工具
+func (a *App) handlePurgeToolCache(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	removed := a.mcpToolService.PurgeCache(uint(id), c.Query("tool"))
+
+	utils.SuccessResponse(c, gin.H{"removed": removed}, i18n.T(c, "cache_purged", removed))
+}
+
+// handleUpdateSamplingPolicy 更新服务器的采样策略
+func (a *App) handleUpdateSamplingPolicy(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	var req models.SamplingPolicyUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	if err := a.mcpServerService.WithContext(c.Request.Context()).UpdateSamplingPolicy(uint(id), req.Policy); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "sampling_policy_updated"))
+}
+
+// handleUpdateServerLogLevel 更新服务器的日志级别，并应用到已连接的会话
+func (a *App) handleUpdateServerLogLevel(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	var req models.MCPServerLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	if err := a.mcpServerService.WithContext(c.Request.Context()).UpdateLogLevel(uint(id), req.Level); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if req.Level != "" {
+		a.clientFactory.SetLogLevel(uint(id), req.Level)
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "log_level_updated"))
+}
+
+// handleGetServerLogs 查询服务器日志，follow=true 时通过SSE持续推送新日志
+func (a *App) handleGetServerLogs(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	var req models.MCPServerLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	if req.Follow || c.GetHeader("Accept") == "text/event-stream" {
+		a.streamServerLogs(c, uint(id), &req)
+		return
+	}
+
+	response, err := a.serverLogService.GetLogs(uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, response)
+}
+
+// streamServerLogs 通过SSE实时推送服务器日志
+func (a *App) streamServerLogs(c *gin.Context, serverID uint, req *models.MCPServerLogListRequest) {
+	logs, cancel := a.serverLogService.Subscribe(serverID, req.Level, req.Logger)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case entry := <-logs:
+			if req.Search != "" && !strings.Contains(entry.Data, req.Search) {
+				return true
+			}
+			c.SSEvent("log", entry)
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().Format(time.RFC3339))
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		case <-a.shuttingDown:
+			return false
+		}
+	})
+}
+
+// handleClearServerLogs 清空服务器日志
+func (a *App) handleClearServerLogs(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	if err := a.serverLogService.Clear(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "server_logs_cleared"))
+}
+
+// handleGetTraffic 查询抓取的JSON-RPC流量，follow=true 时通过SSE持续推送新记录
+func (a *App) handleGetTraffic(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	var req models.MCPTrafficListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	if req.Follow || c.GetHeader("Accept") == "text/event-stream" {
+		a.streamTraffic(c, uint(id), &req)
+		return
+	}
+
+	utils.SuccessResponse(c, a.trafficService.List(uint(id), &req))
+}
+
+// streamTraffic 通过SSE实时推送抓取的流量
+func (a *App) streamTraffic(c *gin.Context, serverID uint, req *models.MCPTrafficListRequest) {
+	entries, cancel := a.trafficService.Subscribe(serverID, *req)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case entry := <-entries:
+			c.SSEvent("traffic", entry)
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", time.Now().Format(time.RFC3339))
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		case <-a.shuttingDown:
+			return false
+		}
+	})
+}
+
+// handleSetTrafficCapture 开启或关闭服务器的JSON-RPC流量抓取
+func (a *App) handleSetTrafficCapture(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	var req models.MCPTrafficCaptureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	status := a.trafficService.SetCapture(uint(id), req.Enabled)
+	key := "traffic_capture_disabled"
+	if req.Enabled {
+		key = "traffic_capture_enabled"
+	}
+	utils.SuccessResponse(c, status, i18n.T(c, key))
+}
+
+// handleExportTraffic 以JSONL格式下载抓取的流量，每行一条记录
+func (a *App) handleExportTraffic(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	var req models.MCPTrafficListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	filename := fmt.Sprintf("mcp-traffic-%d-%s.jsonl", id, time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := a.trafficService.Export(uint(id), &req, c.Writer); err != nil {
+		apiLog.Warn("导出流量记录失败", "server_id", id, "error", err)
+	}
+}
+
+// handleClearTraffic 清空服务器抓取的流量
+func (a *App) handleClearTraffic(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	removed := a.trafficService.Clear(uint(id))
+
+	utils.SuccessResponse(c, gin.H{"removed": removed}, i18n.T(c, "traffic_cleared", removed))
+}
+
+// handleGetSamplingConfig 获取采样配置
+func (a *App) handleGetSamplingConfig(c *gin.Context) {
+	config, err := a.samplingService.GetConfig()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	config.Redact()
+
+	utils.SuccessResponse(c, config)
+}
+
+// handleUpdateSamplingConfig 更新采样配置
+func (a *App) handleUpdateSamplingConfig(c *gin.Context) {
+	var req models.SamplingConfigUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	config, err := a.samplingService.UpdateConfig(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	config.Redact()
+
+	utils.SuccessResponse(c, config, i18n.T(c, "sampling_config_updated"))
+}
+
+// handleGetSamplingLogs 获取采样日志
+func (a *App) handleGetSamplingLogs(c *gin.Context) {
+	var req models.SamplingLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	response, err := a.samplingService.GetLogs(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, response)
+}
+
+// handleGetPendingSampling 获取等待确认的采样请求
+func (a *App) handleGetPendingSampling(c *gin.Context) {
+	utils.SuccessResponse(c, a.samplingService.GetPending())
+}
+
+// handleDecideSampling 提交用户对采样请求的确认结果
+func (a *App) handleDecideSampling(c *gin.Context) {
+	var req models.SamplingDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	if err := a.samplingService.Decide(c.Param("id"), req.Approve); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "sampling_decision_submitted"))
+}
+
+// handleGetPendingElicitations 获取等待用户响应的信息收集请求
+func (a *App) handleGetPendingElicitations(c *gin.Context) {
+	utils.SuccessResponse(c, a.elicitService.GetPending())
+}
+
+// handleRespondElicitation 提交用户对信息收集请求的响应
+func (a *App) handleRespondElicitation(c *gin.Context) {
+	var req models.ElicitationRespondRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	if err := a.elicitService.Respond(c.Param("id"), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "elicitation_response_submitted"))
+}
+
+// handleGetRoots 获取工作区根目录列表
+func (a *App) handleGetRoots(c *gin.Context) {
+	var req models.WorkspaceRootListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	roots, err := a.rootsService.GetList(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, roots, i18n.T(c, "roots_transport_notice"))
+}
+
+// handleCreateRoot 创建工作区根目录
+func (a *App) handleCreateRoot(c *gin.Context) {
+	var req models.WorkspaceRootCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	root, err := a.rootsService.Create(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.CreatedResponse(c, root, i18n.T(c, "root_created"))
+}
+
+// handleUpdateRoot 更新工作区根目录
+func (a *App) handleUpdateRoot(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	var req models.WorkspaceRootUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	root, err := a.rootsService.Update(uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, root, i18n.T(c, "root_updated"))
+}
+
+// handleDeleteRoot 删除工作区根目录
+func (a *App) handleDeleteRoot(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(idStr))
+		return
+	}
+
+	if err := a.rootsService.Delete(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "root_deleted"))
+}
+
+// handleListBackups 列出所有备份
+func (a *App) handleListBackups(c *gin.Context) {
+	backups, err := a.backupService.List()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, backups)
+}
+
+// handleCreateBackup 创建备份
+func (a *App) handleCreateBackup(c *gin.Context) {
+	var req models.BackupCreateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(apperrors.InvalidRequest(err))
+			return
+		}
+	}
+
+	backup, err := a.backupService.Create(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.CreatedResponse(c, backup, i18n.T(c, "backup_created"))
+}
+
+// handleUploadBackup 上传备份文件
+func (a *App) handleUploadBackup(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		_ = c.Error(apperrors.Validation("backup_file_required", "请上传备份文件").Wrap(err))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+	defer file.Close()
+
+	backup, err := a.backupService.Upload(fileHeader.Filename, file)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.CreatedResponse(c, backup, i18n.T(c, "backup_uploaded"))
+}
+
+// handleDownloadBackup 下载备份文件
+func (a *App) handleDownloadBackup(c *gin.Context) {
+	path, err := a.backupService.Path(c.Param("name"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.FileAttachment(path, c.Param("name"))
+}
+
+// handleRestoreBackup 从备份恢复数据
+func (a *App) handleRestoreBackup(c *gin.Context) {
+	var req models.BackupRestoreRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(apperrors.InvalidRequest(err))
+			return
+		}
+	}
+
+	result, err := a.backupService.Restore(c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, i18n.T(c, "backup_restored"))
+}
+
+// handleDeleteBackup 删除备份
+func (a *App) handleDeleteBackup(c *gin.Context) {
+	if err := a.backupService.Delete(c.Param("name")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "backup_deleted"))
+}
+
+// handleGetBackupSchedule 获取自动备份设置
+func (a *App) handleGetBackupSchedule(c *gin.Context) {
+	schedule, err := a.backupService.GetSchedule()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, schedule)
+}
+
+// handleUpdateBackupSchedule 更新自动备份设置
+func (a *App) handleUpdateBackupSchedule(c *gin.Context) {
+	var req models.BackupScheduleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	schedule, err := a.backupService.UpdateSchedule(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, schedule, i18n.T(c, "backup_schedule_updated"))
+}
+
+// handleGetSecrets 获取密钥列表
+func (a *App) handleGetSecrets(c *gin.Context) {
+	secrets, err := a.secretService.List()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, secrets)
+}
+
+// handleCreateSecret 创建密钥
+func (a *App) handleCreateSecret(c *gin.Context) {
+	var req models.SecretCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	secret, err := a.secretService.Create(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.CreatedResponse(c, secret, i18n.T(c, "secret_created"))
+}
+
+// handleUpdateSecret 更新密钥
+func (a *App) handleUpdateSecret(c *gin.Context) {
+	var req models.SecretUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	secret, err := a.secretService.Update(c.Param("name"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, secret, i18n.T(c, "secret_updated"))
+}
+
+// handleDeleteSecret 删除密钥，仍被引用时需要 force=true
+func (a *App) handleDeleteSecret(c *gin.Context) {
+	force := c.Query("force") == "true"
+	if err := a.secretService.Delete(c.Param("name"), force); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "secret_deleted"))
+}
+
+// handleGetSecretUsage 获取引用指定密钥的服务器
+func (a *App) handleGetSecretUsage(c *gin.Context) {
+	usage, err := a.secretService.Usage(c.Param("name"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, usage)
+}
+
+// handleRotateSecretKey 轮换敏感字段的主密钥
+func (a *App) handleRotateSecretKey(c *gin.Context) {
+	result, err := a.secretKeyService.Rotate()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, result, i18n.T(c, "master_key_rotated"))
+}
+
+// handleGetTags 获取所有标签及使用数量
+func (a *App) handleGetTags(c *gin.Context) {
+	var req models.TagListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	tags, err := a.tagService.List(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, tags)
+}
+
+// handleRenameTag 重命名标签
+func (a *App) handleRenameTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(c.Param("id")))
+		return
+	}
+
+	var req models.TagRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	tag, err := a.tagService.WithContext(c.Request.Context()).Rename(uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, tag, i18n.T(c, "tag_renamed"))
+}
+
+// handleMergeTags 合并标签
+func (a *App) handleMergeTags(c *gin.Context) {
+	var req models.TagMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	tag, err := a.tagService.WithContext(c.Request.Context()).Merge(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, tag, i18n.T(c, "tag_merged"))
+}
+
+// handleDeleteTag 删除标签
+func (a *App) handleDeleteTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(c.Param("id")))
+		return
+	}
+
+	if err := a.tagService.WithContext(c.Request.Context()).Delete(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "tag_deleted"))
+}
+
+// handleUpdateToolTags 设置工具的标签
+func (a *App) handleUpdateToolTags(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(c.Param("id")))
+		return
+	}
+
+	var req models.TagAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	tool, err := a.tagService.WithContext(c.Request.Context()).SetToolTags(uint(id), req.Tags)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, tool, i18n.T(c, "tool_tags_updated"))
+}
+
+// ListWorkspaces 获取所有工作区（Wails绑定）
+func (a *App) ListWorkspaces() ([]models.Workspace, error) {
+	return a.workspaceService.List()
+}
+
+// GetActiveWorkspace 获取当前激活的工作区（Wails绑定）
+func (a *App) GetActiveWorkspace() (*models.Workspace, error) {
+	return a.workspaceService.Active()
+}
+
+// SwitchWorkspace 切换当前激活的工作区（Wails绑定）
+func (a *App) SwitchWorkspace(id uint) (*models.Workspace, error) {
+	return a.workspaceService.Switch(id)
+}
+
+// handleGetWorkspaces 获取工作区列表
+func (a *App) handleGetWorkspaces(c *gin.Context) {
+	workspaces, err := a.workspaceService.List()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, workspaces)
+}
+
+// handleGetActiveWorkspace 获取当前激活的工作区
+func (a *App) handleGetActiveWorkspace(c *gin.Context) {
+	workspace, err := a.workspaceService.Active()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, workspace)
+}
+
+// handleCreateWorkspace 创建工作区
+func (a *App) handleCreateWorkspace(c *gin.Context) {
+	var req models.WorkspaceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	workspace, err := a.workspaceService.Create(&req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.CreatedResponse(c, workspace, i18n.T(c, "workspace_created"))
+}
+
+// handleUpdateWorkspace 更新工作区
+func (a *App) handleUpdateWorkspace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(c.Param("id")))
+		return
+	}
+
+	var req models.WorkspaceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	workspace, err := a.workspaceService.Update(uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, workspace, i18n.T(c, "workspace_updated"))
+}
+
+// handleDeleteWorkspace 删除工作区
+func (a *App) handleDeleteWorkspace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(c.Param("id")))
+		return
+	}
+
+	if err := a.workspaceService.Delete(uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, nil, i18n.T(c, "workspace_deleted"))
+}
+
+// handleActivateWorkspace 切换当前激活的工作区
+func (a *App) handleActivateWorkspace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(c.Param("id")))
+		return
+	}
+
+	workspace, err := a.workspaceService.Switch(uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, workspace, i18n.T(c, "workspace_switched"))
+}
+
+// handleCloneWorkspace 复制工作区
+func (a *App) handleCloneWorkspace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(apperrors.InvalidID(c.Param("id")))
+		return
+	}
+
+	var req models.WorkspaceCloneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	workspace, err := a.workspaceService.Clone(uint(id), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.CreatedResponse(c, workspace, i18n.T(c, "workspace_cloned"))
+}
+
+// handleGetAuditLogs 查询配置变更审计日志
+func (a *App) handleGetAuditLogs(c *gin.Context) {
+	var req models.AuditLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(apperrors.InvalidRequest(err))
+		return
+	}
+
+	result, err := a.auditService.List(&req)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	utils.SuccessResponse(c, result)
 }
 
 // handleTestError 测试错误处理的端点
@@ -583,11 +1992,11 @@ func (a *App) handleTestError(c *gin.Context) {
 
 	switch errorType {
 	case "400":
-		utils.BadRequestResponse(c, fmt.Errorf("这是一个400错误测试"), "请求参数错误测试")
+		_ = c.Error(apperrors.Validation("test_error", "请求参数错误测试"))
 	case "404":
-		utils.NotFoundResponse(c, fmt.Errorf("这是一个404错误测试"), "资源未找到测试")
+		_ = c.Error(apperrors.NotFound("test_error", "资源未找到测试"))
 	case "500":
-		utils.InternalServerErrorResponse(c, fmt.Errorf("这是一个500错误测试"), "服务器内部错误测试")
+		_ = c.Error(apperrors.Internal("服务器内部错误测试", fmt.Errorf("这是一个500错误测试")))
 	default:
 		utils.SuccessResponse(c, gin.H{
 			"message": "错误测试端点正常工作",
@@ -0,0 +1,163 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// Kind 错误类别，决定HTTP状态码
+type Kind string
+
+// 错误类别
+const (
+	KindValidation   Kind = "validation"   // 请求参数或业务校验失败
+	KindNotFound     Kind = "not_found"    // 资源不存在
+	KindConflict     Kind = "conflict"     // 与现有数据冲突，如名称重复
+	KindUpstream     Kind = "upstream"     // MCP服务器、LLM接口等外部服务调用失败
+	KindTimeout      Kind = "timeout"      // 外部服务或用户确认超时
+	KindUnauthorized Kind = "unauthorized" // 缺少或无效的凭证
+	KindInternal     Kind = "internal"     // 未分类的内部错误
+)
+
+// 通用错误码，业务相关的错误码在各服务中定义
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeInvalidID      = "invalid_id"
+	CodeNotFound       = "not_found"
+	CodeTimeout        = "timeout"
+	CodeUnauthorized   = "unauthorized"
+	CodeInternal       = "internal_error"
+)
+
+// Error 带类别和错误码的业务错误
+// Code 是稳定的机器可读标识，供前端和外部调用方判断错误，同时作为消息目录的键；
+// Message 是默认语言（简体中文）的描述，Args 是消息模板的参数，用于按请求语言重新生成消息
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Args    []interface{}
+	Err     error
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap 返回底层错误
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 按类别和错误码判断是否为同一错误，便于使用 errors.Is 比较预定义错误
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Wrap 返回附带底层错误的副本，用于给预定义错误补充原因
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// HTTPStatus 返回错误类别对应的HTTP状态码
+func (e *Error) HTTPStatus() int {
+	switch e.Kind {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUpstream:
+		return http.StatusBadGateway
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Newf 按消息模板创建业务错误，模板参数会保留用于翻译
+func Newf(kind Kind, code, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...), Args: args}
+}
+
+// NotFound 创建资源不存在错误
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict 创建数据冲突错误
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Validation 创建校验失败错误
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// Upstream 创建外部服务调用失败错误，底层错误为超时时归类为超时错误
+func Upstream(code, message string, err error) *Error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Error{Kind: KindTimeout, Code: CodeTimeout, Message: message, Err: err}
+	}
+	return &Error{Kind: KindUpstream, Code: code, Message: message, Err: err}
+}
+
+// Timeout 创建超时错误
+func Timeout(code, message string) *Error {
+	return &Error{Kind: KindTimeout, Code: code, Message: message}
+}
+
+// Unauthorized 创建未授权错误
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Internal 创建内部错误
+func Internal(message string, err error) *Error {
+	return &Error{Kind: KindInternal, Code: CodeInternal, Message: message, Err: err}
+}
+
+// InvalidRequest 将请求绑定失败转换为校验错误
+func InvalidRequest(err error) *Error {
+	return &Error{Kind: KindValidation, Code: CodeInvalidRequest, Message: "请求参数错误", Err: err}
+}
+
+// InvalidID 创建路径或查询参数中ID无效的错误
+func InvalidID(value string) *Error {
+	return Newf(KindValidation, CodeInvalidID, "无效的ID: %s", value)
+}
+
+// From 将任意错误转换为业务错误
+// 已包装的业务错误原样返回；未找到记录和超时会被识别，其余归为内部错误
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return &Error{Kind: KindNotFound, Code: CodeNotFound, Message: "资源不存在", Err: err}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Kind: KindTimeout, Code: CodeTimeout, Message: "操作超时", Err: err}
+	}
+	return Internal("服务器内部错误", err)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/gin-gonic/gin/binding"
+
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/i18n"
+	"desktop-ai-tools/middleware"
+	"desktop-ai-tools/models"
+)
+
+// 以下方法通过Wails绑定暴露给前端，与HTTP接口调用同一套服务层代码
+// 关闭HTTP API后桌面界面仍可通过这些方法管理服务器和工具
+
+// bindingError Wails绑定方法返回给前端的错误，与HTTP接口错误响应中的 code、message 一致
+type bindingError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// formatBindingError 将绑定方法返回的错误转换为带错误码的本地化消息，通过 options.App.ErrorFormatter 注册
+func (a *App) formatBindingError(err error) any {
+	appErr := apperrors.From(err)
+	return bindingError{Code: appErr.Code, Message: middleware.LocalizedMessage(a.uiLocale(), appErr)}
+}
+
+// uiLocale 返回桌面界面设置的语言，未设置或不支持时使用默认语言
+func (a *App) uiLocale() i18n.Locale {
+	locale, ok := i18n.Parse(a.config.Get().UI.Language)
+	if !ok {
+		return i18n.DefaultLocale
+	}
+	return locale
+}
+
+// uiContext 返回绑定方法使用的上下文，审计日志中记录为桌面界面操作，
+// 每次调用生成新的请求ID，用于关联MCP流量记录
+func (a *App) uiContext() context.Context {
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = models.WithRequestID(ctx, middleware.NewRequestID())
+	return models.WithAuditSource(ctx, models.AuditSourceUI)
+}
+
+// validateRequest 按 binding 标签校验请求，未填写的字段先使用 form 标签中的默认值
+func validateRequest(req interface{}) error {
+	applyFormDefaults(req)
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return apperrors.InvalidRequest(err)
+	}
+	return nil
+}
+
+// applyFormDefaults 为零值字段填入 form 标签中的 default 选项，与HTTP查询参数绑定的行为一致
+func applyFormDefaults(req interface{}) {
+	value := reflect.ValueOf(req).Elem()
+	defaults := reflect.New(value.Type())
+	if err := binding.MapFormWithTag(defaults.Interface(), map[string][]string{}, "form"); err != nil {
+		return
+	}
+	for i := 0; i < value.NumField(); i++ {
+		if field := value.Field(i); field.CanSet() && field.IsZero() {
+			field.Set(defaults.Elem().Field(i))
+		}
+	}
+}
+
+// ListServers 查询服务器列表（Wails绑定）
+func (a *App) ListServers(req models.MCPServerListRequest) (*models.MCPServerListResponse, error) {
+	if err := validateRequest(&req); err != nil {
+		return nil, err
+	}
+	return a.mcpServerService.GetList(&req)
+}
+
+// GetServer 获取服务器详情（Wails绑定）
+func (a *App) GetServer(id uint) (*models.MCPServer, error) {
+	return a.mcpServerService.GetByID(id)
+}
+
+// CreateServer 创建服务器（Wails绑定）
+func (a *App) CreateServer(req models.MCPServerCreateRequest) (*models.MCPServer, error) {
+	if err := validateRequest(&req); err != nil {
+		return nil, err
+	}
+	return a.mcpServerService.WithContext(a.uiContext()).Create(&req)
+}
+
+// UpdateServer 更新服务器（Wails绑定）
+func (a *App) UpdateServer(id uint, req models.MCPServerUpdateRequest) (*models.MCPServer, error) {
+	if err := validateRequest(&req); err != nil {
+		return nil, err
+	}
+	return a.mcpServerService.WithContext(a.uiContext()).Update(id, &req)
+}
+
+// DeleteServer 删除服务器（Wails绑定）
+func (a *App) DeleteServer(id uint) error {
+	return a.mcpServerService.WithContext(a.uiContext()).Delete(id)
+}
+
+// UpdateServerStatus 更新服务器状态（Wails绑定）
+func (a *App) UpdateServerStatus(id uint, status string) error {
+	req := models.MCPServerStatusUpdateRequest{Status: status}
+	if err := validateRequest(&req); err != nil {
+		return err
+	}
+	return a.mcpServerService.WithContext(a.uiContext()).UpdateStatus(id, req.Status)
+}
+
+// ToggleServer 切换服务器启用状态（Wails绑定）
+func (a *App) ToggleServer(id uint) (*models.MCPServer, error) {
+	return a.mcpServerService.WithContext(a.uiContext()).ToggleEnabled(id)
+}
+
+// GetServerTags 获取服务器使用的所有标签（Wails绑定）
+func (a *App) GetServerTags() ([]string, error) {
+	return a.mcpServerService.GetTags()
+}
+
+// DiscoverTools 连接服务器发现工具（Wails绑定）
+func (a *App) DiscoverTools(serverID uint) (*models.MCPToolDiscoveryResponse, error) {
+	return a.mcpToolService.WithContext(a.uiContext()).DiscoverTools(serverID)
+}
+
+// RefreshTools 重新同步服务器的工具列表（Wails绑定）
+func (a *App) RefreshTools(serverID uint) (*models.MCPToolDiscoveryResponse, error) {
+	return a.mcpToolService.WithContext(a.uiContext()).RefreshAllTools(serverID)
+}
+
+// ListTools 查询工具列表（Wails绑定）
+func (a *App) ListTools(req models.MCPToolListRequest) (*models.MCPToolListResponse, error) {
+	if err := validateRequest(&req); err != nil {
+		return nil, err
+	}
+	return a.mcpToolService.GetToolsByServer(&req)
+}
+
+// UpdateTool 更新工具（Wails绑定）
+func (a *App) UpdateTool(id uint, req models.MCPToolUpdateRequest) error {
+	if err := validateRequest(&req); err != nil {
+		return err
+	}
+	return a.mcpToolService.WithContext(a.uiContext()).UpdateTool(id, &req)
+}
+
+// BatchUpdateTools 批量更新工具（Wails绑定）
+func (a *App) BatchUpdateTools(req models.MCPToolBatchUpdateRequest) error {
+	if err := validateRequest(&req); err != nil {
+		return err
+	}
+	return a.mcpToolService.WithContext(a.uiContext()).BatchUpdateTools(&req)
+}
+
+// GetToolCategories 获取工具分类，serverID 为0时返回所有服务器的分类（Wails绑定）
+func (a *App) GetToolCategories(serverID uint) ([]string, error) {
+	return a.mcpToolService.GetToolCategories(serverID)
+}
+
+// ListAppLogs 查询应用日志（Wails绑定）
+func (a *App) ListAppLogs(req models.AppLogListRequest) (*models.AppLogListResponse, error) {
+	if err := validateRequest(&req); err != nil {
+		return nil, err
+	}
+	return a.queryAppLogs(&req)
+}
+
+// CallTool 调用工具（Wails绑定）
+func (a *App) CallTool(id uint, req models.MCPToolCallRequest) (*models.MCPToolCallResponse, error) {
+	if err := validateRequest(&req); err != nil {
+		return nil, err
+	}
+	return a.mcpToolService.WithContext(a.uiContext()).CallTool(id, &req)
+}
+
+// ListTraffic 查询抓取的JSON-RPC流量（Wails绑定）
+func (a *App) ListTraffic(serverID uint, req models.MCPTrafficListRequest) (*models.MCPTrafficListResponse, error) {
+	if err := validateRequest(&req); err != nil {
+		return nil, err
+	}
+	return a.trafficService.List(serverID, &req), nil
+}
+
+// SetTrafficCapture 开启或关闭JSON-RPC流量抓取（Wails绑定）
+func (a *App) SetTrafficCapture(serverID uint, enabled bool) *models.MCPTrafficStatus {
+	return a.trafficService.SetCapture(serverID, enabled)
+}
+
+// ClearTraffic 清空抓取的流量，返回删除的条数（Wails绑定）
+func (a *App) ClearTraffic(serverID uint) int {
+	return a.trafficService.Clear(serverID)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/models"
+)
+
+// TestValidateRequest 测试绑定方法的请求使用 form 标签中的默认值并按 binding 标签校验
+func TestValidateRequest(t *testing.T) {
+	req := models.MCPServerListRequest{Size: 20}
+	if err := validateRequest(&req); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if req.Page != 1 || req.Size != 20 || req.TagMode != "or" || req.OrderBy != "created_at" || req.OrderDir != "desc" {
+		t.Fatalf("默认值不正确: %+v", req)
+	}
+
+	err := validateRequest(&models.MCPServerListRequest{Size: 1000})
+	if !errors.Is(err, &apperrors.Error{Kind: apperrors.KindValidation, Code: apperrors.CodeInvalidRequest}) {
+		t.Fatalf("超出范围的参数应返回请求参数错误: %v", err)
+	}
+}
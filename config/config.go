@@ -0,0 +1,221 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"desktop-ai-tools/logging"
+)
+
+// Config 应用配置，按 默认值 < 配置文件 < 环境变量 < 命令行参数 的优先级合并
+type Config struct {
+	Server   ServerConfig   `json:"server"`
+	Database DatabaseConfig `json:"database"`
+	CORS     CORSConfig     `json:"cors"`
+	MCP      MCPConfig      `json:"mcp"`
+	UI       UIConfig       `json:"ui"`
+	Log      LogConfig      `json:"log"`
+}
+
+// ServerConfig 内置HTTP API服务配置
+type ServerConfig struct {
+	Enabled       bool   `json:"enabled"` // 为false时不启动HTTP API，桌面界面通过Wails绑定调用
+	Host          string `json:"host"`
+	Port          int    `json:"port"`
+	PortFallback  bool   `json:"port_fallback"`  // 端口被占用时自动选择空闲端口
+	Mode          string `json:"mode"`           // debug, release, test
+	MetricsPublic bool   `json:"metrics_public"` // 默认false，为true时 /metrics 无需API令牌，供本地 Prometheus 抓取
+}
+
+// DatabaseConfig 数据库配置
+type DatabaseConfig struct {
+	Path     string `json:"path"`      // 为空时使用 ~/.desktop-ai-tools/app.db
+	LogLevel string `json:"log_level"` // silent, error, warn, info
+}
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	AllowOrigins []string `json:"allow_origins"` // 允许访问API的来源，不支持通配符
+}
+
+// DefaultAllowOrigins 默认允许的跨域来源：各平台的Wails界面和 wails dev 开发服务器
+var DefaultAllowOrigins = []string{
+	"wails://wails",
+	"wails://wails.localhost",
+	"http://wails.localhost",
+	"http://localhost:34115",
+}
+
+// MCPConfig MCP客户端相关配置
+type MCPConfig struct {
+	ToolCacheTTL        int `json:"tool_cache_ttl"`         // 工具结果缓存的默认有效期（秒）
+	ToolCacheMaxEntries int `json:"tool_cache_max_entries"` // 工具结果缓存的最大条目数
+	ElicitationTimeout  int `json:"elicitation_timeout"`    // 等待用户响应信息收集请求的超时时间（秒）
+}
+
+// UIConfig 界面相关配置
+type UIConfig struct {
+	Language string `json:"language"` // 接口消息语言：zh-CN, en-US，为空时按请求的 Accept-Language 选择
+}
+
+// LogConfig 应用日志配置
+type LogConfig struct {
+	Level      string            `json:"level"`       // 默认日志级别：debug, info, warn, error
+	Subsystems map[string]string `json:"subsystems"`  // 各子系统的日志级别，覆盖默认级别，例如 {"mcp": "debug"}，值为空时使用默认级别
+	Dir        string            `json:"dir"`         // 日志目录，为空时使用 ~/.desktop-ai-tools/logs
+	MaxSizeMB  int               `json:"max_size_mb"` // 单个日志文件的最大大小（MB），超过后轮转
+	MaxFiles   int               `json:"max_files"`   // 保留的历史日志文件数
+}
+
+// Default 默认配置
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Enabled:      true,
+			Host:         "127.0.0.1",
+			Port:         8080,
+			PortFallback: true,
+			Mode:         "debug",
+		},
+		Database: DatabaseConfig{
+			LogLevel: "info",
+		},
+		CORS: CORSConfig{
+			AllowOrigins: append([]string(nil), DefaultAllowOrigins...),
+		},
+		MCP: MCPConfig{
+			ToolCacheTTL:        300,
+			ToolCacheMaxEntries: 500,
+			ElicitationTimeout:  120,
+		},
+		Log: LogConfig{
+			Level:     "info",
+			MaxSizeMB: 10,
+			MaxFiles:  5,
+		},
+	}
+}
+
+// Validate 校验配置
+func (c *Config) Validate() error {
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("无效的端口: %d", c.Server.Port)
+	}
+	switch c.Server.Mode {
+	case "debug", "release", "test":
+	default:
+		return fmt.Errorf("无效的运行模式: %s", c.Server.Mode)
+	}
+	switch c.Database.LogLevel {
+	case "silent", "error", "warn", "info":
+	default:
+		return fmt.Errorf("无效的数据库日志级别: %s", c.Database.LogLevel)
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		if origin == "*" {
+			return fmt.Errorf("不支持通配符跨域来源，请列出允许的来源")
+		}
+		if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") && !strings.HasPrefix(origin, "wails://") {
+			return fmt.Errorf("无效的跨域来源: %s", origin)
+		}
+	}
+	if c.MCP.ToolCacheTTL < 0 {
+		return fmt.Errorf("工具结果缓存有效期不能为负数")
+	}
+	if c.MCP.ToolCacheMaxEntries < 0 {
+		return fmt.Errorf("工具结果缓存条目数不能为负数")
+	}
+	if c.MCP.ElicitationTimeout <= 0 {
+		return fmt.Errorf("信息收集超时时间必须大于0")
+	}
+	switch c.UI.Language {
+	case "", "zh-CN", "en-US":
+	default:
+		return fmt.Errorf("不支持的语言: %s", c.UI.Language)
+	}
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		return err
+	}
+	for subsystem, level := range c.Log.Subsystems {
+		if !logging.ValidSubsystem(subsystem) {
+			return fmt.Errorf("未知的日志子系统: %s，可选: %s", subsystem, strings.Join(logging.Subsystems(), ", "))
+		}
+		if level == "" {
+			continue
+		}
+		if _, err := logging.ParseLevel(level); err != nil {
+			return fmt.Errorf("子系统 %s 的%v", subsystem, err)
+		}
+	}
+	if c.Log.MaxSizeMB <= 0 {
+		return fmt.Errorf("日志文件大小上限必须大于0")
+	}
+	if c.Log.MaxFiles < 0 {
+		return fmt.Errorf("保留的日志文件数不能为负数")
+	}
+	return nil
+}
+
+// IsLoopback 判断API服务是否只监听本机回环地址
+func (c ServerConfig) IsLoopback() bool {
+	if c.Host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(c.Host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// DataDir 获取应用数据目录（~/.desktop-ai-tools），不存在时自动创建
+func DataDir() (string, error) {
+	// 获取用户主目录
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %v", err)
+	}
+
+	// 创建应用数据目录
+	dir := filepath.Join(homeDir, ".desktop-ai-tools")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建应用数据目录失败: %v", err)
+	}
+	return dir, nil
+}
+
+// DatabasePath 获取数据库文件路径，支持 ~ 开头的路径
+func (c *Config) DatabasePath() (string, error) {
+	if c.Database.Path != "" {
+		return expandHome(c.Database.Path), nil
+	}
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "app.db"), nil
+}
+
+// LogDir 获取日志目录，支持 ~ 开头的路径
+func (c *Config) LogDir() (string, error) {
+	if c.Log.Dir != "" {
+		return expandHome(c.Log.Dir), nil
+	}
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "logs"), nil
+}
+
+// expandHome 展开路径开头的 ~
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,379 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"desktop-ai-tools/logging"
+)
+
+const (
+	// EnvPrefix 环境变量前缀
+	EnvPrefix = "DESKTOP_AI_TOOLS_"
+	// configFileName 默认配置文件名
+	configFileName = "config.json"
+)
+
+// configLog 配置子系统的日志记录器
+var configLog = logging.For(logging.SubsystemConfig)
+
+// restartKeys 修改后需要重启才能生效的配置项
+var restartKeys = []string{"server.enabled", "server.host", "server.port", "server.port_fallback", "server.mode", "server.metrics_public", "database.path", "log.dir", "log.max_size_mb", "log.max_files"}
+
+// override 来自环境变量或命令行参数的配置覆盖
+type override struct {
+	key    string
+	source string // env, flag
+	apply  func(*Config) error
+}
+
+// Manager 管理分层配置：配置文件中的值可在运行时修改，环境变量和命令行参数始终优先
+type Manager struct {
+	mu        sync.RWMutex
+	path      string
+	file      Config // 默认值合并配置文件后的配置，PUT /api/settings 修改的就是这一层
+	effective Config // 合并环境变量和命令行参数后的最终配置
+	overrides []override
+	listeners []func(Config)
+}
+
+// SettingsView 设置接口返回的配置信息
+type SettingsView struct {
+	Config     Config            `json:"config"`      // 当前生效的配置
+	File       Config            `json:"file"`        // 配置文件中的配置
+	ConfigFile string            `json:"config_file"` // 配置文件路径
+	Overridden map[string]string `json:"overridden"`  // 被环境变量或命令行参数覆盖的配置项及其来源
+}
+
+// Load 加载配置，args 为命令行参数（不含程序名）
+func Load(args []string) (*Manager, error) {
+	flags, configPath, err := parseFlags(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if configPath == "" {
+		configPath = os.Getenv(EnvPrefix + "CONFIG")
+	}
+	if configPath == "" {
+		dir, err := DataDir()
+		if err != nil {
+			return nil, err
+		}
+		configPath = filepath.Join(dir, configFileName)
+	}
+
+	m := &Manager{
+		path:      expandHome(configPath),
+		overrides: append(envOverrides(), flags...),
+	}
+
+	file := Default()
+	data, err := os.ReadFile(m.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("解析配置文件 %s 失败: %v", m.path, err)
+		}
+		dropWildcardOrigins(&file)
+	}
+
+	effective, err := m.merge(file)
+	if err != nil {
+		return nil, err
+	}
+	m.file = file
+	m.effective = effective
+	return m, nil
+}
+
+// Get 获取当前生效的配置
+func (m *Manager) Get() Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.effective
+}
+
+// View 获取设置接口展示的配置信息
+func (m *Manager) View() SettingsView {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	overridden := make(map[string]string, len(m.overrides))
+	for _, o := range m.overrides {
+		overridden[o.key] = o.source
+	}
+	return SettingsView{
+		Config:     m.effective,
+		File:       m.file,
+		ConfigFile: m.path,
+		Overridden: overridden,
+	}
+}
+
+// OnChange 注册配置变更回调，运行时可修改的配置通过回调生效
+func (m *Manager) OnChange(fn func(Config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+// Update 用JSON补丁更新配置文件层，返回需要重启才能生效的配置项
+func (m *Manager) Update(patch []byte) ([]string, error) {
+	m.mu.Lock()
+
+	file := m.file
+	// 切片字段需要整体替换，先复制一份避免修改原配置
+	file.CORS.AllowOrigins = append([]string(nil), m.file.CORS.AllowOrigins...)
+	// 映射字段按键合并，同样先复制
+	file.Log.Subsystems = make(map[string]string, len(m.file.Log.Subsystems))
+	for subsystem, level := range m.file.Log.Subsystems {
+		file.Log.Subsystems[subsystem] = level
+	}
+	if err := json.Unmarshal(patch, &file); err != nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("解析配置失败: %v", err)
+	}
+	if err := file.Validate(); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	effective, err := m.merge(file)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if err := m.save(file); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	restart := changedKeys(m.file, file, restartKeys)
+	m.file = file
+	m.effective = effective
+	listeners := append([]func(Config){}, m.listeners...)
+	m.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(effective)
+	}
+	return restart, nil
+}
+
+// merge 在配置文件层之上应用环境变量和命令行参数，并校验结果
+func (m *Manager) merge(file Config) (Config, error) {
+	effective := file
+	effective.CORS.AllowOrigins = append([]string(nil), file.CORS.AllowOrigins...)
+	for _, o := range m.overrides {
+		if err := o.apply(&effective); err != nil {
+			return Config{}, fmt.Errorf("%s 配置项 %s 无效: %v", sourceName(o.source), o.key, err)
+		}
+	}
+	if err := effective.Validate(); err != nil {
+		return Config{}, err
+	}
+	return effective, nil
+}
+
+// save 写入配置文件
+func (m *Manager) save(file Config) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+	if err := os.WriteFile(m.path, data, 0600); err != nil {
+		return fmt.Errorf("保存配置文件失败: %v", err)
+	}
+	return nil
+}
+
+// setters 可通过环境变量和命令行参数覆盖的配置项
+var setters = []struct {
+	key   string
+	env   string
+	flag  string
+	usage string
+	set   func(c *Config, value string) error
+}{
+	{"server.enabled", "API_ENABLED", "api-enabled", "是否启动HTTP API服务", func(c *Config, v string) error { return setBool(&c.Server.Enabled, v) }},
+	{"server.host", "HOST", "host", "API服务监听地址", func(c *Config, v string) error { c.Server.Host = v; return nil }},
+	{"server.port", "PORT", "port", "API服务端口", func(c *Config, v string) error { return setInt(&c.Server.Port, v) }},
+	{"server.port_fallback", "PORT_FALLBACK", "port-fallback", "端口被占用时自动选择空闲端口", func(c *Config, v string) error { return setBool(&c.Server.PortFallback, v) }},
+	{"server.mode", "MODE", "mode", "运行模式：debug, release, test", func(c *Config, v string) error { c.Server.Mode = v; return nil }},
+	{"server.metrics_public", "METRICS_PUBLIC", "metrics-public", "/metrics 是否无需API令牌即可访问", func(c *Config, v string) error { return setBool(&c.Server.MetricsPublic, v) }},
+	{"database.path", "DB_PATH", "db-path", "数据库文件路径", func(c *Config, v string) error { c.Database.Path = v; return nil }},
+	{"database.log_level", "DB_LOG_LEVEL", "db-log-level", "数据库日志级别：silent, error, warn, info", func(c *Config, v string) error { c.Database.LogLevel = v; return nil }},
+	{"cors.allow_origins", "CORS_ORIGINS", "cors-origins", "允许的跨域来源，逗号分隔", func(c *Config, v string) error { c.CORS.AllowOrigins = splitList(v); return nil }},
+	{"mcp.tool_cache_ttl", "TOOL_CACHE_TTL", "tool-cache-ttl", "工具结果缓存默认有效期（秒）", func(c *Config, v string) error { return setInt(&c.MCP.ToolCacheTTL, v) }},
+	{"mcp.tool_cache_max_entries", "TOOL_CACHE_MAX_ENTRIES", "tool-cache-max-entries", "工具结果缓存最大条目数", func(c *Config, v string) error { return setInt(&c.MCP.ToolCacheMaxEntries, v) }},
+	{"mcp.elicitation_timeout", "ELICITATION_TIMEOUT", "elicitation-timeout", "信息收集请求超时时间（秒）", func(c *Config, v string) error { return setInt(&c.MCP.ElicitationTimeout, v) }},
+	{"log.level", "LOG_LEVEL", "log-level", "日志级别：debug, info, warn, error", func(c *Config, v string) error { c.Log.Level = v; return nil }},
+	{"log.dir", "LOG_DIR", "log-dir", "日志目录", func(c *Config, v string) error { c.Log.Dir = v; return nil }},
+	{"ui.language", "LANGUAGE", "language", "接口消息语言：zh-CN, en-US，为空时按请求的 Accept-Language 选择", func(c *Config, v string) error { c.UI.Language = v; return nil }},
+}
+
+// envOverrides 读取环境变量覆盖
+func envOverrides() []override {
+	var overrides []override
+	for _, s := range setters {
+		value, ok := os.LookupEnv(EnvPrefix + s.env)
+		if !ok {
+			continue
+		}
+		set := s.set
+		overrides = append(overrides, override{
+			key:    s.key,
+			source: "env",
+			apply:  func(c *Config) error { return set(c, value) },
+		})
+	}
+	return overrides
+}
+
+// parseFlags 解析命令行参数覆盖，未知参数会被忽略（如 wails dev 传入的参数）
+func parseFlags(args []string) ([]override, string, error) {
+	fs := flag.NewFlagSet("desktop-ai-tools", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configPath := fs.String("config", "", "配置文件路径")
+	values := make([]*string, len(setters))
+	for i, s := range setters {
+		values[i] = fs.String(s.flag, "", s.usage)
+	}
+
+	if err := fs.Parse(filterKnownFlags(fs, args)); err != nil {
+		return nil, "", fmt.Errorf("解析命令行参数失败: %v", err)
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var overrides []override
+	for i, s := range setters {
+		if !set[s.flag] {
+			continue
+		}
+		value, setter := *values[i], s.set
+		overrides = append(overrides, override{
+			key:    s.key,
+			source: "flag",
+			apply:  func(c *Config) error { return setter(c, value) },
+		})
+	}
+	return overrides, *configPath, nil
+}
+
+// filterKnownFlags 只保留已定义的命令行参数
+func filterKnownFlags(fs *flag.FlagSet, args []string) []string {
+	var known []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		hasValue := strings.Contains(name, "=")
+		if hasValue {
+			name = name[:strings.Index(name, "=")]
+		}
+		if fs.Lookup(name) == nil {
+			continue
+		}
+		known = append(known, arg)
+		if !hasValue && i+1 < len(args) {
+			known = append(known, args[i+1])
+			i++
+		}
+	}
+	return known
+}
+
+// dropWildcardOrigins 旧版本保存的配置文件中跨域来源默认为 "*"，加载时替换为默认白名单
+func dropWildcardOrigins(file *Config) {
+	for _, origin := range file.CORS.AllowOrigins {
+		if origin == "*" {
+			configLog.Warn("配置文件中的通配符跨域来源已不再支持，改用默认白名单", "allow_origins", strings.Join(DefaultAllowOrigins, ", "))
+			file.CORS.AllowOrigins = append([]string(nil), DefaultAllowOrigins...)
+			return
+		}
+	}
+}
+
+// changedKeys 比较两份配置，返回指定配置项中发生变化的部分
+func changedKeys(before, after Config, keys []string) []string {
+	values := func(c Config) map[string]string {
+		return map[string]string{
+			"server.enabled":        strconv.FormatBool(c.Server.Enabled),
+			"server.host":           c.Server.Host,
+			"server.port":           strconv.Itoa(c.Server.Port),
+			"server.port_fallback":  strconv.FormatBool(c.Server.PortFallback),
+			"server.mode":           c.Server.Mode,
+			"server.metrics_public": strconv.FormatBool(c.Server.MetricsPublic),
+			"database.path":         c.Database.Path,
+			"log.dir":               c.Log.Dir,
+			"log.max_size_mb":       strconv.Itoa(c.Log.MaxSizeMB),
+			"log.max_files":         strconv.Itoa(c.Log.MaxFiles),
+		}
+	}
+	b, a := values(before), values(after)
+
+	changed := []string{}
+	for _, key := range keys {
+		if b[key] != a[key] {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// setInt 解析整数配置
+func setInt(target *int, value string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("不是有效的整数: %s", value)
+	}
+	*target = n
+	return nil
+}
+
+// setBool 解析布尔配置
+func setBool(target *bool, value string) error {
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("不是有效的布尔值: %s", value)
+	}
+	*target = b
+	return nil
+}
+
+// splitList 解析逗号分隔的列表
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sourceName 配置来源的显示名称
+func sourceName(source string) string {
+	if source == "flag" {
+		return "命令行参数"
+	}
+	return "环境变量"
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadPrecedence 测试配置文件、环境变量和命令行参数的优先级
+func TestLoadPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"server": {"port": 9000, "host": "0.0.0.0"}, "database": {"log_level": "warn"}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	t.Setenv(EnvPrefix+"PORT", "9100")
+	t.Setenv(EnvPrefix+"DB_LOG_LEVEL", "error")
+	t.Setenv(EnvPrefix+"TOOL_CACHE_MAX_ENTRIES", "50")
+
+	m, err := Load([]string{"-config", path, "-port=9200", "-unknown", "value"})
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	cfg := m.Get()
+	if cfg.Server.Port != 9200 {
+		t.Fatalf("命令行参数应覆盖环境变量，实际端口: %d", cfg.Server.Port)
+	}
+	if cfg.Database.LogLevel != "error" {
+		t.Fatalf("环境变量应覆盖配置文件，实际日志级别: %s", cfg.Database.LogLevel)
+	}
+	if cfg.MCP.ToolCacheMaxEntries != 50 {
+		t.Fatalf("应使用环境变量中的缓存条目数，实际: %d", cfg.MCP.ToolCacheMaxEntries)
+	}
+	if cfg.Server.Host != "0.0.0.0" {
+		t.Fatalf("应使用配置文件中的监听地址，实际: %s", cfg.Server.Host)
+	}
+	if cfg.MCP.ElicitationTimeout != Default().MCP.ElicitationTimeout {
+		t.Fatalf("未配置的项应使用默认值，实际: %d", cfg.MCP.ElicitationTimeout)
+	}
+	if cfg.Server.MetricsPublic {
+		t.Fatal("默认 /metrics 应需要API令牌")
+	}
+
+	overridden := m.View().Overridden
+	if overridden["server.port"] != "flag" || overridden["database.log_level"] != "env" {
+		t.Fatalf("覆盖来源不正确: %v", overridden)
+	}
+}
+
+// TestUpdate 测试运行时修改配置
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(EnvPrefix+"DB_LOG_LEVEL", "error")
+
+	m, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	var notified Config
+	m.OnChange(func(cfg Config) { notified = cfg })
+
+	restart, err := m.Update([]byte(`{"server": {"port": 9300}, "database": {"log_level": "silent"}, "mcp": {"tool_cache_ttl": 60}}`))
+	if err != nil {
+		t.Fatalf("更新配置失败: %v", err)
+	}
+	if len(restart) != 1 || restart[0] != "server.port" {
+		t.Fatalf("需要重启的配置项不正确: %v", restart)
+	}
+	if notified.MCP.ToolCacheTTL != 60 {
+		t.Fatalf("配置变更回调未收到新配置")
+	}
+	if m.Get().Database.LogLevel != "error" {
+		t.Fatalf("环境变量覆盖的配置不应被修改，实际: %s", m.Get().Database.LogLevel)
+	}
+
+	// 重新加载后应读取到保存的配置
+	reloaded, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("重新加载配置失败: %v", err)
+	}
+	if reloaded.View().File.Database.LogLevel != "silent" || reloaded.Get().MCP.ToolCacheTTL != 60 {
+		t.Fatalf("配置未保存到文件")
+	}
+
+	if _, err := m.Update([]byte(`{"server": {"mode": "invalid"}}`)); err == nil {
+		t.Fatalf("无效配置应更新失败")
+	}
+}
+
+// TestWildcardOrigins 测试不再接受通配符跨域来源，旧配置文件中的通配符替换为默认白名单
+func TestWildcardOrigins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"cors": {"allow_origins": ["*"]}}`), 0600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	m, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("加载旧配置文件失败: %v", err)
+	}
+	if origins := m.Get().CORS.AllowOrigins; len(origins) != len(DefaultAllowOrigins) || origins[0] != DefaultAllowOrigins[0] {
+		t.Fatalf("通配符应替换为默认白名单，实际: %v", origins)
+	}
+	if !m.Get().Server.IsLoopback() {
+		t.Fatalf("默认应只监听本机回环地址，实际: %s", m.Get().Server.Host)
+	}
+
+	if _, err := m.Update([]byte(`{"cors": {"allow_origins": ["*"]}}`)); err == nil {
+		t.Fatalf("不应允许设置通配符跨域来源")
+	}
+}
@@ -2,7 +2,6 @@ package database
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 
@@ -10,31 +9,43 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"desktop-ai-tools/config"
 	"desktop-ai-tools/models"
+	"desktop-ai-tools/security"
 )
 
 var DB *gorm.DB
 
+// AppDataDir 获取应用数据目录（~/.desktop-ai-tools），不存在时自动创建
+func AppDataDir() (string, error) {
+	return config.DataDir()
+}
+
 // InitDatabase 初始化数据库连接
-func InitDatabase() error {
-	// 获取用户主目录
-	homeDir, err := os.UserHomeDir()
+func InitDatabase(cfg *config.Config) error {
+	appDataDir, err := AppDataDir()
 	if err != nil {
-		return fmt.Errorf("获取用户主目录失败: %v", err)
+		return err
 	}
 
-	// 创建应用数据目录
-	appDataDir := filepath.Join(homeDir, ".desktop-ai-tools")
-	if err := os.MkdirAll(appDataDir, 0755); err != nil {
-		return fmt.Errorf("创建应用数据目录失败: %v", err)
+	// 加载敏感字段加密使用的主密钥，密钥文件与数据库分开保存
+	if _, err := security.InitKeyring(appDataDir); err != nil {
+		return fmt.Errorf("加载主密钥失败: %v", err)
 	}
 
 	// 数据库文件路径
-	dbPath := filepath.Join(appDataDir, "app.db")
+	dbPath, err := cfg.DatabasePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return fmt.Errorf("创建数据库目录失败: %v", err)
+	}
 
-	// 配置GORM日志
+	// 配置GORM日志，日志级别可在运行时调整
+	SetLogLevel(cfg.Database.LogLevel)
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: gormLogger,
 	}
 
 	// 连接数据库
@@ -43,23 +54,39 @@ func InitDatabase() error {
 		return fmt.Errorf("连接数据库失败: %v", err)
 	}
 
+	// 统计数据库操作耗时，供 /metrics 输出
+	if err := registerMetrics(db); err != nil {
+		return fmt.Errorf("注册数据库指标失败: %v", err)
+	}
+
 	DB = db
 
-	// 自动迁移数据库表
-	if err := autoMigrate(); err != nil {
+	// 执行版本化迁移
+	if _, err := Migrate(db, dbPath); err != nil {
 		return fmt.Errorf("数据库迁移失败: %v", err)
 	}
 
-	log.Printf("数据库初始化成功，数据库文件: %s", dbPath)
+	dbLog.Info("数据库初始化成功", "path", dbPath)
 	return nil
 }
 
-// autoMigrate 自动迁移数据库表
-func autoMigrate() error {
-	return DB.AutoMigrate(
-		&models.MCPServer{},
-		&models.MCPTool{},
-	)
+// SetLogLevel 设置数据库日志级别，可在运行时调用
+func SetLogLevel(level string) {
+	gormLogger.current.Store(appLogger{level: logLevel(level)})
+}
+
+// logLevel 将配置中的日志级别转换为GORM日志级别
+func logLevel(level string) logger.LogLevel {
+	switch level {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "warn":
+		return logger.Warn
+	default:
+		return logger.Info
+	}
 }
 
 // GetDB 获取数据库实例
@@ -98,11 +125,11 @@ func SeedData() error {
 			Name:        "示例MCP服务器",
 			Description: "这是一个示例MCP服务器，用于演示功能",
 			URL:         "https://api.example.com/mcp",
-			AuthType:    "bearer",
-			AuthConfig:  `{"token": "your-api-token"}`,
+			AuthType:    "none",
+			AuthConfig:  "",
 			Status:      "active",
 			IsEnabled:   true,
-			Tags:        "示例,测试,API",
+			Tags:        models.TagList{{Name: "示例"}, {Name: "测试"}, {Name: "API"}},
 		},
 		{
 			Name:        "本地开发服务器",
@@ -112,17 +139,17 @@ func SeedData() error {
 			AuthConfig:  "",
 			Status:      "inactive",
 			IsEnabled:   false,
-			Tags:        "本地,开发",
+			Tags:        models.TagList{{Name: "本地"}, {Name: "开发"}},
 		},
 	}
 
 	for _, server := range sampleServers {
 		if err := DB.Create(&server).Error; err != nil {
-			log.Printf("插入种子数据失败: %v", err)
+			dbLog.Error("插入种子数据失败", "error", err)
 			return err
 		}
 	}
 
-	log.Println("种子数据插入成功")
+	dbLog.Info("种子数据插入成功")
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+
+	"desktop-ai-tools/logging"
+)
+
+// dbLog 数据库子系统的日志记录器
+var dbLog = logging.For(logging.SubsystemDatabase)
+
+// gormLogger 全局数据库日志记录器
+var gormLogger = newSwitchableLogger(appLogger{level: logger.Info})
+
+// slowQueryThreshold 执行时间超过该值的SQL记录为慢查询
+const slowQueryThreshold = 200 * time.Millisecond
+
+// switchableLogger 可在运行时切换日志级别的GORM日志记录器
+type switchableLogger struct {
+	current atomic.Value // logger.Interface
+}
+
+// newSwitchableLogger 创建可切换日志级别的日志记录器
+func newSwitchableLogger(initial logger.Interface) *switchableLogger {
+	l := &switchableLogger{}
+	l.current.Store(initial)
+	return l
+}
+
+func (l *switchableLogger) get() logger.Interface {
+	return l.current.Load().(logger.Interface)
+}
+
+// LogMode 返回固定级别的日志记录器，供 Session 等场景单独调整日志级别
+func (l *switchableLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return l.get().LogMode(level)
+}
+
+func (l *switchableLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.get().Info(ctx, msg, data...)
+}
+
+func (l *switchableLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.get().Warn(ctx, msg, data...)
+}
+
+func (l *switchableLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.get().Error(ctx, msg, data...)
+}
+
+func (l *switchableLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.get().Trace(ctx, begin, fc, err)
+}
+
+// appLogger 将GORM日志写入应用日志，执行的SQL记录为调试级别，失败和慢查询分别记录为错误和警告
+type appLogger struct {
+	level logger.LogLevel
+}
+
+func (l appLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return appLogger{level: level}
+}
+
+func (l appLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		dbLog.InfoContext(ctx, fmt.Sprintf(msg, data...), "source", utils.FileWithLineNum())
+	}
+}
+
+func (l appLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		dbLog.WarnContext(ctx, fmt.Sprintf(msg, data...), "source", utils.FileWithLineNum())
+	}
+}
+
+func (l appLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		dbLog.ErrorContext(ctx, fmt.Sprintf(msg, data...), "source", utils.FileWithLineNum())
+	}
+}
+
+func (l appLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		dbLog.ErrorContext(ctx, "SQL执行失败", "sql", sql, "rows", rows, "elapsed", elapsed, "source", utils.FileWithLineNum(), "error", err)
+	case elapsed > slowQueryThreshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		dbLog.WarnContext(ctx, "慢查询", "sql", sql, "rows", rows, "elapsed", elapsed, "source", utils.FileWithLineNum())
+	case l.level >= logger.Info && dbLog.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		dbLog.DebugContext(ctx, "执行SQL", "sql", sql, "rows", rows, "elapsed", elapsed, "source", utils.FileWithLineNum())
+	}
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/metrics"
+)
+
+// metricsStartedKey 操作开始时间在语句实例设置中的键名
+const metricsStartedKey = "metrics:started"
+
+// registerMetrics 注册GORM回调，统计每类数据库操作的执行时间和失败次数
+func registerMetrics(db *gorm.DB) error {
+	callbacks := db.Callback()
+	type register func(name string, fn func(*gorm.DB)) error
+	processors := []struct {
+		operation     string
+		before, after register
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, p := range processors {
+		if err := p.before("metrics:before_"+p.operation, startTimer); err != nil {
+			return err
+		}
+		if err := p.after("metrics:after_"+p.operation, observeQuery(p.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startTimer 记录操作开始时间
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(metricsStartedKey, time.Now())
+}
+
+// observeQuery 返回记录操作耗时的回调，查询无记录不计为失败
+func observeQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(metricsStartedKey)
+		if !ok {
+			return
+		}
+		started, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+		metrics.ObserveDBQuery(operation, db.Statement.Table, time.Since(started), failed)
+	}
+}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// snapshotKeep 保留的迁移前快照数量
+const snapshotKeep = 5
+
+// Migration 数据库结构迁移，按版本号顺序执行
+type Migration struct {
+	Version int
+	Name    string
+	// Up 在事务中执行迁移，失败时整体回滚
+	Up func(tx *gorm.DB) error
+}
+
+// schemaMigration 已执行的迁移记录
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:100"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus 数据库迁移状态
+type MigrationStatus struct {
+	Current  int    // 迁移前的数据库结构版本
+	Target   int    // 应用支持的数据库结构版本
+	Applied  []int  // 本次执行的迁移版本
+	Snapshot string // 迁移前创建的数据库快照，未创建时为空
+}
+
+// Migrate 执行尚未应用的迁移，数据库结构版本高于应用支持的版本时拒绝启动
+func Migrate(db *gorm.DB, dbPath string) (*MigrationStatus, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("创建迁移记录表失败: %v", err)
+	}
+
+	current, err := currentVersion(db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{Current: current, Target: SchemaVersion}
+	if current > SchemaVersion {
+		return nil, fmt.Errorf("数据库结构版本 %d 高于当前应用支持的版本 %d，请升级应用后再打开", current, SchemaVersion)
+	}
+
+	var pending []Migration
+	for _, m := range migrations {
+		if m.Version > current {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return status, nil
+	}
+
+	// 已有数据的数据库在迁移前创建快照，迁移出错时可手动恢复
+	if hasUserTables(db) && dbPath != "" {
+		snapshot, err := snapshotDatabase(db, dbPath, current)
+		if err != nil {
+			return nil, err
+		}
+		status.Snapshot = snapshot
+	}
+
+	for _, m := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("执行迁移 %d_%s 失败: %v", m.Version, m.Name, err)
+		}
+		status.Applied = append(status.Applied, m.Version)
+		dbLog.Info("已执行数据库迁移", "version", m.Version, "name", m.Name)
+	}
+	return status, nil
+}
+
+// currentVersion 获取数据库当前结构版本，未执行过迁移时为0
+func currentVersion(db *gorm.DB) (int, error) {
+	var version int
+	if err := db.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("查询数据库结构版本失败: %v", err)
+	}
+	return version, nil
+}
+
+// hasUserTables 判断数据库中是否已有业务表（包括引入版本化迁移之前创建的数据库）
+func hasUserTables(db *gorm.DB) bool {
+	var count int64
+	db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')").Scan(&count)
+	return count > 0
+}
+
+// snapshotDatabase 使用 VACUUM INTO 创建数据库快照，并清理过旧的快照
+func snapshotDatabase(db *gorm.DB, dbPath string, version int) (string, error) {
+	dir := filepath.Join(filepath.Dir(dbPath), "snapshots")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("创建快照目录失败: %v", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+	path := filepath.Join(dir, fmt.Sprintf("%s-v%d-%s.db", base, version, time.Now().Format("20060102-150405")))
+	if err := db.Exec("VACUUM INTO ?", path).Error; err != nil {
+		return "", fmt.Errorf("创建迁移前快照失败: %v", err)
+	}
+	dbLog.Info("已创建迁移前数据库快照", "path", path)
+
+	// 文件名中的版本号未补零（v10 按字符串排在 v9 之前），因此按末尾的时间戳排序
+	matches, err := filepath.Glob(filepath.Join(dir, base+"-v*.db"))
+	if err == nil && len(matches) > snapshotKeep {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return snapshotTimestamp(matches[i]) < snapshotTimestamp(matches[j])
+		})
+		for _, old := range matches[:len(matches)-snapshotKeep] {
+			os.Remove(old)
+		}
+	}
+	return path, nil
+}
+
+// snapshotTimestamp 取出快照文件名末尾的时间戳（20060102-150405），用于按创建时间排序
+func snapshotTimestamp(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ".db")
+	if len(name) < len("20060102-150405") {
+		return name
+	}
+	return name[len(name)-len("20060102-150405"):]
+}
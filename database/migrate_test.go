@@ -0,0 +1,204 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"desktop-ai-tools/models"
+)
+
+// openTestDB 打开临时数据库
+func openTestDB(t *testing.T, path string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// tableColumns 获取所有表的列定义，用于比较表结构
+func tableColumns(t *testing.T, db *gorm.DB) map[string][]string {
+	t.Helper()
+	var tables []string
+	db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')").Scan(&tables)
+
+	result := make(map[string][]string)
+	for _, table := range tables {
+		columns, err := db.Migrator().ColumnTypes(table)
+		if err != nil {
+			t.Fatalf("获取表 %s 的列失败: %v", table, err)
+		}
+		for _, column := range columns {
+			result[table] = append(result[table], column.Name()+" "+strings.ToLower(column.DatabaseTypeName()))
+		}
+		sort.Strings(result[table])
+	}
+	return result
+}
+
+// TestMigrationsMatchModels 测试执行全部迁移后的表结构与模型一致
+func TestMigrationsMatchModels(t *testing.T) {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Fatalf("迁移版本号必须从1开始连续递增，第 %d 个迁移的版本号为 %d", i+1, m.Version)
+		}
+	}
+	if last := migrations[len(migrations)-1].Version; last != SchemaVersion {
+		t.Fatalf("SchemaVersion(%d) 与最后一个迁移的版本号(%d)不一致", SchemaVersion, last)
+	}
+
+	dir := t.TempDir()
+	migrated := openTestDB(t, filepath.Join(dir, "migrated.db"))
+	if _, err := Migrate(migrated, ""); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+
+	expected := openTestDB(t, filepath.Join(dir, "models.db"))
+	if err := expected.AutoMigrate(
+		&models.MCPServer{},
+		&models.MCPTool{},
+		&models.SamplingConfig{},
+		&models.SamplingLog{},
+		&models.WorkspaceRoot{},
+		&models.MCPServerLog{},
+		&models.BackupSchedule{},
+		&models.Secret{},
+		&models.Tag{},
+		&models.Workspace{},
+		&models.AuditLog{},
+	); err != nil {
+		t.Fatalf("创建模型表结构失败: %v", err)
+	}
+
+	got, want := tableColumns(t, migrated), tableColumns(t, expected)
+	for table, columns := range want {
+		if strings.Join(got[table], ", ") != strings.Join(columns, ", ") {
+			t.Fatalf("表 %s 的结构与模型不一致，请添加迁移\n迁移结果: %v\n模型定义: %v", table, got[table], columns)
+		}
+	}
+	for table := range got {
+		if _, ok := want[table]; !ok {
+			t.Fatalf("迁移创建了模型中不存在的表 %s", table)
+		}
+	}
+}
+
+// TestMigrateExistingDatabase 测试迁移已有数据库时创建快照，且拒绝高于当前版本的数据库
+func TestMigrateExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	db := openTestDB(t, path)
+
+	// 模拟引入版本化迁移之前由 AutoMigrate 创建的数据库
+	if err := db.Exec("CREATE TABLE mcp_servers (id integer PRIMARY KEY, name text NOT NULL, url text NOT NULL, tags text)").Error; err != nil {
+		t.Fatalf("创建旧表失败: %v", err)
+	}
+	if err := db.Exec("INSERT INTO mcp_servers (name, url, tags) VALUES ('old', 'http://localhost', 'dev, devops,Dev,'), ('other', 'http://localhost', 'devops')").Error; err != nil {
+		t.Fatalf("插入旧数据失败: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE workspace_roots (id integer PRIMARY KEY, server_id integer, name text, path text NOT NULL)").Error; err != nil {
+		t.Fatalf("创建旧表失败: %v", err)
+	}
+	if err := db.Exec("INSERT INTO workspace_roots (server_id, name, path) VALUES (NULL, 'global', '/tmp'), (2, 'other', '/tmp')").Error; err != nil {
+		t.Fatalf("插入旧数据失败: %v", err)
+	}
+
+	status, err := Migrate(db, path)
+	if err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+	if status.Current != 0 || len(status.Applied) != len(migrations) {
+		t.Fatalf("迁移状态不正确: %+v", status)
+	}
+	if status.Snapshot == "" {
+		t.Fatalf("迁移已有数据库时应创建快照")
+	}
+	snapshot := openTestDB(t, status.Snapshot)
+	var count int64
+	snapshot.Raw("SELECT COUNT(*) FROM mcp_servers").Scan(&count)
+	if count != 2 {
+		t.Fatalf("快照中的数据不正确: %d", count)
+	}
+
+	var server models.MCPServer
+	if err := db.Preload("Tags").First(&server).Error; err != nil || server.Name != "old" || server.AuthType != "none" {
+		t.Fatalf("迁移后旧数据不正确: %+v, %v", server, err)
+	}
+	if names := strings.Join(server.GetTagList(), ","); names != "dev,devops" {
+		t.Fatalf("标签拆分不正确: %s", names)
+	}
+	var tagCount int64
+	db.Model(&models.Tag{}).Count(&tagCount)
+	if tagCount != 2 {
+		t.Fatalf("相同名称的标签应只创建一次，实际: %d", tagCount)
+	}
+	var workspace models.Workspace
+	if err := db.Where("is_active = ?", true).First(&workspace).Error; err != nil || server.WorkspaceID != workspace.ID {
+		t.Fatalf("已有服务器应归入默认工作区: %+v, %v", workspace, err)
+	}
+	var roots []models.WorkspaceRoot
+	db.Find(&roots)
+	if len(roots) != 2 || roots[0].WorkspaceID != workspace.ID || roots[1].WorkspaceID != workspace.ID {
+		t.Fatalf("已有根目录应归入默认工作区: %+v", roots)
+	}
+
+	// 再次执行时没有待执行的迁移
+	status, err = Migrate(db, path)
+	if err != nil || len(status.Applied) != 0 || status.Snapshot != "" {
+		t.Fatalf("重复执行迁移不应有变化: %+v, %v", status, err)
+	}
+
+	// 数据库结构版本高于应用支持的版本时拒绝打开
+	db.Create(&schemaMigration{Version: SchemaVersion + 1, Name: "future"})
+	if _, err := Migrate(db, path); err == nil {
+		t.Fatalf("数据库结构版本高于应用支持的版本时应返回错误")
+	}
+}
+
+// TestSnapshotPrune 测试清理快照时按时间保留最新的快照，版本号从 v9 升到 v10 后不会误删新快照
+func TestSnapshotPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	db := openTestDB(t, path)
+	dir := filepath.Join(filepath.Dir(path), "snapshots")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatalf("创建快照目录失败: %v", err)
+	}
+
+	old := []string{"app-v9-20240101-000000.db", "app-v9-20240102-000000.db", "app-v9-20240103-000000.db"}
+	recent := []string{"app-v10-20240104-000000.db", "app-v10-20240105-000000.db", "app-v10-20240106-000000.db"}
+	for _, name := range append(append([]string{}, old...), recent...) {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+			t.Fatalf("创建快照文件失败: %v", err)
+		}
+	}
+
+	created, err := snapshotDatabase(db, path, 10)
+	if err != nil {
+		t.Fatalf("创建快照失败: %v", err)
+	}
+
+	var remaining []string
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-v*.db"))
+	for _, match := range matches {
+		remaining = append(remaining, filepath.Base(match))
+	}
+	sort.Strings(remaining)
+	want := append([]string{filepath.Base(created)}, old[2:]...)
+	want = append(want, recent...)
+	sort.Strings(want)
+	if strings.Join(remaining, ",") != strings.Join(want, ",") {
+		t.Fatalf("应保留最新的 %d 个快照，实际: %v", snapshotKeep, remaining)
+	}
+}
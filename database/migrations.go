@@ -0,0 +1,301 @@
+package database
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SchemaVersion 当前数据库结构版本，等于最后一个迁移的版本号
+const SchemaVersion = 5
+
+// migrations 按版本号排列的迁移列表，已发布的迁移不能修改，结构变更需追加新的迁移
+var migrations = []Migration{
+	{Version: 1, Name: "initial_schema", Up: migrateInitialSchema},
+	{Version: 2, Name: "normalize_tags", Up: migrateNormalizeTags},
+	{Version: 3, Name: "add_workspaces", Up: migrateAddWorkspaces},
+	{Version: 4, Name: "add_audit_logs", Up: migrateAddAuditLogs},
+	{Version: 5, Name: "add_root_workspaces", Up: migrateAddRootWorkspaces},
+}
+
+// migrateInitialSchema 创建初始表结构
+// 引入版本化迁移之前的数据库由 AutoMigrate 创建，这里同样使用 AutoMigrate 补齐缺失的列和索引。
+// 迁移使用固定的结构体快照而不是 models 中的模型，避免之后修改模型影响历史迁移。
+func migrateInitialSchema(tx *gorm.DB) error {
+	return tx.AutoMigrate(
+		&mcpServerV1{},
+		&mcpToolV1{},
+		&samplingConfigV1{},
+		&samplingLogV1{},
+		&workspaceRootV1{},
+		&mcpServerLogV1{},
+		&backupScheduleV1{},
+		&secretV1{},
+	)
+}
+
+// migrateNormalizeTags 将服务器逗号分隔的标签拆分到标签表和关联表，并删除原有的 tags 列
+func migrateNormalizeTags(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&tagV2{}, &mcpServerTagV2{}, &mcpToolTagV2{}); err != nil {
+		return err
+	}
+
+	var rows []struct {
+		ID   uint
+		Tags string
+	}
+	if err := tx.Table("mcp_servers").Select("id, tags").Where("tags IS NOT NULL AND tags != ''").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	// 标签名称忽略大小写去重，保留第一次出现时的写法
+	tagIDs := make(map[string]uint)
+	for _, row := range rows {
+		for _, name := range strings.Split(row.Tags, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			key := strings.ToLower(name)
+			id, ok := tagIDs[key]
+			if !ok {
+				tag := tagV2{Name: name}
+				if err := tx.Create(&tag).Error; err != nil {
+					return err
+				}
+				id = tag.ID
+				tagIDs[key] = id
+			}
+			link := mcpServerTagV2{MCPServerID: row.ID, TagID: id}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Exec("ALTER TABLE mcp_servers DROP COLUMN tags").Error
+}
+
+// migrateAddWorkspaces 创建工作区表和默认工作区，已有的服务器全部归入默认工作区
+func migrateAddWorkspaces(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&workspaceV3{}); err != nil {
+		return err
+	}
+
+	workspace := workspaceV3{Name: "默认工作区", IsActive: true}
+	if err := tx.Create(&workspace).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec("ALTER TABLE mcp_servers ADD COLUMN workspace_id integer NOT NULL DEFAULT 0").Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("UPDATE mcp_servers SET workspace_id = ?", workspace.ID).Error; err != nil {
+		return err
+	}
+	return tx.Exec("CREATE INDEX idx_mcp_servers_workspace_id ON mcp_servers(workspace_id)").Error
+}
+
+// migrateAddAuditLogs 创建配置变更审计日志表
+func migrateAddAuditLogs(tx *gorm.DB) error {
+	return tx.AutoMigrate(&auditLogV4{})
+}
+
+// migrateAddRootWorkspaces 根目录归属工作区：服务器专属的根目录归入服务器所在的工作区，全局根目录归入当前工作区
+func migrateAddRootWorkspaces(tx *gorm.DB) error {
+	if err := tx.Exec("ALTER TABLE workspace_roots ADD COLUMN workspace_id integer NOT NULL DEFAULT 0").Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("UPDATE workspace_roots SET workspace_id = (SELECT workspace_id FROM mcp_servers WHERE mcp_servers.id = workspace_roots.server_id) WHERE server_id IN (SELECT id FROM mcp_servers)").Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("UPDATE workspace_roots SET workspace_id = COALESCE((SELECT id FROM workspaces WHERE is_active = ? ORDER BY id LIMIT 1), (SELECT MIN(id) FROM workspaces), 0) WHERE workspace_id = 0", true).Error; err != nil {
+		return err
+	}
+	return tx.Exec("CREATE INDEX idx_workspace_roots_workspace_id ON workspace_roots(workspace_id)").Error
+}
+
+// 以下为版本1的表结构快照
+
+type mcpServerV1 struct {
+	ID             uint   `gorm:"primaryKey"`
+	Name           string `gorm:"not null;size:100"`
+	Description    string `gorm:"size:500"`
+	URL            string `gorm:"not null;size:255"`
+	Transport      string `gorm:"size:20;default:'sse'"`
+	Command        string `gorm:"size:500"`
+	Args           string `gorm:"type:text"`
+	Env            string `gorm:"type:text"`
+	Headers        string `gorm:"type:text"`
+	AuthType       string `gorm:"size:50;default:'none'"`
+	AuthConfig     string `gorm:"type:text"`
+	Status         string `gorm:"size:20;default:'inactive'"`
+	IsEnabled      bool   `gorm:"default:true"`
+	Tags           string `gorm:"size:255"`
+	SamplingPolicy string `gorm:"size:20"`
+	LogLevel       string `gorm:"size:20"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+	Tools          []mcpToolV1    `gorm:"foreignKey:ServerID"`
+}
+
+func (mcpServerV1) TableName() string { return "mcp_servers" }
+
+type mcpToolV1 struct {
+	ID             uint   `gorm:"primaryKey"`
+	ServerID       uint   `gorm:"not null"`
+	Name           string `gorm:"not null;size:100"`
+	Description    string `gorm:"size:500"`
+	Category       string `gorm:"size:50"`
+	Parameters     string `gorm:"type:text"`
+	IsEnabled      bool   `gorm:"default:true"`
+	ReadOnlyHint   bool   `gorm:"default:false"`
+	IdempotentHint bool   `gorm:"default:false"`
+	CacheEnabled   *bool
+	CacheTTL       int `gorm:"default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+	Server         mcpServerV1    `gorm:"foreignKey:ServerID"`
+}
+
+func (mcpToolV1) TableName() string { return "mcp_tools" }
+
+type samplingConfigV1 struct {
+	ID            uint   `gorm:"primaryKey"`
+	Enabled       bool   `gorm:"default:false"`
+	BaseURL       string `gorm:"size:255"`
+	APIKey        string `gorm:"type:text"`
+	Model         string `gorm:"size:100"`
+	MaxTokens     int    `gorm:"default:1024"`
+	DefaultPolicy string `gorm:"size:20;default:'ask'"`
+	AskTimeout    int    `gorm:"default:60"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (samplingConfigV1) TableName() string { return "sampling_configs" }
+
+type samplingLogV1 struct {
+	ID         uint   `gorm:"primaryKey"`
+	ServerID   uint   `gorm:"index"`
+	Policy     string `gorm:"size:20"`
+	Decision   string `gorm:"size:20"`
+	Model      string `gorm:"size:100"`
+	Request    string `gorm:"type:text"`
+	Response   string `gorm:"type:text"`
+	Error      string `gorm:"type:text"`
+	DurationMs int64
+	CreatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+}
+
+func (samplingLogV1) TableName() string { return "sampling_logs" }
+
+type workspaceRootV1 struct {
+	ID        uint   `gorm:"primaryKey"`
+	ServerID  *uint  `gorm:"index"`
+	Name      string `gorm:"size:100"`
+	Path      string `gorm:"not null;size:1024"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (workspaceRootV1) TableName() string { return "workspace_roots" }
+
+type mcpServerLogV1 struct {
+	ID        uint      `gorm:"primaryKey"`
+	ServerID  uint      `gorm:"index"`
+	Level     string    `gorm:"size:20"`
+	LevelRank int       `gorm:"index"`
+	Logger    string    `gorm:"size:100"`
+	Data      string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (mcpServerLogV1) TableName() string { return "mcp_server_logs" }
+
+type backupScheduleV1 struct {
+	ID              uint `gorm:"primaryKey"`
+	Enabled         bool `gorm:"default:false"`
+	IntervalHours   int  `gorm:"default:24"`
+	Keep            int  `gorm:"default:7"`
+	IncludeSecrets  bool
+	IncludeHistory  bool
+	LastBackupAt    *time.Time
+	LastBackupError string `gorm:"type:text"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (backupScheduleV1) TableName() string { return "backup_schedules" }
+
+type secretV1 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"uniqueIndex;not null;size:100"`
+	Description string `gorm:"size:500"`
+	Value       string `gorm:"type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (secretV1) TableName() string { return "secrets" }
+
+// 以下为版本2新增的表结构快照
+
+type tagV2 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex;not null;size:50"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (tagV2) TableName() string { return "tags" }
+
+type mcpServerTagV2 struct {
+	MCPServerID uint `gorm:"primaryKey;autoIncrement:false"`
+	TagID       uint `gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (mcpServerTagV2) TableName() string { return "mcp_server_tags" }
+
+type mcpToolTagV2 struct {
+	MCPToolID uint `gorm:"primaryKey;autoIncrement:false"`
+	TagID     uint `gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (mcpToolTagV2) TableName() string { return "mcp_tool_tags" }
+
+// 以下为版本3新增的表结构快照
+
+type workspaceV3 struct {
+	ID             uint   `gorm:"primaryKey"`
+	Name           string `gorm:"uniqueIndex;not null;size:100"`
+	Description    string `gorm:"size:500"`
+	IsActive       bool
+	SamplingPolicy string `gorm:"size:20"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (workspaceV3) TableName() string { return "workspaces" }
+
+// 以下为版本4新增的表结构快照
+
+type auditLogV4 struct {
+	ID         uint      `gorm:"primaryKey"`
+	EntityType string    `gorm:"size:20;index"`
+	EntityID   uint      `gorm:"index"`
+	EntityName string    `gorm:"size:100"`
+	Action     string    `gorm:"size:20;index"`
+	Source     string    `gorm:"size:20;index"`
+	Before     string    `gorm:"type:text"`
+	After      string    `gorm:"type:text"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+func (auditLogV4) TableName() string { return "audit_logs" }
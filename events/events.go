@@ -0,0 +1,183 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Topic 事件主题
+type Topic string
+
+// 应用内发布的事件主题
+const (
+	TopicServerStatusChanged  Topic = "server.status_changed" // 服务器状态或启用状态变化，数据为 ServerStatusChanged
+	TopicToolsSynced          Topic = "tools.synced"          // 服务器工具发现或刷新完成，数据为 ToolsSynced
+	TopicToolCallStarted      Topic = "tool_call.started"     // 开始调用工具，数据为 ToolCallStarted
+	TopicToolCallProgress     Topic = "tool_call.progress"    // 工具调用进度通知，数据为 ToolCallProgress
+	TopicToolCallCompleted    Topic = "tool_call.completed"   // 工具调用结束，数据为 ToolCallCompleted
+	TopicSamplingPending      Topic = "sampling.pending"      // 有待确认的采样请求，数据为 models.SamplingPendingRequest
+	TopicElicitationRequested Topic = "elicitation.requested" // 有待处理的信息收集请求，数据为 models.ElicitationPendingRequest
+	TopicElicitationExpired   Topic = "elicitation.expired"   // 信息收集请求超时或取消，数据为请求ID
+	TopicWorkspaceSwitched    Topic = "workspace.switched"    // 切换了当前工作区，数据为 models.Workspace
+)
+
+// Topics 返回所有事件主题
+func Topics() []Topic {
+	return []Topic{
+		TopicServerStatusChanged,
+		TopicToolsSynced,
+		TopicToolCallStarted,
+		TopicToolCallProgress,
+		TopicToolCallCompleted,
+		TopicSamplingPending,
+		TopicElicitationRequested,
+		TopicElicitationExpired,
+		TopicWorkspaceSwitched,
+	}
+}
+
+// Event 发布到订阅者的事件
+type Event struct {
+	ID    uint64      `json:"id"`
+	Topic Topic       `json:"topic"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// ServerStatusChanged 服务器状态变化
+type ServerStatusChanged struct {
+	ServerID  uint   `json:"server_id"`
+	Status    string `json:"status"`
+	IsEnabled bool   `json:"is_enabled"`
+}
+
+// ToolsSynced 工具同步完成
+type ToolsSynced struct {
+	ServerID   uint   `json:"server_id"`
+	ToolsCount int    `json:"tools_count"`
+	Mode       string `json:"mode"` // discover, refresh
+	Duration   int64  `json:"duration_ms"`
+}
+
+// ToolCallStarted 开始调用工具
+type ToolCallStarted struct {
+	CallID   string `json:"call_id"`
+	ServerID uint   `json:"server_id"`
+	ToolID   uint   `json:"tool_id"`
+	ToolName string `json:"tool_name"`
+}
+
+// ToolCallProgress 工具调用进度，来自MCP服务器的 notifications/progress
+type ToolCallProgress struct {
+	CallID   string  `json:"call_id"`
+	ServerID uint    `json:"server_id"`
+	Progress float64 `json:"progress"`
+	Total    float64 `json:"total,omitempty"`
+	Message  string  `json:"message,omitempty"`
+}
+
+// ToolCallCompleted 工具调用结束
+type ToolCallCompleted struct {
+	CallID   string `json:"call_id"`
+	ServerID uint   `json:"server_id"`
+	ToolID   uint   `json:"tool_id"`
+	ToolName string `json:"tool_name"`
+	Cached   bool   `json:"cached"`
+	IsError  bool   `json:"is_error"`        // 工具返回了错误结果
+	Error    string `json:"error,omitempty"` // 调用失败的原因
+	Duration int64  `json:"duration_ms"`
+}
+
+// subscriberBuffer 每个订阅者的缓冲事件数，订阅者处理过慢时丢弃新事件
+const subscriberBuffer = 100
+
+// subscriber 事件订阅者
+type subscriber struct {
+	filters []string
+	ch      chan Event
+}
+
+// Bus 进程内的事件总线
+type Bus struct {
+	nextID      atomic.Uint64
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+// NewBus 创建事件总线
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*subscriber]struct{})}
+}
+
+// Publish 发布事件，不会阻塞发布者
+func (b *Bus) Publish(topic Topic, data interface{}) {
+	event := Event{
+		ID:    b.nextID.Add(1),
+		Topic: topic,
+		Time:  time.Now(),
+		Data:  data,
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subscribers {
+		if !Match(sub.filters, topic) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe 订阅事件，filters 为空时订阅所有主题，返回的取消函数必须调用以释放资源，取消后通道会被关闭
+func (b *Bus) Subscribe(filters ...string) (<-chan Event, func()) {
+	sub := &subscriber{
+		filters: filters,
+		ch:      make(chan Event, subscriberBuffer),
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			close(sub.ch)
+			b.mu.Unlock()
+		})
+	}
+	return sub.ch, cancel
+}
+
+// Match 判断主题是否匹配过滤条件，支持完整主题和 tool_call.* 形式的前缀匹配
+func Match(filters []string, topic Topic) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if filter == "*" || filter == string(topic) {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(filter, "*"); ok && strings.HasPrefix(string(topic), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidFilter 判断过滤条件是否能匹配到至少一个主题
+func ValidFilter(filter string) bool {
+	for _, topic := range Topics() {
+		if Match([]string{filter}, topic) {
+			return true
+		}
+	}
+	return false
+}
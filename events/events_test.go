@@ -0,0 +1,48 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBusSubscribeFilter 测试按主题过滤订阅和取消订阅
+func TestBusSubscribeFilter(t *testing.T) {
+	bus := NewBus()
+	toolCalls, cancel := bus.Subscribe("tool_call.*")
+
+	bus.Publish(TopicServerStatusChanged, ServerStatusChanged{ServerID: 1})
+	bus.Publish(TopicToolCallProgress, ToolCallProgress{CallID: "abc", Progress: 1})
+
+	select {
+	case event := <-toolCalls:
+		if event.Topic != TopicToolCallProgress {
+			t.Fatalf("收到了不匹配的主题: %s", event.Topic)
+		}
+		if data, ok := event.Data.(ToolCallProgress); !ok || data.CallID != "abc" {
+			t.Fatalf("事件数据不正确: %#v", event.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("未收到订阅的事件")
+	}
+
+	cancel()
+	if _, ok := <-toolCalls; ok {
+		t.Fatal("取消订阅后通道应被关闭")
+	}
+	// 取消后继续发布不应阻塞或出错
+	bus.Publish(TopicToolCallStarted, ToolCallStarted{})
+}
+
+// TestValidFilter 测试过滤条件校验
+func TestValidFilter(t *testing.T) {
+	for _, filter := range []string{"*", "tools.synced", "tool_call.*", "sampling.*"} {
+		if !ValidFilter(filter) {
+			t.Fatalf("过滤条件 %s 应有效", filter)
+		}
+	}
+	for _, filter := range []string{"unknown", "tool_call.finished", "foo.*"} {
+		if ValidFilter(filter) {
+			t.Fatalf("过滤条件 %s 应无效", filter)
+		}
+	}
+}
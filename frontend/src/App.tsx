@@ -1,4 +1,4 @@
-import React, { useState } from 'react';
+import React, { useEffect, useState } from 'react';
 import { 
   Button, 
   Input, 
@@ -11,13 +11,18 @@ import {
   Divider,
   Alert,
   Menu,
-  Layout
+  Layout,
+  Select
 } from 'antd';
-import { SendOutlined, ApiOutlined, DatabaseOutlined, HomeOutlined, ToolOutlined } from '@ant-design/icons';
+import { SendOutlined, ApiOutlined, DatabaseOutlined, HomeOutlined, ToolOutlined, FileTextOutlined } from '@ant-design/icons';
 import axios from 'axios';
-import { Greet } from "../wailsjs/go/main/App";
+import { Greet, ListWorkspaces, SwitchWorkspace } from "../wailsjs/go/main/App";
+import { EventsOn } from "../wailsjs/runtime/runtime";
+import { models } from "../wailsjs/go/models";
 import MCPServerList from './components/MCPServerList';
 import MCPTools from './pages/MCPTools';
+import AppLogs from './pages/AppLogs';
+import { getApiBaseURL, getApiHeaders } from './services/apiBase';
 import './App.css';
 
 const { Title, Text } = Typography;
@@ -34,6 +39,37 @@ function App() {
   const [response, setResponse] = useState('');
   const [wailsName, setWailsName] = useState('');
   const [wailsResult, setWailsResult] = useState("请在下方输入您的姓名 👇");
+  const [workspaces, setWorkspaces] = useState<models.Workspace[]>([]);
+  const [activeWorkspace, setActiveWorkspace] = useState<number>();
+
+  /**
+   * 加载工作区列表，切换工作区后重新加载，页面内容按工作区重新挂载
+   */
+  const loadWorkspaces = async () => {
+    try {
+      const list = await ListWorkspaces();
+      setWorkspaces(list);
+      setActiveWorkspace(list.find((workspace) => workspace.is_active)?.id);
+    } catch (error) {
+      console.error('Error loading workspaces:', error);
+    }
+  };
+
+  useEffect(() => {
+    loadWorkspaces();
+    return EventsOn('workspace.switched', loadWorkspaces);
+  }, []);
+
+  /**
+   * 切换当前工作区
+   */
+  const handleSwitchWorkspace = async (id: number) => {
+    try {
+      await SwitchWorkspace(id);
+    } catch (error) {
+      antdMessage.error(`切换工作区失败: ${error}`);
+    }
+  };
 
   /**
    * 调用Gin后端API的Hello接口
@@ -46,12 +82,14 @@ function App() {
 
     setLoading(true);
     try {
-      const response = await axios.post('http://localhost:8080/api/hello', {
+      const response = await axios.post(`${await getApiBaseURL()}/hello`, {
         message: message
+      }, {
+        headers: await getApiHeaders()
       });
 
       if (response.data.success) {
-        setResponse(response.data.response);
+        setResponse(response.data.data.response);
         antdMessage.success('消息发送成功！');
       } else {
         antdMessage.error('发送失败');
@@ -183,7 +221,7 @@ function App() {
    */
   const renderMCPServerPage = () => (
     <div style={{ padding: '0 24px' }}>
-      <MCPServerList />
+      <MCPServerList key={activeWorkspace} />
     </div>
   );
 
@@ -192,7 +230,16 @@ function App() {
    */
   const renderMCPToolsPage = () => (
     <div style={{ padding: '0 24px' }}>
-      <MCPTools />
+      <MCPTools key={activeWorkspace} />
+    </div>
+  );
+
+  /**
+   * 渲染应用日志页面
+   */
+  const renderLogsPage = () => (
+    <div style={{ padding: '0 24px' }}>
+      <AppLogs />
     </div>
   );
 
@@ -205,6 +252,8 @@ function App() {
         return renderMCPServerPage();
       case 'mcp-tools':
         return renderMCPToolsPage();
+      case 'logs':
+        return renderLogsPage();
       default:
         return renderHomePage();
     }
@@ -239,8 +288,23 @@ function App() {
                 icon: <ToolOutlined />,
                 label: 'MCP Tools 管理',
               },
+              {
+                key: 'logs',
+                icon: <FileTextOutlined />,
+                label: '应用日志',
+              },
             ]}
           />
+          <Select
+            value={activeWorkspace}
+            onChange={handleSwitchWorkspace}
+            placeholder="选择工作区"
+            style={{ width: 180 }}
+            options={workspaces.map((workspace) => ({
+              value: workspace.id,
+              label: workspace.name,
+            }))}
+          />
         </div>
       </Header>
       <Content style={{ padding: '24px', backgroundColor: '#f5f5f5' }}>
@@ -66,7 +66,7 @@ const MCPServerForm: React.FC<MCPServerFormProps> = ({
       }
 
       // 解析标签
-      const parsedTags = server.tags ? server.tags.split(',').map(tag => tag.trim()).filter(Boolean) : [];
+      const parsedTags = (server.tags || []).map(tag => tag.name);
 
       // 设置表单值
       form.setFieldsValue({
@@ -162,7 +162,7 @@ const MCPServerForm: React.FC<MCPServerFormProps> = ({
         url: values.url,
         auth_type: values.auth_type,
         auth_config: JSON.stringify(authConfig),
-        tags: tags.join(','),
+        tags,
       };
 
       if (server) {
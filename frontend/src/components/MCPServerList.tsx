@@ -27,16 +27,19 @@ import {
   LinkOutlined,
   TagsOutlined,
   SyncOutlined,
+  ApiOutlined,
 } from '@ant-design/icons';
 import type { ColumnsType, TableProps } from 'antd/es/table';
 import type {
   MCPServer,
+  MCPTag,
   MCPServerListRequest,
   MCPServerListResponse,
   MCPServerListState
 } from '../types/mcpServer';
 import { MCPServerService } from '../services/mcpServerService';
 import MCPServerForm from './MCPServerForm';
+import TrafficInspector from './TrafficInspector';
 
 const { Search } = Input;
 const { Option } = Select;
@@ -66,6 +69,7 @@ const MCPServerList: React.FC = () => {
   // 表单相关状态
   const [formVisible, setFormVisible] = useState(false);
   const [editingServer, setEditingServer] = useState<MCPServer | null>(null);
+  const [trafficServer, setTrafficServer] = useState<MCPServer | null>(null);
 
   /**
    * 加载服务器列表
@@ -256,15 +260,14 @@ const MCPServerList: React.FC = () => {
   /**
    * 渲染标签
    */
-  const renderTags = (tags: string) => {
-    if (!tags) return null;
+  const renderTags = (tags: MCPTag[]) => {
+    if (!tags || tags.length === 0) return null;
 
-    const tagList = tags.split(',').map(tag => tag.trim()).filter(Boolean);
     return (
       <Space size={4} wrap>
-        {tagList.map((tag, index) => (
-          <Tag key={index} icon={<TagsOutlined />}>
-            {tag}
+        {tags.map((tag) => (
+          <Tag key={tag.id} icon={<TagsOutlined />}>
+            {tag.name}
           </Tag>
         ))}
       </Space>
@@ -351,6 +354,13 @@ const MCPServerList: React.FC = () => {
               disabled={record.status !== 'active' || !record.is_enabled}
             />
           </Tooltip>
+          <Tooltip title="流量查看器">
+            <Button
+              type="text"
+              icon={<ApiOutlined />}
+              onClick={() => setTrafficServer(record)}
+            />
+          </Tooltip>
           <Tooltip title="编辑">
             <Button
               type="text"
@@ -493,6 +503,14 @@ const MCPServerList: React.FC = () => {
           onCancel={() => setFormVisible(false)}
         />
       </Modal>
+
+      {/* JSON-RPC 流量查看器 */}
+      <TrafficInspector
+        serverId={trafficServer?.id}
+        serverName={trafficServer?.name}
+        open={trafficServer !== null}
+        onClose={() => setTrafficServer(null)}
+      />
     </Card>
   );
 };
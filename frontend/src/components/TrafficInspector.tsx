@@ -0,0 +1,270 @@
+import React, { useEffect, useState } from 'react';
+import { Button, Drawer, Input, Popconfirm, Select, Space, Switch, Table, Tag, Typography, message } from 'antd';
+import { ArrowDownOutlined, ArrowUpOutlined, DeleteOutlined, DownloadOutlined, ReloadOutlined } from '@ant-design/icons';
+import type { ColumnsType } from 'antd/es/table';
+import {
+  clearTraffic,
+  followTraffic,
+  listTraffic,
+  setTrafficCapture,
+  toJSONL,
+  type TrafficEntry,
+  type TrafficKind,
+  type TrafficStatus,
+} from '../services/trafficApi';
+
+const { Text } = Typography;
+
+// 实时跟踪时列表中保留的最大记录数
+const MAX_TAIL_ENTRIES = 1000;
+
+const KIND_COLORS: Record<TrafficKind, string> = {
+  request: 'blue',
+  response: 'green',
+  notification: 'purple',
+};
+
+interface TrafficInspectorProps {
+  serverId?: number;
+  serverName?: string;
+  open: boolean;
+  onClose: () => void;
+}
+
+/**
+ * JSON-RPC 流量查看器
+ * 开启抓取后记录与服务器之间收发的每条消息，可按类型、方法和请求ID过滤，并导出为JSONL
+ */
+const TrafficInspector: React.FC<TrafficInspectorProps> = ({ serverId, serverName, open, onClose }) => {
+  const [loading, setLoading] = useState(false);
+  const [entries, setEntries] = useState<TrafficEntry[]>([]);
+  const [status, setStatus] = useState<TrafficStatus>();
+  const [kind, setKind] = useState<TrafficKind>();
+  const [method, setMethod] = useState('');
+  const [requestId, setRequestId] = useState('');
+  const [follow, setFollow] = useState(false);
+
+  const filters = {
+    kind,
+    method: method || undefined,
+    request_id: requestId || undefined,
+  };
+
+  /**
+   * 加载流量记录
+   */
+  const loadTraffic = async () => {
+    if (!serverId) {
+      return;
+    }
+    setLoading(true);
+    try {
+      const response = await listTraffic(serverId, { ...filters, limit: MAX_TAIL_ENTRIES });
+      setEntries(response.entries || []);
+      setStatus(response);
+    } catch (error) {
+      console.error('加载流量记录失败:', error);
+      message.error('加载流量记录失败');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    if (open) {
+      loadTraffic();
+    } else {
+      setFollow(false);
+    }
+  }, [open, serverId, kind, method, requestId]);
+
+  // 实时跟踪：新记录追加到列表末尾
+  useEffect(() => {
+    if (!follow || !serverId) {
+      return;
+    }
+    let stop: (() => void) | undefined;
+    let cancelled = false;
+    followTraffic(serverId, filters, (entry) => {
+      setEntries((current) => [...current, entry].slice(-MAX_TAIL_ENTRIES));
+    }).then((close) => {
+      if (cancelled) {
+        close();
+      } else {
+        stop = close;
+      }
+    }).catch((error) => {
+      console.error('订阅流量记录失败:', error);
+      message.error('订阅流量记录失败');
+      setFollow(false);
+    });
+    return () => {
+      cancelled = true;
+      stop?.();
+    };
+  }, [follow, serverId, kind, method, requestId]);
+
+  /**
+   * 开启或关闭抓取
+   */
+  const handleCapture = async (enabled: boolean) => {
+    if (!serverId) {
+      return;
+    }
+    try {
+      setStatus(await setTrafficCapture(serverId, enabled));
+      message.success(enabled ? '已开启流量抓取，新建立的连接将被记录' : '已关闭流量抓取');
+    } catch (error) {
+      console.error('设置流量抓取失败:', error);
+      message.error('设置流量抓取失败');
+    }
+  };
+
+  /**
+   * 清空流量记录
+   */
+  const handleClear = async () => {
+    if (!serverId) {
+      return;
+    }
+    try {
+      const removed = await clearTraffic(serverId);
+      message.success(`已清空 ${removed} 条流量记录`);
+      loadTraffic();
+    } catch (error) {
+      console.error('清空流量记录失败:', error);
+      message.error('清空流量记录失败');
+    }
+  };
+
+  /**
+   * 将当前过滤条件下的记录导出为JSONL文件
+   */
+  const handleExport = async () => {
+    if (!serverId) {
+      return;
+    }
+    try {
+      const response = await listTraffic(serverId, { ...filters, limit: 5000 });
+      const blob = new Blob([toJSONL(response.entries || [])], { type: 'application/x-ndjson' });
+      const link = document.createElement('a');
+      link.href = URL.createObjectURL(blob);
+      link.download = `mcp-traffic-${serverId}.jsonl`;
+      link.click();
+      URL.revokeObjectURL(link.href);
+    } catch (error) {
+      console.error('导出流量记录失败:', error);
+      message.error('导出流量记录失败');
+    }
+  };
+
+  const columns: ColumnsType<TrafficEntry> = [
+    {
+      title: '时间',
+      dataIndex: 'time',
+      width: 110,
+      render: (time: string) => new Date(time).toLocaleTimeString(),
+    },
+    {
+      title: '方向',
+      dataIndex: 'direction',
+      width: 60,
+      render: (direction: string) => (direction === 'outgoing'
+        ? <ArrowUpOutlined style={{ color: '#1677ff' }} title="发往服务器" />
+        : <ArrowDownOutlined style={{ color: '#52c41a' }} title="来自服务器" />),
+    },
+    {
+      title: '类型',
+      dataIndex: 'kind',
+      width: 100,
+      render: (value: TrafficKind) => <Tag color={KIND_COLORS[value]}>{value}</Tag>,
+    },
+    {
+      title: '方法',
+      dataIndex: 'method',
+      render: (value: string, entry) => (
+        <Space size={4}>
+          <Text code>{value || '-'}</Text>
+          {entry.rpc_id && <Text type="secondary">#{entry.rpc_id}</Text>}
+          {entry.error && <Tag color="red">{entry.error}</Tag>}
+        </Space>
+      ),
+    },
+    {
+      title: '耗时',
+      dataIndex: 'duration_ms',
+      width: 90,
+      render: (value?: number) => (value !== undefined ? `${value.toFixed(1)} ms` : ''),
+    },
+    {
+      title: '请求ID',
+      dataIndex: 'request_id',
+      width: 160,
+      render: (value?: string) => value && (
+        <Text type="secondary" style={{ cursor: 'pointer', fontSize: 12 }} onClick={() => setRequestId(value)}>
+          {value}
+        </Text>
+      ),
+    },
+  ];
+
+  return (
+    <Drawer title={`流量查看器${serverName ? ` - ${serverName}` : ''}`} open={open} onClose={onClose} width={960} destroyOnClose>
+      <Space direction="vertical" size="middle" style={{ width: '100%' }}>
+        <Space wrap>
+          <Space>
+            <Switch checked={status?.enabled} onChange={handleCapture} />
+            <Text>抓取流量</Text>
+          </Space>
+          <Select
+            allowClear
+            placeholder="消息类型"
+            value={kind}
+            onChange={setKind}
+            style={{ width: 130 }}
+            options={(['request', 'response', 'notification'] as TrafficKind[]).map((value) => ({ value, label: value }))}
+          />
+          <Input.Search allowClear placeholder="方法名前缀" onSearch={setMethod} style={{ width: 180 }} />
+          <Input
+            allowClear
+            placeholder="请求ID"
+            value={requestId}
+            onChange={(event) => setRequestId(event.target.value)}
+            style={{ width: 180 }}
+          />
+          <Space>
+            <Switch checked={follow} onChange={setFollow} />
+            <Text>实时跟踪</Text>
+          </Space>
+          <Button icon={<ReloadOutlined />} onClick={loadTraffic}>刷新</Button>
+          <Button icon={<DownloadOutlined />} onClick={handleExport}>导出JSONL</Button>
+          <Popconfirm title="确定要清空流量记录吗？" onConfirm={handleClear} okText="确定" cancelText="取消">
+            <Button danger icon={<DeleteOutlined />}>清空</Button>
+          </Popconfirm>
+        </Space>
+        {status && (
+          <Text type="secondary">
+            缓冲区 {status.count}/{status.capacity} 条{status.dropped > 0 ? `，已覆盖 ${status.dropped} 条最早的记录` : ''}
+          </Text>
+        )}
+        <Table
+          rowKey="id"
+          size="small"
+          loading={loading}
+          columns={columns}
+          dataSource={entries}
+          pagination={{ pageSize: 50, showSizeChanger: false }}
+          expandable={{
+            expandedRowRender: (entry) => (
+              <pre style={{ margin: 0, maxHeight: 400, overflow: 'auto', fontSize: 12 }}>
+                {entry.truncated ? entry.message : JSON.stringify(entry.message, null, 2)}
+              </pre>
+            ),
+          }}
+        />
+      </Space>
+    </Drawer>
+  );
+};
+
+export default TrafficInspector;
@@ -0,0 +1,176 @@
+import React, { useEffect, useState } from 'react';
+import { Card, DatePicker, Input, Select, Space, Switch, Table, Tag, Typography, message } from 'antd';
+import { FileTextOutlined } from '@ant-design/icons';
+import type { ColumnsType } from 'antd/es/table';
+import type { Dayjs } from 'dayjs';
+import { followAppLogs, listAppLogs, type AppLogEntry, type AppLogLevel } from '../services/appLogApi';
+
+const { Title, Text } = Typography;
+const { RangePicker } = DatePicker;
+
+// 实时跟踪时列表中保留的最大日志条数
+const MAX_TAIL_ENTRIES = 1000;
+
+const LEVEL_COLORS: Record<AppLogLevel, string> = {
+  debug: 'default',
+  info: 'blue',
+  warn: 'orange',
+  error: 'red',
+};
+
+/**
+ * 应用日志页面
+ * 按级别、子系统、时间和关键字查询日志文件，开启实时跟踪后持续显示新写入的日志
+ */
+const AppLogs: React.FC = () => {
+  const [loading, setLoading] = useState(false);
+  const [logs, setLogs] = useState<AppLogEntry[]>([]);
+  const [total, setTotal] = useState(0);
+  const [subsystems, setSubsystems] = useState<string[]>([]);
+  const [level, setLevel] = useState<AppLogLevel>();
+  const [subsystem, setSubsystem] = useState<string>();
+  const [search, setSearch] = useState('');
+  const [range, setRange] = useState<[Dayjs | null, Dayjs | null] | null>(null);
+  const [follow, setFollow] = useState(false);
+
+  const filters = {
+    level,
+    subsystem,
+    search: search || undefined,
+    since: range?.[0]?.toISOString(),
+    until: range?.[1]?.toISOString(),
+  };
+
+  /**
+   * 加载日志
+   */
+  const loadLogs = async () => {
+    setLoading(true);
+    try {
+      const response = await listAppLogs({ ...filters, limit: 500 });
+      setLogs(response.logs || []);
+      setTotal(response.total);
+      setSubsystems(response.subsystems || []);
+    } catch (error) {
+      console.error('加载日志失败:', error);
+      message.error('加载日志失败');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    loadLogs();
+  }, [level, subsystem, search, range]);
+
+  // 实时跟踪：新日志插入列表顶部，截止时间对实时日志无意义
+  useEffect(() => {
+    if (!follow) {
+      return;
+    }
+    let stop: (() => void) | undefined;
+    let cancelled = false;
+    followAppLogs({ ...filters, until: undefined }, (entry) => {
+      setLogs((current) => [entry, ...current].slice(0, MAX_TAIL_ENTRIES));
+      setTotal((current) => current + 1);
+    }).then((close) => {
+      if (cancelled) {
+        close();
+      } else {
+        stop = close;
+      }
+    }).catch((error) => {
+      console.error('订阅日志失败:', error);
+      message.error('订阅日志失败');
+      setFollow(false);
+    });
+    return () => {
+      cancelled = true;
+      stop?.();
+    };
+  }, [follow, level, subsystem, search, range]);
+
+  const columns: ColumnsType<AppLogEntry> = [
+    {
+      title: '时间',
+      dataIndex: 'time',
+      width: 200,
+      render: (time: string) => new Date(time).toLocaleString(),
+    },
+    {
+      title: '级别',
+      dataIndex: 'level',
+      width: 80,
+      render: (value: AppLogLevel) => <Tag color={LEVEL_COLORS[value]}>{value.toUpperCase()}</Tag>,
+    },
+    {
+      title: '子系统',
+      dataIndex: 'subsystem',
+      width: 100,
+    },
+    {
+      title: '消息',
+      dataIndex: 'msg',
+      render: (msg: string, entry) => (
+        <div>
+          <Text>{msg}</Text>
+          {entry.attrs && (
+            <div>
+              {Object.entries(entry.attrs).map(([key, value]) => (
+                <Text key={key} type="secondary" style={{ marginRight: 12, fontFamily: 'monospace', fontSize: 12 }}>
+                  {key}={typeof value === 'string' ? value : JSON.stringify(value)}
+                </Text>
+              ))}
+            </div>
+          )}
+        </div>
+      ),
+    },
+  ];
+
+  return (
+    <Card>
+      <Space direction="vertical" size="middle" style={{ width: '100%' }}>
+        <Title level={4} style={{ margin: 0 }}>
+          <FileTextOutlined style={{ marginRight: 8 }} />
+          应用日志
+        </Title>
+        <Space wrap>
+          <Select
+            allowClear
+            placeholder="最低级别"
+            value={level}
+            onChange={setLevel}
+            style={{ width: 120 }}
+            options={(['debug', 'info', 'warn', 'error'] as AppLogLevel[]).map((value) => ({ value, label: value }))}
+          />
+          <Select
+            allowClear
+            placeholder="子系统"
+            value={subsystem}
+            onChange={setSubsystem}
+            style={{ width: 140 }}
+            options={subsystems.map((value) => ({ value, label: value }))}
+          />
+          <Input.Search allowClear placeholder="搜索消息或属性" onSearch={setSearch} style={{ width: 240 }} />
+          <RangePicker showTime value={range} onChange={(value) => setRange(value)} />
+          <Space>
+            <Switch checked={follow} onChange={setFollow} />
+            <Text>实时跟踪</Text>
+          </Space>
+        </Space>
+        <Text type="secondary">共 {total} 条，显示最近 {logs.length} 条</Text>
+        <Table
+          rowKey={(entry, index) => `${entry.time}-${index}`}
+          size="small"
+          loading={loading}
+          columns={columns}
+          dataSource={logs}
+          pagination={{ pageSize: 50, showSizeChanger: false }}
+        />
+      </Space>
+    </Card>
+  );
+};
+
+export default AppLogs;
@@ -0,0 +1,108 @@
+import { isAxiosError, type InternalAxiosRequestConfig } from 'axios';
+import { GetAPIBaseURL, GetAPIToken } from '../../wailsjs/go/main/App';
+
+// 后端不可用（如在浏览器中单独调试前端）时使用的默认地址
+const DEFAULT_API_BASE_URL = 'http://localhost:8080/api';
+
+let baseURLPromise: Promise<string> | null = null;
+let tokenPromise: Promise<string> | null = null;
+
+/**
+ * 获取内置API服务的实际地址
+ * 默认端口被占用时后端会改用空闲端口，实际地址通过Wails绑定获取
+ */
+export function getApiBaseURL(): Promise<string> {
+  if (!baseURLPromise) {
+    baseURLPromise = (async () => {
+      try {
+        const url = await GetAPIBaseURL();
+        return url || DEFAULT_API_BASE_URL;
+      } catch {
+        return DEFAULT_API_BASE_URL;
+      }
+    })();
+  }
+  return baseURLPromise;
+}
+
+/**
+ * 获取本次启动生成的API令牌，后端要求所有请求携带该令牌
+ */
+export function getApiToken(): Promise<string> {
+  if (!tokenPromise) {
+    tokenPromise = GetAPIToken().catch(() => '');
+  }
+  return tokenPromise;
+}
+
+/**
+ * 获取访问API所需的请求头
+ */
+export async function getApiHeaders(): Promise<Record<string, string>> {
+  return {
+    Authorization: `Bearer ${await getApiToken()}`,
+    'X-Request-Source': 'ui',
+  };
+}
+
+/**
+ * axios请求拦截器：在发送请求前填入实际的API地址和令牌，并标识请求来自桌面界面（用于审计日志）
+ */
+export async function withApiBaseURL(config: InternalAxiosRequestConfig): Promise<InternalAxiosRequestConfig> {
+  config.baseURL = await getApiBaseURL();
+  for (const [name, value] of Object.entries(await getApiHeaders())) {
+    config.headers.set(name, value);
+  }
+  return config;
+}
+
+/**
+ * 后端返回的错误，code 为稳定的错误码（如 server_not_found）
+ */
+export class ApiError extends Error {
+  code?: string;
+  status?: number;
+
+  constructor(message: string, code?: string, status?: number) {
+    super(message);
+    this.name = 'ApiError';
+    this.code = code;
+    this.status = status;
+  }
+}
+
+/**
+ * axios响应错误拦截器：将后端统一的错误响应转换为 ApiError
+ */
+export function toApiError(error: unknown): Promise<never> {
+  console.error('API请求错误:', error);
+  if (isAxiosError(error) && error.response?.data?.message) {
+    const { message, code } = error.response.data;
+    return Promise.reject(new ApiError(message, code, error.response.status));
+  }
+  return Promise.reject(error);
+}
+
+/**
+ * 是否运行在Wails桌面窗口中，此时直接调用Go绑定方法，不经过HTTP API
+ * HTTP API可在设置中关闭，在浏览器中单独调试前端时仍使用HTTP API
+ */
+export function hasBindings(): boolean {
+  return typeof window !== 'undefined' && Boolean((window as any).go?.main?.App);
+}
+
+/**
+ * 调用Wails绑定方法，将后端返回的错误（{ code, message }）转换为 ApiError
+ */
+export async function callBinding<T>(call: () => Promise<T>): Promise<T> {
+  try {
+    return await call();
+  } catch (error) {
+    console.error('调用后端方法失败:', error);
+    if (error && typeof error === 'object' && 'message' in error) {
+      const { message, code } = error as { message: string; code?: string };
+      throw new ApiError(message, code);
+    }
+    throw new ApiError(String(error));
+  }
+}
@@ -0,0 +1,74 @@
+import axios from 'axios';
+import { callBinding, getApiBaseURL, getApiToken, hasBindings, toApiError, withApiBaseURL } from './apiBase';
+import { ListAppLogs } from '../../wailsjs/go/main/App';
+import { models } from '../../wailsjs/go/models';
+
+// 创建axios实例
+const api = axios.create({
+  timeout: 10000,
+});
+
+// 请求拦截器：使用后端实际监听的地址
+api.interceptors.request.use(withApiBaseURL);
+
+// 响应拦截器
+api.interceptors.response.use((response) => response, toApiError);
+
+export type AppLogLevel = 'debug' | 'info' | 'warn' | 'error';
+
+export interface AppLogListRequest {
+  level?: AppLogLevel;
+  subsystem?: string;
+  search?: string;
+  since?: string;
+  until?: string;
+  limit?: number;
+}
+
+export interface AppLogEntry {
+  time: string;
+  level: AppLogLevel;
+  subsystem: string;
+  msg: string;
+  attrs?: Record<string, any>;
+}
+
+export interface AppLogListResponse {
+  total: number;
+  logs: AppLogEntry[];
+  subsystems: string[];
+}
+
+/**
+ * 查询应用日志，按时间从新到旧返回
+ */
+export async function listAppLogs(params: AppLogListRequest = {}): Promise<AppLogListResponse> {
+  if (hasBindings()) {
+    const request = models.AppLogListRequest.createFrom({ limit: 200, ...params });
+    return callBinding(() => ListAppLogs(request)) as Promise<unknown> as Promise<AppLogListResponse>;
+  }
+
+  const response = await api.get('/logs', { params });
+  return response.data.data;
+}
+
+/**
+ * 实时订阅新写入的应用日志（SSE），返回取消订阅的函数
+ */
+export async function followAppLogs(
+  params: AppLogListRequest,
+  onLog: (entry: AppLogEntry) => void,
+): Promise<() => void> {
+  const query = new URLSearchParams({ follow: 'true', token: await getApiToken() });
+  for (const [key, value] of Object.entries(params)) {
+    if (value !== undefined && value !== '') {
+      query.set(key, String(value));
+    }
+  }
+
+  const source = new EventSource(`${await getApiBaseURL()}/logs?${query}`);
+  source.addEventListener('log', (event) => {
+    onLog(JSON.parse((event as MessageEvent).data));
+  });
+  return () => source.close();
+}
@@ -1,4 +1,18 @@
 import axios from 'axios';
+import { callBinding, hasBindings, toApiError, withApiBaseURL } from './apiBase';
+import {
+  CreateServer,
+  DeleteServer,
+  DiscoverTools,
+  GetServer,
+  GetServerTags,
+  ListServers,
+  RefreshTools,
+  ToggleServer,
+  UpdateServer,
+  UpdateServerStatus,
+} from '../../wailsjs/go/main/App';
+import { models } from '../../wailsjs/go/models';
 import type {
   MCPServer,
   MCPServerCreateRequest,
@@ -11,21 +25,17 @@ import type {
 
 // 创建axios实例
 const api = axios.create({
-  baseURL: 'http://localhost:8080/api',
   timeout: 10000,
   headers: {
     'Content-Type': 'application/json',
   },
 });
 
+// 请求拦截器：使用后端实际监听的地址
+api.interceptors.request.use(withApiBaseURL);
+
 // 响应拦截器
-api.interceptors.response.use(
-  (response) => response,
-  (error) => {
-    console.error('API请求错误:', error);
-    return Promise.reject(error);
-  }
-);
+api.interceptors.response.use((response) => response, toApiError);
 
 /**
  * MCP Server API 服务类
@@ -35,12 +45,18 @@ export class MCPServerService {
    * 获取MCP服务器列表
    */
   static async getList(params: MCPServerListRequest = {}): Promise<MCPServerListResponse> {
+    if (hasBindings()) {
+      const { tags, ...rest } = params;
+      const request = models.MCPServerListRequest.createFrom({ ...rest, tags: tags ? [tags] : undefined });
+      return callBinding(() => ListServers(request)) as Promise<unknown> as Promise<MCPServerListResponse>;
+    }
+
     const response = await api.get<ApiResponse<MCPServerListResponse>>('/mcp-servers', {
       params,
     });
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '获取服务器列表失败');
+      throw new Error(response.data.message || '获取服务器列表失败');
     }
     
     return response.data.data!;
@@ -50,10 +66,14 @@ export class MCPServerService {
    * 根据ID获取MCP服务器详情
    */
   static async getById(id: number): Promise<MCPServer> {
+    if (hasBindings()) {
+      return callBinding(() => GetServer(id)) as Promise<unknown> as Promise<MCPServer>;
+    }
+
     const response = await api.get<ApiResponse<MCPServer>>(`/mcp-servers/${id}`);
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '获取服务器详情失败');
+      throw new Error(response.data.message || '获取服务器详情失败');
     }
     
     return response.data.data!;
@@ -63,10 +83,15 @@ export class MCPServerService {
    * 创建MCP服务器
    */
   static async create(data: MCPServerCreateRequest): Promise<MCPServer> {
+    if (hasBindings()) {
+      const request = models.MCPServerCreateRequest.createFrom(data);
+      return callBinding(() => CreateServer(request)) as Promise<unknown> as Promise<MCPServer>;
+    }
+
     const response = await api.post<ApiResponse<MCPServer>>('/mcp-servers', data);
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '创建服务器失败');
+      throw new Error(response.data.message || '创建服务器失败');
     }
     
     return response.data.data!;
@@ -76,10 +101,15 @@ export class MCPServerService {
    * 更新MCP服务器
    */
   static async update(id: number, data: MCPServerUpdateRequest): Promise<MCPServer> {
+    if (hasBindings()) {
+      const request = models.MCPServerUpdateRequest.createFrom(data);
+      return callBinding(() => UpdateServer(id, request)) as Promise<unknown> as Promise<MCPServer>;
+    }
+
     const response = await api.put<ApiResponse<MCPServer>>(`/mcp-servers/${id}`, data);
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '更新服务器失败');
+      throw new Error(response.data.message || '更新服务器失败');
     }
     
     return response.data.data!;
@@ -89,10 +119,14 @@ export class MCPServerService {
    * 删除MCP服务器
    */
   static async delete(id: number): Promise<void> {
+    if (hasBindings()) {
+      return callBinding(() => DeleteServer(id));
+    }
+
     const response = await api.delete<ApiResponse>(`/mcp-servers/${id}`);
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '删除服务器失败');
+      throw new Error(response.data.message || '删除服务器失败');
     }
   }
 
@@ -100,10 +134,14 @@ export class MCPServerService {
    * 更新服务器状态
    */
   static async updateStatus(id: number, status: string): Promise<void> {
+    if (hasBindings()) {
+      return callBinding(() => UpdateServerStatus(id, status));
+    }
+
     const response = await api.put<ApiResponse>(`/mcp-servers/${id}/status`, { status });
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '更新服务器状态失败');
+      throw new Error(response.data.message || '更新服务器状态失败');
     }
   }
 
@@ -111,10 +149,14 @@ export class MCPServerService {
    * 切换服务器启用状态
    */
   static async toggle(id: number): Promise<MCPServer> {
+    if (hasBindings()) {
+      return callBinding(() => ToggleServer(id)) as Promise<unknown> as Promise<MCPServer>;
+    }
+
     const response = await api.put<ApiResponse<MCPServer>>(`/mcp-servers/${id}/toggle`);
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '切换服务器状态失败');
+      throw new Error(response.data.message || '切换服务器状态失败');
     }
     
     return response.data.data!;
@@ -124,10 +166,14 @@ export class MCPServerService {
    * 获取所有标签
    */
   static async getTags(): Promise<string[]> {
+    if (hasBindings()) {
+      return (await callBinding(() => GetServerTags())) || [];
+    }
+
     const response = await api.get<ApiResponse<string[]>>('/mcp-servers/tags');
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '获取标签失败');
+      throw new Error(response.data.message || '获取标签失败');
     }
     
     return response.data.data || [];
@@ -155,6 +201,11 @@ export class MCPServerService {
    */
   static async discoverTools(id: number): Promise<{ success: boolean; message?: string; tools_count?: number }> {
     try {
+      if (hasBindings()) {
+        const response = await callBinding(() => DiscoverTools(id));
+        return { success: true, tools_count: response.tools?.length ?? 0 };
+      }
+
       const response = await api.post<ApiResponse<{ tools_count: number }>>(`/mcp-servers/${id}/discover-tools`);
       
       return {
@@ -163,10 +214,9 @@ export class MCPServerService {
         tools_count: response.data.data?.tools_count,
       };
     } catch (error) {
-      console.error('工具发现失败:', error);
       return {
         success: false,
-        message: '工具发现失败',
+        message: (error as Error).message || '工具发现失败',
       };
     }
   }
@@ -176,6 +226,11 @@ export class MCPServerService {
    */
   static async refreshTools(id: number): Promise<{ success: boolean; message?: string; tools?: any[] }> {
     try {
+      if (hasBindings()) {
+        const response = await callBinding(() => RefreshTools(id));
+        return { success: true, tools: response.tools };
+      }
+
       const response = await api.post<ApiResponse<{ tools: any[] }>>(`/mcp-tools/refresh/${id}`);
       
       return {
@@ -184,10 +239,9 @@ export class MCPServerService {
         tools: response.data.data?.tools,
       };
     } catch (error) {
-      console.error('刷新工具失败:', error);
       return {
         success: false,
-        message: '刷新工具失败',
+        message: (error as Error).message || '刷新工具失败',
       };
     }
   }
@@ -1,22 +1,21 @@
 import axios from 'axios';
+import { callBinding, hasBindings, toApiError, withApiBaseURL } from './apiBase';
+import { BatchUpdateTools, GetToolCategories, ListTools, UpdateTool } from '../../wailsjs/go/main/App';
+import { models } from '../../wailsjs/go/models';
 
 // 创建axios实例
 const api = axios.create({
-  baseURL: 'http://localhost:8080/api',
   timeout: 10000,
   headers: {
     'Content-Type': 'application/json',
   },
 });
 
+// 请求拦截器：使用后端实际监听的地址
+api.interceptors.request.use(withApiBaseURL);
+
 // 响应拦截器
-api.interceptors.response.use(
-  (response) => response,
-  (error) => {
-    console.error('API请求错误:', error);
-    return Promise.reject(error);
-  }
-);
+api.interceptors.response.use((response) => response, toApiError);
 
 export interface MCPToolListRequest {
   server_id?: number;
@@ -57,6 +56,7 @@ export interface MCPToolListResponse {
 
 export interface ApiResponse<T = any> {
   success: boolean;
+  code?: string;
   data?: T;
   message?: string;
   error?: string;
@@ -70,12 +70,18 @@ export class MCPToolService {
    * 获取工具列表
    */
   static async getList(params: MCPToolListRequest = {}): Promise<MCPToolListResponse> {
+    if (hasBindings()) {
+      const { is_enabled, ...rest } = params;
+      const request = models.MCPToolListRequest.createFrom({ ...rest, enabled: is_enabled });
+      return callBinding(() => ListTools(request)) as Promise<unknown> as Promise<MCPToolListResponse>;
+    }
+
     const response = await api.get<ApiResponse<MCPToolListResponse>>('/mcp-tools', {
       params,
     });
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '获取工具列表失败');
+      throw new Error(response.data.message || '获取工具列表失败');
     }
     
     return response.data.data!;
@@ -85,10 +91,14 @@ export class MCPToolService {
    * 更新工具
    */
   static async update(id: number, data: MCPToolUpdateRequest): Promise<void> {
+    if (hasBindings()) {
+      return callBinding(() => UpdateTool(id, models.MCPToolUpdateRequest.createFrom(data)));
+    }
+
     const response = await api.put<ApiResponse>(`/mcp-tools/${id}`, data);
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '更新工具失败');
+      throw new Error(response.data.message || '更新工具失败');
     }
   }
 
@@ -96,10 +106,14 @@ export class MCPToolService {
    * 批量更新工具
    */
   static async batchUpdate(data: MCPToolBatchUpdateRequest): Promise<void> {
+    if (hasBindings()) {
+      return callBinding(() => BatchUpdateTools(models.MCPToolBatchUpdateRequest.createFrom(data)));
+    }
+
     const response = await api.put<ApiResponse>('/mcp-tools/batch', data);
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '批量更新工具失败');
+      throw new Error(response.data.message || '批量更新工具失败');
     }
   }
 
@@ -107,13 +121,17 @@ export class MCPToolService {
    * 获取工具分类
    */
   static async getCategories(serverId?: number): Promise<string[]> {
+    if (hasBindings()) {
+      return (await callBinding(() => GetToolCategories(serverId ?? 0))) || [];
+    }
+
     const params = serverId ? { server_id: serverId } : {};
     const response = await api.get<ApiResponse<string[]>>('/mcp-tools/categories', {
       params,
     });
     
     if (!response.data.success) {
-      throw new Error(response.data.error || '获取工具分类失败');
+      throw new Error(response.data.message || '获取工具分类失败');
     }
     
     return response.data.data || [];
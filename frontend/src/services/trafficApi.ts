@@ -0,0 +1,123 @@
+import axios from 'axios';
+import { callBinding, getApiBaseURL, getApiToken, hasBindings, toApiError, withApiBaseURL } from './apiBase';
+import { ClearTraffic, ListTraffic, SetTrafficCapture } from '../../wailsjs/go/main/App';
+import { models } from '../../wailsjs/go/models';
+
+// 创建axios实例
+const api = axios.create({
+  timeout: 10000,
+});
+
+// 请求拦截器：使用后端实际监听的地址
+api.interceptors.request.use(withApiBaseURL);
+
+// 响应拦截器
+api.interceptors.response.use((response) => response, toApiError);
+
+export type TrafficDirection = 'outgoing' | 'incoming';
+export type TrafficKind = 'request' | 'response' | 'notification';
+
+export interface TrafficListRequest {
+  direction?: TrafficDirection;
+  kind?: TrafficKind;
+  method?: string;
+  request_id?: string;
+  search?: string;
+  after_id?: number;
+  limit?: number;
+}
+
+export interface TrafficEntry {
+  id: number;
+  server_id: number;
+  session: number;
+  direction: TrafficDirection;
+  kind: TrafficKind;
+  method?: string;
+  rpc_id?: string;
+  request_id?: string;
+  time: string;
+  duration_ms?: number;
+  error?: string;
+  truncated?: boolean;
+  message?: any;
+}
+
+export interface TrafficStatus {
+  server_id: number;
+  enabled: boolean;
+  capacity: number;
+  count: number;
+  dropped: number;
+}
+
+export interface TrafficListResponse extends TrafficStatus {
+  total: number;
+  entries: TrafficEntry[];
+}
+
+/**
+ * 查询服务器抓取的JSON-RPC流量，按时间从旧到新返回
+ */
+export async function listTraffic(serverId: number, params: TrafficListRequest = {}): Promise<TrafficListResponse> {
+  if (hasBindings()) {
+    const request = models.MCPTrafficListRequest.createFrom({ limit: 200, ...params });
+    return callBinding(() => ListTraffic(serverId, request)) as Promise<unknown> as Promise<TrafficListResponse>;
+  }
+
+  const response = await api.get(`/mcp-servers/${serverId}/traffic`, { params });
+  return response.data.data;
+}
+
+/**
+ * 开启或关闭服务器的流量抓取
+ */
+export async function setTrafficCapture(serverId: number, enabled: boolean): Promise<TrafficStatus> {
+  if (hasBindings()) {
+    return callBinding(() => SetTrafficCapture(serverId, enabled));
+  }
+
+  const response = await api.put(`/mcp-servers/${serverId}/traffic/capture`, { enabled });
+  return response.data.data;
+}
+
+/**
+ * 清空服务器抓取的流量，返回删除的条数
+ */
+export async function clearTraffic(serverId: number): Promise<number> {
+  if (hasBindings()) {
+    return callBinding(() => ClearTraffic(serverId));
+  }
+
+  const response = await api.delete(`/mcp-servers/${serverId}/traffic`);
+  return response.data.data.removed;
+}
+
+/**
+ * 实时订阅服务器新抓取的流量（SSE），返回取消订阅的函数
+ */
+export async function followTraffic(
+  serverId: number,
+  params: TrafficListRequest,
+  onEntry: (entry: TrafficEntry) => void,
+): Promise<() => void> {
+  const query = new URLSearchParams({ follow: 'true', token: await getApiToken() });
+  for (const [key, value] of Object.entries(params)) {
+    if (value !== undefined && value !== '') {
+      query.set(key, String(value));
+    }
+  }
+
+  const source = new EventSource(`${await getApiBaseURL()}/mcp-servers/${serverId}/traffic?${query}`);
+  source.addEventListener('traffic', (event) => {
+    onEntry(JSON.parse((event as MessageEvent).data));
+  });
+  return () => source.close();
+}
+
+/**
+ * 将流量记录转换为JSONL文本，与 /traffic/export 的格式一致
+ */
+export function toJSONL(entries: TrafficEntry[]): string {
+  return entries.map((entry) => JSON.stringify(entry)).join('\n') + (entries.length ? '\n' : '');
+}
@@ -2,6 +2,7 @@
 
 export interface MCPServer {
   id: number;
+  workspace_id: number;
   name: string;
   description: string;
   url: string;
@@ -9,12 +10,19 @@ export interface MCPServer {
   auth_config: string;
   status: 'active' | 'inactive' | 'error';
   is_enabled: boolean;
-  tags: string;
+  tags: MCPTag[];
   created_at: string;
   updated_at: string;
   tools?: MCPTool[];
 }
 
+export interface MCPTag {
+  id: number;
+  name: string;
+  server_count?: number;
+  tool_count?: number;
+}
+
 export interface MCPTool {
   id: number;
   server_id: number;
@@ -23,6 +31,7 @@ export interface MCPTool {
   category: string;
   parameters: string;
   is_enabled: boolean;
+  tags?: MCPTag[];
   created_at: string;
   updated_at: string;
 }
@@ -33,7 +42,7 @@ export interface MCPServerCreateRequest {
   url: string;
   auth_type: 'none' | 'bearer' | 'basic' | 'api_key';
   auth_config?: string;
-  tags?: string;
+  tags?: string[];
 }
 
 export interface MCPServerUpdateRequest {
@@ -43,7 +52,7 @@ export interface MCPServerUpdateRequest {
   auth_type: 'none' | 'bearer' | 'basic' | 'api_key';
   auth_config?: string;
   is_enabled?: boolean;
-  tags?: string;
+  tags?: string[];
 }
 
 export interface MCPServerListRequest {
@@ -52,6 +61,8 @@ export interface MCPServerListRequest {
   search?: string;
   status?: 'active' | 'inactive' | 'error' | '';
   enabled?: boolean;
+  tags?: string; // 逗号分隔的标签名称
+  tag_mode?: 'and' | 'or';
   order_by?: 'created_at' | 'updated_at' | 'name';
   order_dir?: 'asc' | 'desc';
 }
@@ -65,6 +76,7 @@ export interface MCPServerListResponse {
 
 export interface ApiResponse<T = any> {
   success: boolean;
+  code?: string;
   data?: T;
   message?: string;
   error?: string;
@@ -1,4 +1,67 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {models} from '../models';
+
+export function BatchUpdateTools(arg1:models.MCPToolBatchUpdateRequest):Promise<void>;
+
+export function CallTool(arg1:number,arg2:models.MCPToolCallRequest):Promise<models.MCPToolCallResponse>;
+
+export function ClearTraffic(arg1:number):Promise<number>;
+
+export function CreateBackup(arg1:models.BackupCreateRequest):Promise<models.BackupInfo>;
+
+export function CreateServer(arg1:models.MCPServerCreateRequest):Promise<models.MCPServer>;
+
+export function DeleteBackup(arg1:string):Promise<void>;
+
+export function DeleteServer(arg1:number):Promise<void>;
+
+export function DiscoverTools(arg1:number):Promise<models.MCPToolDiscoveryResponse>;
+
+export function GetAPIBaseURL():Promise<string>;
+
+export function GetAPIPort():Promise<number>;
+
+export function GetAPIToken():Promise<string>;
+
+export function GetActiveWorkspace():Promise<models.Workspace>;
+
+export function GetBackupSchedule():Promise<models.BackupSchedule>;
+
+export function GetServer(arg1:number):Promise<models.MCPServer>;
+
+export function GetServerTags():Promise<Array<string>>;
+
+export function GetToolCategories(arg1:number):Promise<Array<string>>;
 
 export function Greet(arg1:string):Promise<string>;
+
+export function ListAppLogs(arg1:models.AppLogListRequest):Promise<models.AppLogListResponse>;
+
+export function ListBackups():Promise<Array<models.BackupInfo>>;
+
+export function ListServers(arg1:models.MCPServerListRequest):Promise<models.MCPServerListResponse>;
+
+export function ListTools(arg1:models.MCPToolListRequest):Promise<models.MCPToolListResponse>;
+
+export function ListTraffic(arg1:number,arg2:models.MCPTrafficListRequest):Promise<models.MCPTrafficListResponse>;
+
+export function ListWorkspaces():Promise<Array<models.Workspace>>;
+
+export function RefreshTools(arg1:number):Promise<models.MCPToolDiscoveryResponse>;
+
+export function RestoreBackup(arg1:string,arg2:models.BackupRestoreRequest):Promise<models.BackupRestoreResult>;
+
+export function SetTrafficCapture(arg1:number,arg2:boolean):Promise<models.MCPTrafficStatus>;
+
+export function SwitchWorkspace(arg1:number):Promise<models.Workspace>;
+
+export function ToggleServer(arg1:number):Promise<models.MCPServer>;
+
+export function UpdateBackupSchedule(arg1:models.BackupScheduleUpdateRequest):Promise<models.BackupSchedule>;
+
+export function UpdateServer(arg1:number,arg2:models.MCPServerUpdateRequest):Promise<models.MCPServer>;
+
+export function UpdateServerStatus(arg1:number,arg2:string):Promise<void>;
+
+export function UpdateTool(arg1:number,arg2:models.MCPToolUpdateRequest):Promise<void>;
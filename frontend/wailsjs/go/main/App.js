@@ -2,6 +2,130 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function BatchUpdateTools(arg1) {
+  return window['go']['main']['App']['BatchUpdateTools'](arg1);
+}
+
+export function CallTool(arg1, arg2) {
+  return window['go']['main']['App']['CallTool'](arg1, arg2);
+}
+
+export function ClearTraffic(arg1) {
+  return window['go']['main']['App']['ClearTraffic'](arg1);
+}
+
+export function CreateBackup(arg1) {
+  return window['go']['main']['App']['CreateBackup'](arg1);
+}
+
+export function CreateServer(arg1) {
+  return window['go']['main']['App']['CreateServer'](arg1);
+}
+
+export function DeleteBackup(arg1) {
+  return window['go']['main']['App']['DeleteBackup'](arg1);
+}
+
+export function DeleteServer(arg1) {
+  return window['go']['main']['App']['DeleteServer'](arg1);
+}
+
+export function DiscoverTools(arg1) {
+  return window['go']['main']['App']['DiscoverTools'](arg1);
+}
+
+export function GetAPIBaseURL() {
+  return window['go']['main']['App']['GetAPIBaseURL']();
+}
+
+export function GetAPIPort() {
+  return window['go']['main']['App']['GetAPIPort']();
+}
+
+export function GetAPIToken() {
+  return window['go']['main']['App']['GetAPIToken']();
+}
+
+export function GetActiveWorkspace() {
+  return window['go']['main']['App']['GetActiveWorkspace']();
+}
+
+export function GetBackupSchedule() {
+  return window['go']['main']['App']['GetBackupSchedule']();
+}
+
+export function GetServer(arg1) {
+  return window['go']['main']['App']['GetServer'](arg1);
+}
+
+export function GetServerTags() {
+  return window['go']['main']['App']['GetServerTags']();
+}
+
+export function GetToolCategories(arg1) {
+  return window['go']['main']['App']['GetToolCategories'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
+
+export function ListAppLogs(arg1) {
+  return window['go']['main']['App']['ListAppLogs'](arg1);
+}
+
+export function ListBackups() {
+  return window['go']['main']['App']['ListBackups']();
+}
+
+export function ListServers(arg1) {
+  return window['go']['main']['App']['ListServers'](arg1);
+}
+
+export function ListTools(arg1) {
+  return window['go']['main']['App']['ListTools'](arg1);
+}
+
+export function ListTraffic(arg1, arg2) {
+  return window['go']['main']['App']['ListTraffic'](arg1, arg2);
+}
+
+export function ListWorkspaces() {
+  return window['go']['main']['App']['ListWorkspaces']();
+}
+
+export function RefreshTools(arg1) {
+  return window['go']['main']['App']['RefreshTools'](arg1);
+}
+
+export function RestoreBackup(arg1, arg2) {
+  return window['go']['main']['App']['RestoreBackup'](arg1, arg2);
+}
+
+export function SetTrafficCapture(arg1, arg2) {
+  return window['go']['main']['App']['SetTrafficCapture'](arg1, arg2);
+}
+
+export function SwitchWorkspace(arg1) {
+  return window['go']['main']['App']['SwitchWorkspace'](arg1);
+}
+
+export function ToggleServer(arg1) {
+  return window['go']['main']['App']['ToggleServer'](arg1);
+}
+
+export function UpdateBackupSchedule(arg1) {
+  return window['go']['main']['App']['UpdateBackupSchedule'](arg1);
+}
+
+export function UpdateServer(arg1, arg2) {
+  return window['go']['main']['App']['UpdateServer'](arg1, arg2);
+}
+
+export function UpdateServerStatus(arg1, arg2) {
+  return window['go']['main']['App']['UpdateServerStatus'](arg1, arg2);
+}
+
+export function UpdateTool(arg1, arg2) {
+  return window['go']['main']['App']['UpdateTool'](arg1, arg2);
+}
@@ -0,0 +1,1100 @@
+export namespace gorm {
+	
+	export class DeletedAt {
+	    // Go type: time
+	    Time: any;
+	    Valid: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeletedAt(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Time = this.convertValues(source["Time"], null);
+	        this.Valid = source["Valid"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace logging {
+	
+	export class Entry {
+	    // Go type: time
+	    time: any;
+	    level: string;
+	    subsystem: string;
+	    msg: string;
+	    attrs?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.level = source["level"];
+	        this.subsystem = source["subsystem"];
+	        this.msg = source["msg"];
+	        this.attrs = source["attrs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace mcp {
+	
+	export class Meta {
+	    ProgressToken: any;
+	    AdditionalFields: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Meta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ProgressToken = source["ProgressToken"];
+	        this.AdditionalFields = source["AdditionalFields"];
+	    }
+	}
+	export class CallToolResult {
+	    // Go type: Meta
+	    _meta?: any;
+	    content: any[];
+	    structuredContent?: any;
+	    isError?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CallToolResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this._meta = this.convertValues(source["_meta"], null);
+	        this.content = source["content"];
+	        this.structuredContent = source["structuredContent"];
+	        this.isError = source["isError"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace models {
+	
+	export class AppLogListRequest {
+	    level: string;
+	    subsystem: string;
+	    search: string;
+	    // Go type: time
+	    since: any;
+	    // Go type: time
+	    until: any;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppLogListRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.subsystem = source["subsystem"];
+	        this.search = source["search"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.until = this.convertValues(source["until"], null);
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AppLogListResponse {
+	    total: number;
+	    logs: logging.Entry[];
+	    subsystems: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AppLogListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.logs = this.convertValues(source["logs"], logging.Entry);
+	        this.subsystems = source["subsystems"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupCreateRequest {
+	    include_secrets: boolean;
+	    exclude_history: boolean;
+	    note: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupCreateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.include_secrets = source["include_secrets"];
+	        this.exclude_history = source["exclude_history"];
+	        this.note = source["note"];
+	    }
+	}
+	export class BackupManifest {
+	    format_version: number;
+	    schema_version: number;
+	    // Go type: time
+	    created_at: any;
+	    automatic: boolean;
+	    includes_secrets: boolean;
+	    includes_history: boolean;
+	    note?: string;
+	    excludes: string[];
+	    counts: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupManifest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format_version = source["format_version"];
+	        this.schema_version = source["schema_version"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.automatic = source["automatic"];
+	        this.includes_secrets = source["includes_secrets"];
+	        this.includes_history = source["includes_history"];
+	        this.note = source["note"];
+	        this.excludes = source["excludes"];
+	        this.counts = source["counts"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupInfo {
+	    name: string;
+	    path: string;
+	    size: number;
+	    manifest: BackupManifest;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.manifest = this.convertValues(source["manifest"], BackupManifest);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class BackupRestoreRequest {
+	    mode: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupRestoreRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	    }
+	}
+	export class BackupRestoreResult {
+	    mode: string;
+	    safety_backup: string;
+	    restored: Record<string, number>;
+	    skipped_history: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupRestoreResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.safety_backup = source["safety_backup"];
+	        this.restored = source["restored"];
+	        this.skipped_history = source["skipped_history"];
+	    }
+	}
+	export class BackupSchedule {
+	    id: number;
+	    enabled: boolean;
+	    interval_hours: number;
+	    keep: number;
+	    include_secrets: boolean;
+	    include_history: boolean;
+	    // Go type: time
+	    last_backup_at?: any;
+	    last_backup_error: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupSchedule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.enabled = source["enabled"];
+	        this.interval_hours = source["interval_hours"];
+	        this.keep = source["keep"];
+	        this.include_secrets = source["include_secrets"];
+	        this.include_history = source["include_history"];
+	        this.last_backup_at = this.convertValues(source["last_backup_at"], null);
+	        this.last_backup_error = source["last_backup_error"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupScheduleUpdateRequest {
+	    enabled: boolean;
+	    interval_hours: number;
+	    keep: number;
+	    include_secrets: boolean;
+	    include_history: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupScheduleUpdateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.interval_hours = source["interval_hours"];
+	        this.keep = source["keep"];
+	        this.include_secrets = source["include_secrets"];
+	        this.include_history = source["include_history"];
+	    }
+	}
+	export class Tag {
+	    id: number;
+	    name: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	    server_count: number;
+	    tool_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.server_count = source["server_count"];
+	        this.tool_count = source["tool_count"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPTool {
+	    id: number;
+	    server_id: number;
+	    name: string;
+	    description: string;
+	    category: string;
+	    parameters: string;
+	    is_enabled: boolean;
+	    read_only_hint: boolean;
+	    idempotent_hint: boolean;
+	    cache_enabled?: boolean;
+	    cache_ttl: number;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	    deleted_at: gorm.DeletedAt;
+	    server?: MCPServer;
+	    tags: Tag[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPTool(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.server_id = source["server_id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.category = source["category"];
+	        this.parameters = source["parameters"];
+	        this.is_enabled = source["is_enabled"];
+	        this.read_only_hint = source["read_only_hint"];
+	        this.idempotent_hint = source["idempotent_hint"];
+	        this.cache_enabled = source["cache_enabled"];
+	        this.cache_ttl = source["cache_ttl"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.deleted_at = this.convertValues(source["deleted_at"], gorm.DeletedAt);
+	        this.server = this.convertValues(source["server"], MCPServer);
+	        this.tags = this.convertValues(source["tags"], Tag);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPServer {
+	    id: number;
+	    workspace_id: number;
+	    name: string;
+	    description: string;
+	    url: string;
+	    transport: string;
+	    command: string;
+	    args: string;
+	    env: string;
+	    headers: string;
+	    auth_type: string;
+	    auth_config: string;
+	    status: string;
+	    is_enabled: boolean;
+	    sampling_policy: string;
+	    log_level: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	    deleted_at: gorm.DeletedAt;
+	    tools?: MCPTool[];
+	    tags: Tag[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPServer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workspace_id = source["workspace_id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.url = source["url"];
+	        this.transport = source["transport"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.env = source["env"];
+	        this.headers = source["headers"];
+	        this.auth_type = source["auth_type"];
+	        this.auth_config = source["auth_config"];
+	        this.status = source["status"];
+	        this.is_enabled = source["is_enabled"];
+	        this.sampling_policy = source["sampling_policy"];
+	        this.log_level = source["log_level"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.deleted_at = this.convertValues(source["deleted_at"], gorm.DeletedAt);
+	        this.tools = this.convertValues(source["tools"], MCPTool);
+	        this.tags = this.convertValues(source["tags"], Tag);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPServerCreateRequest {
+	    name: string;
+	    description: string;
+	    url: string;
+	    transport: string;
+	    command: string;
+	    args: string[];
+	    env: Record<string, string>;
+	    headers: Record<string, string>;
+	    auth_type: string;
+	    auth_config: string;
+	    tags: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPServerCreateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.url = source["url"];
+	        this.transport = source["transport"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.env = source["env"];
+	        this.headers = source["headers"];
+	        this.auth_type = source["auth_type"];
+	        this.auth_config = source["auth_config"];
+	        this.tags = source["tags"];
+	    }
+	}
+	export class MCPServerListRequest {
+	    page: number;
+	    size: number;
+	    search: string;
+	    status: string;
+	    enabled?: boolean;
+	    tags: string[];
+	    tag_mode: string;
+	    order_by: string;
+	    order_dir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPServerListRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.page = source["page"];
+	        this.size = source["size"];
+	        this.search = source["search"];
+	        this.status = source["status"];
+	        this.enabled = source["enabled"];
+	        this.tags = source["tags"];
+	        this.tag_mode = source["tag_mode"];
+	        this.order_by = source["order_by"];
+	        this.order_dir = source["order_dir"];
+	    }
+	}
+	export class MCPServerListResponse {
+	    total: number;
+	    page: number;
+	    size: number;
+	    servers: MCPServer[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPServerListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.page = source["page"];
+	        this.size = source["size"];
+	        this.servers = this.convertValues(source["servers"], MCPServer);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPServerUpdateRequest {
+	    name: string;
+	    description: string;
+	    url: string;
+	    transport: string;
+	    command?: string;
+	    args?: string[];
+	    env?: Record<string, string>;
+	    headers?: Record<string, string>;
+	    auth_type: string;
+	    auth_config: string;
+	    is_enabled?: boolean;
+	    tags: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPServerUpdateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.url = source["url"];
+	        this.transport = source["transport"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.env = source["env"];
+	        this.headers = source["headers"];
+	        this.auth_type = source["auth_type"];
+	        this.auth_config = source["auth_config"];
+	        this.is_enabled = source["is_enabled"];
+	        this.tags = source["tags"];
+	    }
+	}
+	
+	export class MCPToolBatchUpdateRequest {
+	    tool_ids: number[];
+	    is_enabled?: boolean;
+	    category: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolBatchUpdateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_ids = source["tool_ids"];
+	        this.is_enabled = source["is_enabled"];
+	        this.category = source["category"];
+	    }
+	}
+	export class MCPToolCallRequest {
+	    arguments: Record<string, any>;
+	    bypass_cache: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolCallRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.arguments = source["arguments"];
+	        this.bypass_cache = source["bypass_cache"];
+	    }
+	}
+	export class MCPToolCallResponse {
+	    tool_id: number;
+	    tool_name: string;
+	    server_id: number;
+	    cached: boolean;
+	    // Go type: time
+	    cached_at?: any;
+	    result?: mcp.CallToolResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolCallResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tool_id = source["tool_id"];
+	        this.tool_name = source["tool_name"];
+	        this.server_id = source["server_id"];
+	        this.cached = source["cached"];
+	        this.cached_at = this.convertValues(source["cached_at"], null);
+	        this.result = this.convertValues(source["result"], mcp.CallToolResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPToolDiscoveryResponse {
+	    success: boolean;
+	    message: string;
+	    tools?: MCPTool[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolDiscoveryResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.tools = this.convertValues(source["tools"], MCPTool);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPToolListRequest {
+	    server_id: number;
+	    category: string;
+	    enabled?: boolean;
+	    search: string;
+	    tags: string[];
+	    tag_mode: string;
+	    page: number;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolListRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.server_id = source["server_id"];
+	        this.category = source["category"];
+	        this.enabled = source["enabled"];
+	        this.search = source["search"];
+	        this.tags = source["tags"];
+	        this.tag_mode = source["tag_mode"];
+	        this.page = source["page"];
+	        this.size = source["size"];
+	    }
+	}
+	export class MCPToolListResponse {
+	    total: number;
+	    page: number;
+	    size: number;
+	    tools: MCPTool[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.page = source["page"];
+	        this.size = source["size"];
+	        this.tools = this.convertValues(source["tools"], MCPTool);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPToolUpdateRequest {
+	    is_enabled?: boolean;
+	    category: string;
+	    cache_enabled?: boolean;
+	    cache_ttl?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolUpdateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.is_enabled = source["is_enabled"];
+	        this.category = source["category"];
+	        this.cache_enabled = source["cache_enabled"];
+	        this.cache_ttl = source["cache_ttl"];
+	    }
+	}
+	export class MCPTrafficEntry {
+	    id: number;
+	    server_id: number;
+	    session: number;
+	    direction: string;
+	    kind: string;
+	    method?: string;
+	    rpc_id?: string;
+	    request_id?: string;
+	    // Go type: time
+	    time: any;
+	    duration_ms?: number;
+	    error?: string;
+	    truncated?: boolean;
+	    message?: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPTrafficEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.server_id = source["server_id"];
+	        this.session = source["session"];
+	        this.direction = source["direction"];
+	        this.kind = source["kind"];
+	        this.method = source["method"];
+	        this.rpc_id = source["rpc_id"];
+	        this.request_id = source["request_id"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.duration_ms = source["duration_ms"];
+	        this.error = source["error"];
+	        this.truncated = source["truncated"];
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPTrafficListRequest {
+	    direction: string;
+	    kind: string;
+	    method: string;
+	    request_id: string;
+	    search: string;
+	    after_id: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPTrafficListRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.direction = source["direction"];
+	        this.kind = source["kind"];
+	        this.method = source["method"];
+	        this.request_id = source["request_id"];
+	        this.search = source["search"];
+	        this.after_id = source["after_id"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class MCPTrafficListResponse {
+	    server_id: number;
+	    enabled: boolean;
+	    capacity: number;
+	    count: number;
+	    dropped: number;
+	    total: number;
+	    entries: MCPTrafficEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPTrafficListResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.server_id = source["server_id"];
+	        this.enabled = source["enabled"];
+	        this.capacity = source["capacity"];
+	        this.count = source["count"];
+	        this.dropped = source["dropped"];
+	        this.total = source["total"];
+	        this.entries = this.convertValues(source["entries"], MCPTrafficEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPTrafficStatus {
+	    server_id: number;
+	    enabled: boolean;
+	    capacity: number;
+	    count: number;
+	    dropped: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPTrafficStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.server_id = source["server_id"];
+	        this.enabled = source["enabled"];
+	        this.capacity = source["capacity"];
+	        this.count = source["count"];
+	        this.dropped = source["dropped"];
+	    }
+	}
+	
+	export class Workspace {
+	    id: number;
+	    name: string;
+	    description: string;
+	    is_active: boolean;
+	    sampling_policy: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at: any;
+	    server_count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Workspace(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.is_active = source["is_active"];
+	        this.sampling_policy = source["sampling_policy"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.server_count = source["server_count"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale 语言标识
+type Locale string
+
+// 支持的语言
+const (
+	ZhCN Locale = "zh-CN"
+	EnUS Locale = "en-US"
+)
+
+// DefaultLocale 默认语言，无法确定请求语言时使用
+const DefaultLocale = ZhCN
+
+// ContextKey 请求上下文中保存语言的键，gin.Context 可直接通过 Value 读取
+const ContextKey = "locale"
+
+// Supported 返回支持的语言列表
+func Supported() []Locale {
+	return []Locale{ZhCN, EnUS}
+}
+
+// Parse 解析语言标识，支持 zh、en-GB 等写法，不支持的语言返回 false
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case tag == "zh" || strings.HasPrefix(tag, "zh-") || strings.HasPrefix(tag, "zh_"):
+		return ZhCN, true
+	case tag == "en" || strings.HasPrefix(tag, "en-") || strings.HasPrefix(tag, "en_"):
+		return EnUS, true
+	}
+	return "", false
+}
+
+// FromAcceptLanguage 按 Accept-Language 请求头的优先级选择支持的语言
+func FromAcceptLanguage(header string) (Locale, bool) {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: fields[0], q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if locale, ok := Parse(c.tag); ok {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// FromContext 获取上下文中的语言，未设置时返回默认语言
+func FromContext(ctx context.Context) Locale {
+	if ctx != nil {
+		if locale, ok := ctx.Value(ContextKey).(Locale); ok {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// Lookup 查找消息模板，当前语言缺失时使用默认语言
+func Lookup(locale Locale, key string) (string, bool) {
+	entry, ok := messages[key]
+	if !ok {
+		return "", false
+	}
+	if text, ok := entry[locale]; ok {
+		return text, true
+	}
+	text, ok := entry[DefaultLocale]
+	return text, ok
+}
+
+// Message 按语言生成消息，消息目录中没有该键时返回键本身
+func Message(locale Locale, key string, args ...interface{}) string {
+	text, ok := Lookup(locale, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// T 按上下文中的语言生成消息
+func T(ctx context.Context, key string, args ...interface{}) string {
+	return Message(FromContext(ctx), key, args...)
+}
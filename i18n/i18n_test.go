@@ -0,0 +1,48 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestCatalogComplete 测试消息目录中每条消息都有所有语言的模板，且格式化参数一致
+func TestCatalogComplete(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	for key, entry := range messages {
+		zh, ok := entry[ZhCN]
+		if !ok {
+			t.Fatalf("消息 %s 缺少简体中文模板", key)
+		}
+		for _, locale := range Supported() {
+			text, ok := entry[locale]
+			if !ok {
+				t.Fatalf("消息 %s 缺少 %s 模板", key, locale)
+			}
+			if got, want := verbs.FindAllString(text, -1), verbs.FindAllString(zh, -1); len(got) != len(want) {
+				t.Fatalf("消息 %s 的 %s 模板参数与简体中文不一致: %v %v", key, locale, got, want)
+			}
+		}
+	}
+}
+
+// TestFromAcceptLanguage 测试按 Accept-Language 请求头选择语言
+func TestFromAcceptLanguage(t *testing.T) {
+	cases := []struct {
+		header string
+		want   Locale
+		ok     bool
+	}{
+		{"en-US,en;q=0.9", EnUS, true},
+		{"zh-CN,zh;q=0.9,en;q=0.8", ZhCN, true},
+		{"fr-FR, en-GB;q=0.8, zh;q=0.5", EnUS, true},
+		{"en;q=0.3, zh-TW;q=0.7", ZhCN, true},
+		{"fr-FR", "", false},
+		{"", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := FromAcceptLanguage(tc.header)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("Accept-Language %q 应选择 %q，实际为 %q", tc.header, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package i18n
+
+// messages 消息目录，键为错误码或消息码
+// 同一条消息的各语言模板必须使用相同的格式化参数
+var messages = map[string]map[Locale]string{
+	// 通用错误
+	"invalid_request": {ZhCN: "请求参数错误", EnUS: "Invalid request parameters"},
+	"invalid_id":      {ZhCN: "无效的ID: %s", EnUS: "Invalid ID: %s"},
+	"not_found":       {ZhCN: "资源不存在", EnUS: "Resource not found"},
+	"timeout":         {ZhCN: "操作超时", EnUS: "Operation timed out"},
+	"unauthorized":    {ZhCN: "缺少或无效的API令牌", EnUS: "Missing or invalid API token"},
+	"internal_error":  {ZhCN: "服务器内部错误", EnUS: "Internal server error"},
+
+	// 请求参数校验，参数为字段名和校验规则的参数
+	"validation_required": {ZhCN: "%s 为必填项", EnUS: "%s is required"},
+	"validation_min":      {ZhCN: "%s 不能小于 %s", EnUS: "%s must be at least %s"},
+	"validation_max":      {ZhCN: "%s 不能大于 %s", EnUS: "%s must be at most %s"},
+	"validation_oneof":    {ZhCN: "%s 必须是以下值之一: %s", EnUS: "%s must be one of: %s"},
+	"validation_invalid":  {ZhCN: "%s 的值无效", EnUS: "%s is invalid"},
+
+	// 应用设置
+	"invalid_settings":         {ZhCN: "更新应用设置失败", EnUS: "Failed to update settings"},
+	"settings_loaded":          {ZhCN: "获取应用设置成功", EnUS: "Settings loaded"},
+	"settings_updated":         {ZhCN: "更新应用设置成功", EnUS: "Settings updated"},
+	"settings_updated_restart": {ZhCN: "更新应用设置成功，部分设置需要重启应用后生效", EnUS: "Settings updated; some changes take effect after restarting the app"},
+	"server_running":           {ZhCN: "服务运行正常", EnUS: "Server is running"},
+	"shutdown_confirm_title":   {ZhCN: "确认退出", EnUS: "Quit the app?"},
+	"shutdown_calls_running":   {ZhCN: "仍有 %d 个工具调用正在运行，退出将中断这些调用。确定要退出吗？", EnUS: "%d tool call(s) are still running and will be interrupted. Quit anyway?"},
+
+	// MCP服务器
+	"server_not_found":            {ZhCN: "服务器不存在", EnUS: "Server not found"},
+	"server_name_exists":          {ZhCN: "服务器名称已存在", EnUS: "A server with this name already exists"},
+	"server_disabled":             {ZhCN: "服务器已禁用", EnUS: "Server is disabled"},
+	"server_inactive":             {ZhCN: "服务器未激活，请先连接服务器", EnUS: "Server is not active; connect it first"},
+	"server_name_required":        {ZhCN: "服务器名称不能为空", EnUS: "Server name is required"},
+	"server_name_too_long":        {ZhCN: "服务器名称不能超过%d个字符", EnUS: "Server name must not exceed %d characters"},
+	"server_description_too_long": {ZhCN: "服务器描述不能超过%d个字符", EnUS: "Server description must not exceed %d characters"},
+	"server_command_required":     {ZhCN: "stdio服务器必须配置启动命令", EnUS: "A stdio server requires a command"},
+	"server_url_required":         {ZhCN: "服务器地址不能为空", EnUS: "Server URL is required"},
+	"invalid_server_url":          {ZhCN: "无效的服务器地址: %s", EnUS: "Invalid server URL: %s"},
+	"invalid_transport":           {ZhCN: "无效的传输方式: %s", EnUS: "Invalid transport: %s"},
+	"invalid_auth_type":           {ZhCN: "无效的认证方式: %s", EnUS: "Invalid auth type: %s"},
+	"invalid_server_status":       {ZhCN: "无效的状态值: %s", EnUS: "Invalid status: %s"},
+	"invalid_log_level":           {ZhCN: "无效的日志级别: %s", EnUS: "Invalid log level: %s"},
+	"invalid_event_topic":         {ZhCN: "无效的事件主题: %s", EnUS: "Invalid event topic: %s"},
+	"mcp_connect_failed":          {ZhCN: "连接 MCP 服务器失败", EnUS: "Failed to connect to the MCP server"},
+	"mcp_request_failed":          {ZhCN: "MCP服务器请求失败", EnUS: "MCP server request failed"},
+	"mcp_not_connected":           {ZhCN: "客户端未连接", EnUS: "Client is not connected"},
+	"server_created":              {ZhCN: "MCP服务器创建成功", EnUS: "MCP server created"},
+	"server_updated":              {ZhCN: "MCP服务器更新成功", EnUS: "MCP server updated"},
+	"server_deleted":              {ZhCN: "MCP服务器删除成功", EnUS: "MCP server deleted"},
+	"server_status_updated":       {ZhCN: "服务器状态更新成功", EnUS: "Server status updated"},
+	"server_toggled":              {ZhCN: "服务器状态切换成功", EnUS: "Server enabled state toggled"},
+	"sampling_policy_updated":     {ZhCN: "采样策略更新成功", EnUS: "Sampling policy updated"},
+	"log_level_updated":           {ZhCN: "日志级别更新成功", EnUS: "Log level updated"},
+	"server_logs_cleared":         {ZhCN: "服务器日志已清空", EnUS: "Server logs cleared"},
+	"traffic_capture_enabled":     {ZhCN: "已开启流量抓取", EnUS: "Traffic capture enabled"},
+	"traffic_capture_disabled":    {ZhCN: "已关闭流量抓取", EnUS: "Traffic capture disabled"},
+	"traffic_cleared":             {ZhCN: "已清空 %d 条流量记录", EnUS: "Cleared %d traffic entries"},
+
+	// 导入
+	"import_source_required":       {ZhCN: "请提供配置内容、文件路径或指定配置格式", EnUS: "Provide config content, a file path or a config format"},
+	"client_config_unreadable":     {ZhCN: "读取配置文件失败: %v", EnUS: "Failed to read config file: %v"},
+	"invalid_client_config":        {ZhCN: "解析配置文件失败: %v", EnUS: "Failed to parse config file: %v"},
+	"client_config_no_servers":     {ZhCN: "配置文件中未找到 mcpServers 或 servers 配置", EnUS: "No mcpServers or servers section found in the config file"},
+	"invalid_client_server_config": {ZhCN: "解析服务器配置失败: %v", EnUS: "Failed to parse server config: %v"},
+	"no_default_config_path":       {ZhCN: "格式 %s 没有默认配置文件路径", EnUS: "Format %s has no default config file path"},
+	"import_completed":             {ZhCN: "导入完成：新建 %d 个，覆盖 %d 个，跳过 %d 个，失败 %d 个", EnUS: "Import finished: %d created, %d overwritten, %d skipped, %d failed"},
+
+	// 工具
+	"tool_not_found":   {ZhCN: "工具不存在", EnUS: "Tool not found"},
+	"tool_disabled":    {ZhCN: "工具已禁用", EnUS: "Tool is disabled"},
+	"tool_updated":     {ZhCN: "工具更新成功", EnUS: "Tool updated"},
+	"tools_updated":    {ZhCN: "成功更新 %d 个工具", EnUS: "Updated %d tools"},
+	"tools_discovered": {ZhCN: "成功发现 %d 个工具", EnUS: "Discovered %d tools"},
+	"tools_refreshed":  {ZhCN: "成功刷新 %d 个工具", EnUS: "Refreshed %d tools"},
+	"cache_purged":     {ZhCN: "已清除 %d 条缓存", EnUS: "Purged %d cache entries"},
+
+	// 采样与信息收集
+	"sampling_config_incomplete":     {ZhCN: "启用采样时必须配置接口地址和模型", EnUS: "Base URL and model are required when sampling is enabled"},
+	"sampling_request_not_found":     {ZhCN: "采样请求不存在或已过期", EnUS: "Sampling request not found or expired"},
+	"sampling_unsupported_transport": {ZhCN: "%s 传输方式不支持采样，请改用 streamable_http 或 stdio", EnUS: "The %s transport does not support sampling; use streamable_http or stdio"},
+	"sampling_timeout":               {ZhCN: "等待用户确认超时", EnUS: "Timed out waiting for user confirmation"},
+	"sampling_config_updated":        {ZhCN: "采样配置更新成功", EnUS: "Sampling settings updated"},
+	"sampling_decision_submitted":    {ZhCN: "已提交采样确认结果", EnUS: "Sampling decision submitted"},
+	"elicitation_not_found":          {ZhCN: "信息收集请求不存在或已过期", EnUS: "Elicitation request not found or expired"},
+	"elicitation_field_required":     {ZhCN: "缺少必填字段: %s", EnUS: "Missing required field: %s"},
+	"elicitation_response_submitted": {ZhCN: "已提交响应", EnUS: "Response submitted"},
+
+	// 根目录
+	"root_not_found":          {ZhCN: "根目录不存在", EnUS: "Root not found"},
+	"root_path_not_absolute":  {ZhCN: "根目录必须是绝对路径", EnUS: "Root path must be absolute"},
+	"root_path_not_found":     {ZhCN: "根目录不存在: %s", EnUS: "Root path does not exist: %s"},
+	"root_path_not_directory": {ZhCN: "根目录不是文件夹: %s", EnUS: "Root path is not a directory: %s"},
+	"root_created":            {ZhCN: "根目录创建成功", EnUS: "Root created"},
+	"root_updated":            {ZhCN: "根目录更新成功", EnUS: "Root updated"},
+	"roots_transport_notice":  {ZhCN: "根目录只提供给 streamable_http 和 stdio 服务器，SSE 服务器无法接收；变更只通知已连接的会话，其他会话在下次连接时获取最新的根目录", EnUS: "Roots are only provided to streamable_http and stdio servers, SSE servers cannot receive them; changes are only announced to connected sessions, other sessions get the current roots on their next connection"},
+	"root_deleted":            {ZhCN: "根目录删除成功", EnUS: "Root deleted"},
+
+	// 备份
+	"invalid_backup_name":           {ZhCN: "无效的备份文件名: %s", EnUS: "Invalid backup file name: %s"},
+	"backup_not_found":              {ZhCN: "备份不存在: %s", EnUS: "Backup not found: %s"},
+	"backup_file_required":          {ZhCN: "请上传备份文件", EnUS: "Please upload a backup file"},
+	"backup_format_unsupported":     {ZhCN: "备份文件格式版本 %d 高于当前支持的版本 %d，请升级应用后再恢复", EnUS: "Backup format version %d is newer than the supported version %d; upgrade the app before restoring"},
+	"backup_schema_unsupported":     {ZhCN: "备份的数据库结构版本 %d 高于当前版本 %d，请升级应用后再恢复", EnUS: "Backup schema version %d is newer than the current version %d; upgrade the app before restoring"},
+	"invalid_backup_file":           {ZhCN: "打开备份文件失败: %v", EnUS: "Failed to open backup file: %v"},
+	"backup_missing_format_version": {ZhCN: "无效的备份文件：缺少格式版本", EnUS: "Invalid backup file: missing format version"},
+	"backup_missing_entry":          {ZhCN: "无效的备份文件：缺少 %s", EnUS: "Invalid backup file: missing %s"},
+	"invalid_backup_entry":          {ZhCN: "解析 %s 失败: %v", EnUS: "Failed to parse %s: %v"},
+	"backup_secrets_undecryptable":  {ZhCN: "无法解密备份中的敏感信息，备份可能来自其他设备或加密密钥已删除: %v", EnUS: "Cannot decrypt the secrets in the backup; it may come from another device or its encryption key was removed: %v"},
+	"backup_created":                {ZhCN: "备份创建成功", EnUS: "Backup created"},
+	"backup_uploaded":               {ZhCN: "备份上传成功", EnUS: "Backup uploaded"},
+	"backup_restored":               {ZhCN: "备份恢复成功", EnUS: "Backup restored"},
+	"backup_deleted":                {ZhCN: "备份删除成功", EnUS: "Backup deleted"},
+	"backup_schedule_updated":       {ZhCN: "自动备份设置已更新", EnUS: "Backup schedule updated"},
+
+	// 密钥
+	"secret_not_found":           {ZhCN: "密钥不存在", EnUS: "Secret not found"},
+	"secret_name_exists":         {ZhCN: "密钥名称已存在", EnUS: "A secret with this name already exists"},
+	"invalid_secret_name":        {ZhCN: "密钥名称只能包含字母、数字、下划线、点和中划线", EnUS: "Secret names may only contain letters, digits, underscores, dots and hyphens"},
+	"secret_in_use":              {ZhCN: "密钥仍被以下服务器引用: %s", EnUS: "Secret is still referenced by these servers: %s"},
+	"master_key_not_initialized": {ZhCN: "主密钥未初始化", EnUS: "Master key is not initialized"},
+	"secret_created":             {ZhCN: "密钥创建成功", EnUS: "Secret created"},
+	"secret_updated":             {ZhCN: "密钥更新成功", EnUS: "Secret updated"},
+	"secret_deleted":             {ZhCN: "密钥删除成功", EnUS: "Secret deleted"},
+	"master_key_rotated":         {ZhCN: "主密钥轮换成功", EnUS: "Master key rotated"},
+
+	// 标签
+	"tag_not_found":        {ZhCN: "标签不存在", EnUS: "Tag not found"},
+	"source_tag_not_found": {ZhCN: "部分源标签不存在", EnUS: "Some source tags do not exist"},
+	"invalid_tag_name":     {ZhCN: "无效的标签名称: %s", EnUS: "Invalid tag name: %s"},
+	"tag_name_exists":      {ZhCN: "标签 %s 已存在，请使用合并", EnUS: "Tag %s already exists; merge the tags instead"},
+	"tag_too_long":         {ZhCN: "标签 %s 超过%d个字符", EnUS: "Tag %s exceeds %d characters"},
+	"too_many_tags":        {ZhCN: "标签不能超过%d个", EnUS: "No more than %d tags are allowed"},
+	"tag_renamed":          {ZhCN: "标签重命名成功", EnUS: "Tag renamed"},
+	"tag_merged":           {ZhCN: "标签合并成功", EnUS: "Tags merged"},
+	"tag_deleted":          {ZhCN: "标签删除成功", EnUS: "Tag deleted"},
+	"tool_tags_updated":    {ZhCN: "工具标签更新成功", EnUS: "Tool tags updated"},
+
+	// 工作区
+	"workspace_not_found":   {ZhCN: "工作区不存在", EnUS: "Workspace not found"},
+	"workspace_name_exists": {ZhCN: "工作区名称已存在", EnUS: "A workspace with this name already exists"},
+	"workspace_active":      {ZhCN: "不能删除当前激活的工作区，请先切换到其他工作区", EnUS: "The active workspace cannot be deleted; switch to another workspace first"},
+	"no_active_workspace":   {ZhCN: "没有激活的工作区", EnUS: "No active workspace"},
+	"workspace_created":     {ZhCN: "工作区创建成功", EnUS: "Workspace created"},
+	"workspace_updated":     {ZhCN: "工作区更新成功", EnUS: "Workspace updated"},
+	"workspace_deleted":     {ZhCN: "工作区删除成功", EnUS: "Workspace deleted"},
+	"workspace_switched":    {ZhCN: "工作区切换成功", EnUS: "Workspace switched"},
+	"workspace_cloned":      {ZhCN: "工作区复制成功", EnUS: "Workspace cloned"},
+}
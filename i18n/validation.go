@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationMessage 将 gin 绑定产生的校验错误翻译为指定语言，不是校验错误时返回 false
+func ValidationMessage(locale Locale, err error) (string, bool) {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return "", false
+	}
+
+	parts := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		switch fe.Tag() {
+		case "required":
+			parts = append(parts, Message(locale, "validation_required", fe.Field()))
+		case "min", "gte":
+			parts = append(parts, Message(locale, "validation_min", fe.Field(), fe.Param()))
+		case "max", "lte":
+			parts = append(parts, Message(locale, "validation_max", fe.Field(), fe.Param()))
+		case "oneof":
+			parts = append(parts, Message(locale, "validation_oneof", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", ")))
+		default:
+			parts = append(parts, Message(locale, "validation_invalid", fe.Field()))
+		}
+	}
+	return strings.Join(parts, "; "), true
+}
+
+// FieldName 返回校验错误中使用的字段名，优先使用 json 或 form 标签，与请求中的字段名保持一致
+// 通过 validator.Validate.RegisterTagNameFunc 注册
+func FieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(key), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// currentFileName 正在写入的日志文件名
+	currentFileName = "app.log"
+	// rotatedPattern 轮转后的日志文件名，按轮转时间命名
+	rotatedPattern = "app-*.log"
+
+	defaultMaxSize  = 10 << 20
+	defaultMaxFiles = 5
+)
+
+// rotatingFile 按大小轮转的日志文件，调用方负责加锁
+type rotatingFile struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// openRotatingFile 打开日志目录中正在写入的日志文件，不存在时创建
+func openRotatingFile(dir string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	if maxSize <= 0 {
+		maxSize = defaultMaxSize
+	}
+	if maxFiles <= 0 {
+		maxFiles = defaultMaxFiles
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建日志目录失败: %v", err)
+	}
+
+	f := &rotatingFile{dir: dir, maxSize: maxSize, maxFiles: maxFiles}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(filepath.Join(f.dir, currentFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("读取日志文件信息失败: %v", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// WriteEntry 以JSON行的形式写入一条日志，文件超过大小限制时先轮转
+func (f *rotatingFile) WriteEntry(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		// 属性中包含无法序列化的值时只保留消息
+		entry.Attrs = map[string]interface{}{"attrs_error": err.Error()}
+		if line, err = json.Marshal(entry); err != nil {
+			return err
+		}
+	}
+	line = append(line, '\n')
+
+	if f.size > 0 && f.size+int64(len(line)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	return err
+}
+
+// rotate 将当前文件改名为带时间的历史文件，并删除超出数量的旧文件
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	rotated := filepath.Join(f.dir, "app-"+time.Now().Format("20060102-150405.000")+".log")
+	if err := os.Rename(filepath.Join(f.dir, currentFileName), rotated); err != nil {
+		return fmt.Errorf("轮转日志文件失败: %v", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	history, err := rotatedFiles(f.dir)
+	if err != nil {
+		return err
+	}
+	for len(history) > f.maxFiles {
+		os.Remove(history[0])
+		history = history[1:]
+	}
+	return nil
+}
+
+// Close 关闭日志文件
+func (f *rotatingFile) Close() error {
+	return f.file.Close()
+}
+
+// rotatedFiles 返回目录中的历史日志文件，按时间从旧到新排列
+func rotatedFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, rotatedPattern))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// logFiles 返回目录中的所有日志文件，按时间从旧到新排列，正在写入的文件在最后
+func logFiles(dir string) ([]string, error) {
+	files, err := rotatedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	current := filepath.Join(dir, currentFileName)
+	if _, err := os.Stat(current); err == nil {
+		files = append(files, current)
+	}
+	return files, nil
+}
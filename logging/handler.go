@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// handler slog 处理器，日志写入当前的输出目标并按子系统的级别过滤
+type handler struct {
+	subsystem string
+	prefix    string // WithGroup 设置的属性名前缀
+	attrs     map[string]interface{}
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return std.Load().enabled(h.subsystem, level)
+}
+
+func (h *handler) Handle(_ context.Context, record slog.Record) error {
+	entry := Entry{
+		Time:      record.Time,
+		Level:     LevelName(record.Level),
+		Subsystem: h.subsystem,
+		Message:   record.Message,
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	if len(h.attrs) > 0 || record.NumAttrs() > 0 {
+		entry.Attrs = make(map[string]interface{}, len(h.attrs)+record.NumAttrs())
+		for key, value := range h.attrs {
+			entry.Attrs[key] = value
+		}
+		record.Attrs(func(attr slog.Attr) bool {
+			addAttr(entry.Attrs, h.prefix, attr)
+			return true
+		})
+	}
+
+	std.Load().write(entry)
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := &handler{subsystem: h.subsystem, prefix: h.prefix, attrs: make(map[string]interface{}, len(h.attrs)+len(attrs))}
+	for key, value := range h.attrs {
+		clone.attrs[key] = value
+	}
+	for _, attr := range attrs {
+		addAttr(clone.attrs, h.prefix, attr)
+	}
+	return clone
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{subsystem: h.subsystem, prefix: h.prefix + name + ".", attrs: h.attrs}
+}
+
+// addAttr 将属性转换为可序列化的值，分组属性展开为 group.key 形式
+func addAttr(attrs map[string]interface{}, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+		for _, member := range value.Group() {
+			addAttr(attrs, groupPrefix, member)
+		}
+		return
+	}
+	if attr.Key == "" {
+		return
+	}
+
+	switch value.Kind() {
+	case slog.KindDuration:
+		attrs[prefix+attr.Key] = value.Duration().String()
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			attrs[prefix+attr.Key] = err.Error()
+			return
+		}
+		attrs[prefix+attr.Key] = value.Any()
+	default:
+		attrs[prefix+attr.Key] = value.Any()
+	}
+}
+
+// sortedKeys 按名称排序的属性名，保证控制台输出顺序稳定
+func sortedKeys(attrs map[string]interface{}) []string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
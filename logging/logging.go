@@ -0,0 +1,235 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 日志所属的子系统，各子系统可以单独设置日志级别
+const (
+	SubsystemApp      = "app"      // 应用启动、退出等生命周期
+	SubsystemAPI      = "api"      // HTTP API 请求和错误
+	SubsystemConfig   = "config"   // 配置加载和变更
+	SubsystemDatabase = "database" // 数据库连接、迁移和SQL
+	SubsystemMCP      = "mcp"      // MCP 连接、工具发现和调用、采样和信息收集
+	SubsystemBackup   = "backup"   // 备份和恢复
+)
+
+// Subsystems 返回所有子系统
+func Subsystems() []string {
+	return []string{SubsystemApp, SubsystemAPI, SubsystemConfig, SubsystemDatabase, SubsystemMCP, SubsystemBackup}
+}
+
+// ValidSubsystem 判断子系统名称是否有效
+func ValidSubsystem(name string) bool {
+	for _, subsystem := range Subsystems() {
+		if subsystem == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Levels 日志级别名称，按严重程度从低到高排列
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// ParseLevel 解析日志级别名称
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("无效的日志级别: %s", name)
+}
+
+// LevelName 返回日志级别名称
+func LevelName(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "debug"
+	case level < slog.LevelWarn:
+		return "info"
+	case level < slog.LevelError:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// Entry 一条日志，日志文件中每行保存一条JSON格式的记录
+type Entry struct {
+	Time      time.Time              `json:"time"`
+	Level     string                 `json:"level"`
+	Subsystem string                 `json:"subsystem"`
+	Message   string                 `json:"msg"`
+	Attrs     map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Options 日志配置
+type Options struct {
+	Dir        string            // 日志目录，为空时只输出到控制台
+	MaxSize    int64             // 单个日志文件的最大字节数，超过后轮转
+	MaxFiles   int               // 保留的历史日志文件数
+	Level      string            // 默认日志级别
+	Subsystems map[string]string // 各子系统的日志级别，为空的项使用默认级别
+	Console    io.Writer         // 同时以文本格式输出到控制台，为nil时不输出
+}
+
+// levelSet 默认级别和各子系统的级别
+type levelSet struct {
+	level      slog.Level
+	subsystems map[string]slog.Level
+}
+
+// Logger 日志输出目标，负责写入日志文件、控制台并推送给实时订阅者
+type Logger struct {
+	mu      sync.Mutex // 保证每条日志完整写入
+	dir     string
+	file    *rotatingFile
+	console io.Writer
+	levels  atomic.Pointer[levelSet]
+
+	subMu       sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// std 当前使用的日志输出目标，初始化前只输出到标准错误
+var std atomic.Pointer[Logger]
+
+func init() {
+	l := &Logger{console: os.Stderr, subscribers: make(map[*subscriber]struct{})}
+	l.levels.Store(&levelSet{level: slog.LevelInfo})
+	std.Store(l)
+}
+
+// Init 按配置初始化日志，替换之前的输出目标
+func Init(opts Options) error {
+	l := &Logger{dir: opts.Dir, console: opts.Console, subscribers: make(map[*subscriber]struct{})}
+	if err := l.setLevels(opts.Level, opts.Subsystems); err != nil {
+		return err
+	}
+	if opts.Dir != "" {
+		file, err := openRotatingFile(opts.Dir, opts.MaxSize, opts.MaxFiles)
+		if err != nil {
+			return err
+		}
+		l.file = file
+	}
+
+	previous := std.Swap(l)
+	previous.close()
+	return nil
+}
+
+// SetLevels 设置默认日志级别和各子系统的日志级别，可在运行时调用
+func SetLevels(level string, subsystems map[string]string) error {
+	return std.Load().setLevels(level, subsystems)
+}
+
+// Close 关闭日志文件，之后的日志只输出到控制台
+func Close() error {
+	l := std.Load()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// For 返回指定子系统的日志记录器
+func For(subsystem string) *slog.Logger {
+	return slog.New(&handler{subsystem: subsystem})
+}
+
+func (l *Logger) setLevels(level string, subsystems map[string]string) error {
+	set := &levelSet{level: slog.LevelInfo, subsystems: make(map[string]slog.Level)}
+	if level != "" {
+		parsed, err := ParseLevel(level)
+		if err != nil {
+			return err
+		}
+		set.level = parsed
+	}
+	for subsystem, name := range subsystems {
+		if name == "" {
+			continue
+		}
+		parsed, err := ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		set.subsystems[subsystem] = parsed
+	}
+	l.levels.Store(set)
+	return nil
+}
+
+// enabled 判断子系统是否输出该级别的日志
+func (l *Logger) enabled(subsystem string, level slog.Level) bool {
+	set := l.levels.Load()
+	min, ok := set.subsystems[subsystem]
+	if !ok {
+		min = set.level
+	}
+	return level >= min
+}
+
+// write 写入日志文件和控制台，并推送给订阅者
+func (l *Logger) write(entry Entry) {
+	l.mu.Lock()
+	if l.file != nil {
+		if err := l.file.WriteEntry(entry); err != nil && l.console != nil {
+			fmt.Fprintf(l.console, "写入日志文件失败: %v\n", err)
+		}
+	}
+	if l.console != nil {
+		io.WriteString(l.console, formatText(entry))
+	}
+	l.mu.Unlock()
+
+	l.publish(entry)
+}
+
+// close 关闭日志文件并结束所有订阅
+func (l *Logger) close() {
+	l.mu.Lock()
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+	l.mu.Unlock()
+
+	l.subMu.Lock()
+	for sub := range l.subscribers {
+		delete(l.subscribers, sub)
+		close(sub.ch)
+	}
+	l.subMu.Unlock()
+}
+
+// formatText 控制台输出的文本格式
+func formatText(entry Entry) string {
+	var b strings.Builder
+	b.WriteString(entry.Time.Format("2006-01-02 15:04:05.000"))
+	fmt.Fprintf(&b, " %-5s [%s] %s", strings.ToUpper(entry.Level), entry.Subsystem, entry.Message)
+	for _, key := range sortedKeys(entry.Attrs) {
+		fmt.Fprintf(&b, " %s=%v", key, entry.Attrs[key])
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSubsystemLevelsAndQuery 测试按子系统设置日志级别，以及按级别、子系统和时间查询日志文件
+func TestSubsystemLevelsAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	if err := Init(Options{Dir: dir, Level: "info", Subsystems: map[string]string{SubsystemMCP: "debug"}}); err != nil {
+		t.Fatalf("初始化日志失败: %v", err)
+	}
+	defer Close()
+
+	started := time.Now()
+	For(SubsystemAPI).Debug("不应写入")
+	For(SubsystemAPI).Warn("请求失败", "path", "/api/test", "status", 404)
+	For(SubsystemMCP).Debug("连接服务器", "server_id", 1)
+	For(SubsystemMCP).With("server_id", 2).Error("调用工具失败", "error", errString("timeout"))
+
+	entries, total, err := Query(Filter{}, 10)
+	if err != nil {
+		t.Fatalf("查询日志失败: %v", err)
+	}
+	if total != 3 || len(entries) != 3 {
+		t.Fatalf("应写入3条日志，实际 %d 条", total)
+	}
+	if entries[0].Message != "调用工具失败" || entries[0].Attrs["error"] != "timeout" || entries[0].Attrs["server_id"] != float64(2) {
+		t.Fatalf("日志应按时间倒序返回并包含属性: %+v", entries[0])
+	}
+
+	if entries, _, _ := Query(Filter{Level: "warn"}, 10); len(entries) != 2 {
+		t.Fatalf("按最低级别过滤应返回2条，实际 %d 条", len(entries))
+	}
+	if entries, _, _ := Query(Filter{Subsystem: SubsystemMCP, Search: "连接"}, 10); len(entries) != 1 {
+		t.Fatalf("按子系统和关键字过滤应返回1条，实际 %d 条", len(entries))
+	}
+	if entries, _, _ := Query(Filter{Until: started.Add(-time.Second)}, 10); len(entries) != 0 {
+		t.Fatalf("截止时间之前不应有日志，实际 %d 条", len(entries))
+	}
+	if entries, total, _ := Query(Filter{}, 1); len(entries) != 1 || total != 3 {
+		t.Fatalf("限制条数时应返回1条并保留总数，实际 %d 条，总数 %d", len(entries), total)
+	}
+
+	if err := SetLevels("info", nil); err != nil {
+		t.Fatalf("设置日志级别失败: %v", err)
+	}
+	For(SubsystemMCP).Debug("调整级别后不应写入")
+	if _, total, _ := Query(Filter{}, 10); total != 3 {
+		t.Fatalf("调整级别后调试日志不应写入，实际共 %d 条", total)
+	}
+}
+
+// TestRotation 测试日志文件超过大小后轮转并删除多余的历史文件
+func TestRotation(t *testing.T) {
+	dir := t.TempDir()
+	if err := Init(Options{Dir: dir, MaxSize: 200, MaxFiles: 2}); err != nil {
+		t.Fatalf("初始化日志失败: %v", err)
+	}
+	defer Close()
+
+	logs, cancel := Subscribe(Filter{Search: "第"})
+	defer cancel()
+
+	logger := For(SubsystemApp)
+	for i := 0; i < 10; i++ {
+		logger.Info("写入第几条日志", "index", i)
+		// 轮转后的文件按毫秒命名
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	history, err := filepath.Glob(filepath.Join(dir, rotatedPattern))
+	if err != nil {
+		t.Fatalf("读取历史日志失败: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("应保留2个历史日志文件，实际 %d 个", len(history))
+	}
+	if len(logs) != 10 {
+		t.Fatalf("订阅者应收到10条日志，实际 %d 条", len(logs))
+	}
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Filter 日志查询和实时订阅的过滤条件
+type Filter struct {
+	Level     string    // 最低日志级别，为空时不过滤
+	Subsystem string    // 子系统，为空时不过滤
+	Search    string    // 在消息和属性中搜索的文本
+	Since     time.Time // 起始时间，为零值时不限制
+	Until     time.Time // 截止时间，为零值时不限制
+}
+
+// Match 判断日志是否满足过滤条件
+func (f Filter) Match(entry Entry) bool {
+	if f.Level != "" {
+		min, err := ParseLevel(f.Level)
+		level, _ := ParseLevel(entry.Level)
+		if err == nil && level < min {
+			return false
+		}
+	}
+	if f.Subsystem != "" && f.Subsystem != entry.Subsystem {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(entry.Message, f.Search) {
+		found := false
+		for _, value := range entry.Attrs {
+			if strings.Contains(fmt.Sprint(value), f.Search) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// maxLineSize 读取日志文件时单行的最大长度
+const maxLineSize = 1 << 20
+
+// Query 从日志文件中查询满足条件的日志，按时间从新到旧返回最多 limit 条，同时返回匹配的总条数
+func Query(filter Filter, limit int) ([]Entry, int, error) {
+	l := std.Load()
+	if l.dir == "" {
+		return []Entry{}, 0, nil
+	}
+	files, err := logFiles(l.dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("读取日志目录失败: %v", err)
+	}
+
+	// 按时间从旧到新读取，只保留最近的 limit 条
+	var recent []Entry
+	total := 0
+	for _, path := range files {
+		// 最后修改时间早于起始时间的文件中不会有满足条件的日志
+		if info, err := os.Stat(path); err == nil && !filter.Since.IsZero() && info.ModTime().Before(filter.Since) {
+			continue
+		}
+		err := scanFile(path, func(entry Entry) {
+			if !filter.Match(entry) {
+				return
+			}
+			total++
+			recent = append(recent, entry)
+			if limit > 0 && len(recent) > limit {
+				recent = recent[1:]
+			}
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	entries := make([]Entry, len(recent))
+	for i, entry := range recent {
+		entries[len(recent)-1-i] = entry
+	}
+	return entries, total, nil
+}
+
+// scanFile 逐行读取日志文件，无法解析的行（例如写入中断的最后一行）会被跳过
+func scanFile(path string, fn func(Entry)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// 读取过程中文件被轮转删除
+			return nil
+		}
+		return fmt.Errorf("打开日志文件失败: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		fn(entry)
+	}
+	return scanner.Err()
+}
+
+// subscriberBuffer 每个订阅者的缓冲日志数，订阅者处理过慢时丢弃新日志
+const subscriberBuffer = 200
+
+// subscriber 日志实时订阅者
+type subscriber struct {
+	filter Filter
+	ch     chan Entry
+}
+
+// Subscribe 订阅新写入的日志，返回的取消函数必须调用以释放资源
+func Subscribe(filter Filter) (<-chan Entry, func()) {
+	l := std.Load()
+	sub := &subscriber{filter: filter, ch: make(chan Entry, subscriberBuffer)}
+
+	l.subMu.Lock()
+	l.subscribers[sub] = struct{}{}
+	l.subMu.Unlock()
+
+	cancel := func() {
+		l.subMu.Lock()
+		defer l.subMu.Unlock()
+		// 日志重新初始化时订阅已被结束
+		if _, ok := l.subscribers[sub]; ok {
+			delete(l.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+	return sub.ch, cancel
+}
+
+// publish 推送日志给订阅者，不会阻塞写日志的一方
+func (l *Logger) publish(entry Entry) {
+	l.subMu.Lock()
+	defer l.subMu.Unlock()
+	for sub := range l.subscribers {
+		if !sub.filter.Match(entry) {
+			continue
+		}
+		select {
+		case sub.ch <- entry:
+		default:
+		}
+	}
+}
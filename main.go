@@ -2,21 +2,32 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+
+	"desktop-ai-tools/config"
+	"desktop-ai-tools/logging"
 )
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 func main() {
+	// 加载配置（默认值 < 配置文件 < 环境变量 < 命令行参数）
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		logging.For(logging.SubsystemConfig).Error("加载配置失败", "error", err)
+		os.Exit(1)
+	}
+
 	// Create an instance of the app structure
-	app := NewApp()
+	app := NewApp(cfg)
 
 	// Create application with options
-	err := wails.Run(&options.App{
+	err = wails.Run(&options.App{
 		Title:  "desktop-ai-tools",
 		Width:  1024,
 		Height: 768,
@@ -25,12 +36,15 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnBeforeClose:    app.beforeClose,
+		OnShutdown:       app.shutdown,
+		ErrorFormatter:   app.formatBindingError,
 		Bind: []interface{}{
 			app,
 		},
 	})
 
 	if err != nil {
-		println("Error:", err.Error())
+		logging.For(logging.SubsystemApp).Error("应用运行失败", "error", err)
 	}
 }
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 所有指标名称的前缀
+const namespace = "desktop_ai_tools"
+
+// 工具调用结果
+const (
+	ResultSuccess   = "success"    // 调用成功
+	ResultToolError = "tool_error" // 服务器返回 isError=true 的结果
+	ResultError     = "error"      // 连接失败或JSON-RPC请求失败
+	ResultCached    = "cached"     // 使用缓存结果，未请求服务器
+)
+
+// registry 应用自己的指标注册表，不使用全局注册表，避免依赖库注册的指标混入
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP API请求数，按方法、路由和状态码统计",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP API请求的处理时间",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	toolCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mcp_tool_calls_total",
+		Help:      "MCP工具调用次数，按服务器、工具和结果统计",
+	}, []string{"server_id", "server", "tool", "result"})
+
+	toolCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mcp_tool_call_duration_seconds",
+		Help:      "请求MCP服务器调用工具的耗时，不含缓存命中",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"server_id", "server", "tool"})
+
+	discoveryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mcp_tool_discovery_duration_seconds",
+		Help:      "从MCP服务器获取工具列表的耗时，包括建立连接",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"server_id", "server", "result"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "数据库操作的执行时间，按操作类型和表统计",
+		Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"operation", "table"})
+
+	dbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "执行失败的数据库操作数，不含查询无记录",
+	}, []string{"operation", "table"})
+)
+
+func init() {
+	registry.MustRegister(
+		httpRequests, httpDuration,
+		toolCalls, toolCallDuration, discoveryDuration,
+		dbQueryDuration, dbQueryErrors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		stateCollector{},
+	)
+}
+
+// Handler 返回以Prometheus文本格式输出所有指标的处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// ObserveHTTPRequest 记录一次HTTP API请求，route 为路由模板（如 /api/mcp-servers/:id），避免按ID产生大量标签
+func ObserveHTTPRequest(method, route string, status int, elapsed time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// ObserveToolCall 记录一次工具调用，缓存命中的调用只计数，不记录耗时
+func ObserveToolCall(serverID uint, server, tool, result string, elapsed time.Duration) {
+	id := formatID(serverID)
+	toolCalls.WithLabelValues(id, server, tool, result).Inc()
+	if result != ResultCached {
+		toolCallDuration.WithLabelValues(id, server, tool).Observe(elapsed.Seconds())
+	}
+}
+
+// ObserveDiscovery 记录一次工具发现
+func ObserveDiscovery(serverID uint, server string, err error, elapsed time.Duration) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+	discoveryDuration.WithLabelValues(formatID(serverID), server, result).Observe(elapsed.Seconds())
+}
+
+// ObserveDBQuery 记录一次数据库操作
+func ObserveDBQuery(operation, table string, elapsed time.Duration, failed bool) {
+	dbQueryDuration.WithLabelValues(operation, table).Observe(elapsed.Seconds())
+	if failed {
+		dbQueryErrors.WithLabelValues(operation, table).Inc()
+	}
+}
+
+// ServerState 抓取指标时服务器的状态快照
+type ServerState struct {
+	ID       uint
+	Name     string
+	Status   string // active, inactive, error
+	Enabled  bool
+	Sessions int // 当前已连接的会话数
+}
+
+// StateSource 抓取指标时读取应用实时状态的回调，未设置的回调不输出对应指标
+type StateSource struct {
+	Servers func() ([]ServerState, error)
+	Queues  func() map[string]int // 各队列中等待处理的任务数，例如等待确认的采样请求
+}
+
+var (
+	sourceMu sync.RWMutex
+	source   StateSource
+)
+
+// SetStateSource 设置读取服务器状态和队列长度的回调
+func SetStateSource(s StateSource) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	source = s
+}
+
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHandler 测试记录的请求、工具调用和抓取时读取的状态都以Prometheus文本格式输出
+func TestHandler(t *testing.T) {
+	ObserveHTTPRequest(http.MethodGet, "/api/mcp-servers/:id", http.StatusOK, 20*time.Millisecond)
+	ObserveToolCall(1, "fs", "read_file", ResultSuccess, 150*time.Millisecond)
+	ObserveToolCall(1, "fs", "read_file", ResultCached, 0)
+	ObserveDiscovery(1, "fs", errors.New("timeout"), time.Second)
+	ObserveDBQuery("query", "mcp_servers", time.Millisecond, false)
+
+	SetStateSource(StateSource{
+		Servers: func() ([]ServerState, error) {
+			return []ServerState{{ID: 1, Name: "fs", Status: "active", Enabled: true, Sessions: 2}}, nil
+		},
+		Queues: func() map[string]int { return map[string]int{"sampling": 3} },
+	})
+	defer SetStateSource(StateSource{})
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("获取指标失败: %d", w.Code)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		`desktop_ai_tools_http_requests_total{method="GET",route="/api/mcp-servers/:id",status="200"} 1`,
+		`desktop_ai_tools_mcp_tool_calls_total{result="cached",server="fs",server_id="1",tool="read_file"} 1`,
+		`desktop_ai_tools_mcp_tool_call_duration_seconds_count{server="fs",server_id="1",tool="read_file"} 1`,
+		`desktop_ai_tools_mcp_tool_discovery_duration_seconds_count{result="error",server="fs",server_id="1"} 1`,
+		`desktop_ai_tools_db_query_duration_seconds_count{operation="query",table="mcp_servers"} 1`,
+		`desktop_ai_tools_mcp_server_status{server="fs",server_id="1",status="active"} 1`,
+		`desktop_ai_tools_mcp_server_status{server="fs",server_id="1",status="error"} 0`,
+		`desktop_ai_tools_mcp_server_sessions{server="fs",server_id="1"} 2`,
+		`desktop_ai_tools_queue_depth{queue="sampling"} 3`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("指标输出中缺少: %s", want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// serverStatuses 服务器可能的状态，每个状态输出一条序列，当前状态为1
+var serverStatuses = []string{"active", "inactive", "error"}
+
+var (
+	serverStatusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "mcp", "server_status"),
+		"MCP服务器的连接状态，当前状态的值为1",
+		[]string{"server_id", "server", "status"}, nil,
+	)
+	serverEnabledDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "mcp", "server_enabled"),
+		"MCP服务器是否启用",
+		[]string{"server_id", "server"}, nil,
+	)
+	serverSessionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "mcp", "server_sessions"),
+		"MCP服务器当前已连接的会话数",
+		[]string{"server_id", "server"}, nil,
+	)
+	queueDepthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "queue_depth"),
+		"各队列中等待处理的任务数",
+		[]string{"queue"}, nil,
+	)
+)
+
+// stateCollector 在抓取时读取服务器状态和队列长度
+type stateCollector struct{}
+
+// Describe 实现 prometheus.Collector
+func (stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- serverStatusDesc
+	ch <- serverEnabledDesc
+	ch <- serverSessionsDesc
+	ch <- queueDepthDesc
+}
+
+// Collect 实现 prometheus.Collector
+func (stateCollector) Collect(ch chan<- prometheus.Metric) {
+	sourceMu.RLock()
+	s := source
+	sourceMu.RUnlock()
+
+	if s.Servers != nil {
+		servers, err := s.Servers()
+		if err != nil {
+			// 读取失败时其他指标照常输出
+			ch <- prometheus.NewInvalidMetric(serverStatusDesc, err)
+		}
+		for _, server := range servers {
+			id := formatID(server.ID)
+			for _, status := range serverStatuses {
+				ch <- prometheus.MustNewConstMetric(serverStatusDesc, prometheus.GaugeValue, boolValue(server.Status == status), id, server.Name, status)
+			}
+			ch <- prometheus.MustNewConstMetric(serverEnabledDesc, prometheus.GaugeValue, boolValue(server.Enabled), id, server.Name)
+			ch <- prometheus.MustNewConstMetric(serverSessionsDesc, prometheus.GaugeValue, float64(server.Sessions), id, server.Name)
+		}
+	}
+
+	if s.Queues != nil {
+		for queue, depth := range s.Queues() {
+			ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(depth), queue)
+		}
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"desktop-ai-tools/models"
+)
+
+// RequestSourceHeader 标识请求来源的请求头，桌面界面发出的请求设置为 ui
+const RequestSourceHeader = "X-Request-Source"
+
+// AuditSource 根据请求头在请求上下文中设置审计来源，未标识来源的请求视为外部API调用
+func AuditSource() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		source := models.AuditSourceAPI
+		if c.GetHeader(RequestSourceHeader) == models.AuditSourceUI {
+			source = models.AuditSourceUI
+		}
+		c.Request = c.Request.WithContext(models.WithAuditSource(c.Request.Context(), source))
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"desktop-ai-tools/apperrors"
+)
+
+// GenerateAPIToken 生成随机的API令牌，每次启动重新生成
+func GenerateAPIToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成API令牌失败: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// APIToken 校验请求携带的API令牌，skipPaths 中的路径无需令牌
+// 令牌通过 Authorization: Bearer <token> 请求头传递，无法设置请求头的场景（如 EventSource、下载链接）可使用 token 查询参数
+func APIToken(token string, skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions || skip[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		provided := c.Query("token")
+		if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			provided = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			apiLog.Warn("拒绝未授权的API请求", "method", c.Request.Method, "path", c.Request.URL.Path, "client_ip", c.ClientIP(), "origin", c.GetHeader("Origin"))
+			_ = c.Error(apperrors.Unauthorized(apperrors.CodeUnauthorized, "缺少或无效的API令牌"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
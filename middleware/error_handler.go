@@ -2,26 +2,22 @@ package middleware
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/i18n"
+	"desktop-ai-tools/logging"
+	"desktop-ai-tools/models"
 	"desktop-ai-tools/utils"
 )
 
-// ErrorResponse 统一错误响应结构
-type ErrorResponse struct {
-	Success   bool        `json:"success"`
-	Message   string      `json:"message"`
-	Error     string      `json:"error,omitempty"`
-	Details   interface{} `json:"details,omitempty"`
-	Timestamp string      `json:"timestamp"`
-	Path      string      `json:"path"`
-	Method    string      `json:"method"`
-	Stack     []string    `json:"stack,omitempty"`
-}
+// apiLog API子系统的日志记录器
+var apiLog = logging.For(logging.SubsystemAPI)
 
 // ErrorHandlerConfig 错误处理配置
 type ErrorHandlerConfig struct {
@@ -74,10 +70,11 @@ func handlePanic(c *gin.Context, err interface{}, cfg ErrorHandlerConfig) {
 	
 	// 记录错误日志
 	if cfg.LogErrors {
-		fmt.Printf("[ERROR] %s %s - Panic: %v\n", c.Request.Method, c.Request.URL.Path, err)
+		attrs := []any{"method", c.Request.Method, "path", c.Request.URL.Path, "panic", fmt.Sprint(err)}
 		if cfg.ShowStack {
-			fmt.Printf("Stack trace:\n%s\n", strings.Join(stack, "\n"))
+			attrs = append(attrs, "stack", strings.Join(stack, "\n"))
 		}
+		apiLog.Error("处理请求时发生panic", attrs...)
 	}
 	
 	// 使用统一的panic响应格式
@@ -85,6 +82,7 @@ func handlePanic(c *gin.Context, err interface{}, cfg ErrorHandlerConfig) {
 }
 
 // handleErrors 处理普通错误
+// 处理函数通过 c.Error 记录错误，这里按错误类别统一转换为HTTP状态码和响应结构
 func handleErrors(c *gin.Context, errors []*gin.Error, cfg ErrorHandlerConfig) {
 	// 获取最后一个错误
 	if len(errors) == 0 {
@@ -92,45 +90,46 @@ func handleErrors(c *gin.Context, errors []*gin.Error, cfg ErrorHandlerConfig) {
 	}
 	lastError := errors[len(errors)-1]
 	
-	// 记录错误日志
-	if cfg.LogErrors {
-		fmt.Printf("[ERROR] %s %s - Error: %v\n", c.Request.Method, c.Request.URL.Path, lastError.Error())
-	}
+	// 普通错误由 LogErrors 中间件记录到应用日志
 
 	// 如果已经写入了响应，则不再处理
 	if c.Writer.Written() {
 		return
 	}
 
-	// 构建错误响应
-	response := ErrorResponse{
-		Success:   false,
-		Message:   "请求处理失败",
-		Timestamp: time.Now().Format(time.RFC3339),
-		Path:      c.Request.URL.Path,
-		Method:    c.Request.Method,
-	}
+	appErr := responseError(lastError)
 
-	// 根据配置决定是否显示详细信息
-	if cfg.ShowDetails {
-		response.Error = lastError.Error()
-		response.Details = map[string]interface{}{
-			"error_type":  fmt.Sprintf("%d", lastError.Type),
-			"user_agent":  c.Request.UserAgent(),
-			"remote_addr": c.ClientIP(),
-		}
+	// 根据配置决定是否显示底层错误
+	detail := ""
+	if cfg.ShowDetails && appErr.Err != nil {
+		detail = appErr.Err.Error()
 	}
 
-	// 确定HTTP状态码
-	statusCode := http.StatusInternalServerError
-	if lastError.Type == gin.ErrorTypeBind {
-		statusCode = http.StatusBadRequest
-		response.Message = "请求参数错误"
+	utils.ErrorResponse(c, appErr.HTTPStatus(), appErr.Code, LocalizedMessage(i18n.FromContext(c), appErr), detail)
+}
+
+// responseError 将处理函数记录的错误转换为返回给客户端的应用错误，请求绑定失败视为参数错误
+func responseError(err *gin.Error) *apperrors.Error {
+	appErr := apperrors.From(err.Err)
+	if err.Type == gin.ErrorTypeBind && appErr.Kind == apperrors.KindInternal {
+		appErr = apperrors.InvalidRequest(err.Err)
 	}
+	return appErr
+}
 
-	// 设置响应头并返回JSON
-	c.Header("Content-Type", "application/json")
-	c.AbortWithStatusJSON(statusCode, response)
+// LocalizedMessage 按指定语言生成错误消息，消息目录中没有该错误码时使用错误自带的消息
+func LocalizedMessage(locale i18n.Locale, appErr *apperrors.Error) string {
+	message := appErr.Message
+	if _, ok := i18n.Lookup(locale, appErr.Code); ok {
+		message = i18n.Message(locale, appErr.Code, appErr.Args...)
+	}
+	// 请求参数校验失败时附带每个字段的原因
+	if appErr.Code == apperrors.CodeInvalidRequest {
+		if fields, ok := i18n.ValidationMessage(locale, appErr.Err); ok {
+			message += ": " + fields
+		}
+	}
+	return message
 }
 
 // getStackTrace 获取堆栈跟踪信息
@@ -169,23 +168,52 @@ func RecoveryWithWriter() gin.HandlerFunc {
 	})
 }
 
-// LogErrors 错误日志中间件
+// LogErrors 错误日志中间件，将请求处理中记录的错误写入应用日志
+// 服务端错误记录为 error 级别，请求参数、资源不存在等客户端错误记录为 warn 级别
 func LogErrors() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
-		
-		// 记录所有错误
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		// 错误响应由外层的 ErrorHandler 在之后写入，此时按最后一个错误推算实际返回的状态码
+		status := c.Writer.Status()
+		if !c.Writer.Written() {
+			status = responseError(c.Errors.Last()).HTTPStatus()
+		}
+		level := slog.LevelWarn
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
 		for _, err := range c.Errors {
-			utils.PrintJSON(map[string]interface{}{
-				"timestamp":   time.Now().Format(time.RFC3339),
-				"method":      c.Request.Method,
-				"path":        c.Request.URL.Path,
-				"status_code": c.Writer.Status(),
-				"error":       err.Error(),
-				"error_type":  fmt.Sprintf("%d", err.Type),
-				"client_ip":   c.ClientIP(),
-				"user_agent":  c.Request.UserAgent(),
-			}, "API错误日志")
+			apiLog.Log(c.Request.Context(), level, "API请求错误",
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"status", status,
+				"code", apperrors.From(err.Err).Code,
+				"request_id", models.RequestIDFrom(c.Request.Context()),
+				"error", err.Error(),
+				"client_ip", c.ClientIP(),
+				"user_agent", c.Request.UserAgent(),
+			)
 		}
 	}
+}
+
+// RequestLogger 请求日志中间件，替代 gin 默认的控制台日志，每个请求记录为 debug 级别
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		c.Next()
+
+		apiLog.Debug("API请求",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"elapsed", time.Since(started),
+			"request_id", models.RequestIDFrom(c.Request.Context()),
+			"client_ip", c.ClientIP(),
+		)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/logging"
+	"desktop-ai-tools/utils"
+)
+
+// TestErrorHandlerMapping 测试错误处理中间件按错误类别返回状态码和错误码
+func TestErrorHandlerMapping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"不存在", apperrors.NotFound("server_not_found", "服务器不存在"), http.StatusNotFound, "server_not_found"},
+		{"包装后的冲突", fmt.Errorf("创建失败: %w", apperrors.Conflict("server_name_exists", "服务器名称已存在")), http.StatusConflict, "server_name_exists"},
+		{"校验失败", apperrors.Validation("validation_failed", "服务器名称不能为空"), http.StatusBadRequest, "validation_failed"},
+		{"上游失败", apperrors.Upstream("mcp_connect_failed", "连接 MCP 服务器失败", errors.New("connection refused")), http.StatusBadGateway, "mcp_connect_failed"},
+		{"上游超时", apperrors.Upstream("mcp_connect_failed", "连接 MCP 服务器失败", context.DeadlineExceeded), http.StatusGatewayTimeout, apperrors.CodeTimeout},
+		{"未找到记录", gorm.ErrRecordNotFound, http.StatusNotFound, apperrors.CodeNotFound},
+		{"未分类错误", errors.New("磁盘已满"), http.StatusInternalServerError, apperrors.CodeInternal},
+	}
+
+	for _, tc := range cases {
+		router := gin.New()
+		router.Use(ErrorHandler(ErrorHandlerConfig{ShowDetails: true}))
+		router.GET("/", func(c *gin.Context) {
+			_ = c.Error(tc.err)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		var resp utils.APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: 解析响应失败: %v", tc.name, err)
+		}
+		if w.Code != tc.status || resp.Code != tc.code || resp.Success || resp.Message == "" {
+			t.Fatalf("%s: 响应不正确: %d %+v", tc.name, w.Code, resp)
+		}
+	}
+}
+
+// TestErrorHandlerLocalized 测试错误消息和请求参数校验错误按请求语言翻译
+func TestErrorHandlerLocalized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	language := ""
+	router := gin.New()
+	router.Use(ErrorHandler(), Locale(func() string { return language }))
+	router.GET("/servers/:id", func(c *gin.Context) {
+		_ = c.Error(apperrors.InvalidID(c.Param("id")))
+	})
+	router.POST("/servers", func(c *gin.Context) {
+		var req struct {
+			Name string `json:"name" binding:"required"`
+			Mode string `json:"mode" binding:"omitempty,oneof=a b"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(apperrors.InvalidRequest(err))
+		}
+	})
+
+	request := func(method, path, body, acceptLanguage string) utils.APIResponse {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp utils.APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		return resp
+	}
+
+	if resp := request(http.MethodGet, "/servers/abc", "", "en-US,en;q=0.9"); resp.Message != "Invalid ID: abc" {
+		t.Fatalf("英文错误消息不正确: %s", resp.Message)
+	}
+	if resp := request(http.MethodGet, "/servers/abc", "", ""); resp.Message != "无效的ID: abc" {
+		t.Fatalf("默认应使用中文错误消息: %s", resp.Message)
+	}
+	if resp := request(http.MethodPost, "/servers", `{"mode":"c"}`, "en"); resp.Message != "Invalid request parameters: name is required; mode must be one of: a, b" {
+		t.Fatalf("参数校验错误翻译不正确: %s", resp.Message)
+	}
+
+	// 设置中指定的语言优先于请求头
+	language = "zh-CN"
+	if resp := request(http.MethodPost, "/servers", `{}`, "en"); resp.Message != "请求参数错误: name 为必填项" {
+		t.Fatalf("应使用设置中指定的语言: %s", resp.Message)
+	}
+}
+
+// TestLogErrorsStatus 测试错误日志记录实际返回的状态码，服务端错误记录为 error 级别
+func TestLogErrorsStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler(), LogErrors())
+	router.GET("/internal", func(c *gin.Context) {
+		_ = c.Error(errors.New("磁盘已满"))
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		_ = c.Error(apperrors.NotFound("server_not_found", "服务器不存在"))
+	})
+
+	logs, cancel := logging.Subscribe(logging.Filter{Subsystem: logging.SubsystemAPI, Search: "API请求错误"})
+	defer cancel()
+
+	cases := []struct {
+		path   string
+		status int
+		level  string
+	}{
+		{"/internal", http.StatusInternalServerError, "error"},
+		{"/missing", http.StatusNotFound, "warn"},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.status {
+			t.Fatalf("%s: 响应状态码应为 %d，实际 %d", tc.path, tc.status, w.Code)
+		}
+
+		var entry logging.Entry
+		select {
+		case entry = <-logs:
+		case <-time.After(time.Second):
+			t.Fatalf("%s: 未记录错误日志", tc.path)
+		}
+		if entry.Level != tc.level || fmt.Sprint(entry.Attrs["status"]) != fmt.Sprint(tc.status) {
+			t.Fatalf("%s: 日志应为 %s 级别且状态码为 %d: %+v", tc.path, tc.level, tc.status, entry)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"desktop-ai-tools/i18n"
+)
+
+var registerFieldNames sync.Once
+
+// Locale 确定请求的消息语言：设置中指定了语言时使用该语言，否则按 Accept-Language 请求头选择
+// preferred 返回设置中的语言，每次请求读取以便修改设置后立即生效
+func Locale(preferred func() string) gin.HandlerFunc {
+	// 校验错误中的字段名使用请求中的字段名
+	registerFieldNames.Do(func() {
+		if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+			v.RegisterTagNameFunc(i18n.FieldName)
+		}
+	})
+
+	return func(c *gin.Context) {
+		locale, ok := i18n.Parse(preferred())
+		if !ok {
+			if locale, ok = i18n.FromAcceptLanguage(c.GetHeader("Accept-Language")); !ok {
+				locale = i18n.DefaultLocale
+			}
+		}
+		c.Set(i18n.ContextKey, locale)
+		c.Header("Content-Language", string(locale))
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"desktop-ai-tools/metrics"
+)
+
+// unmatchedRoute 未匹配任何路由的请求统一使用的标签，避免按请求路径产生大量标签
+const unmatchedRoute = "unmatched"
+
+// Metrics 按路由模板统计请求数和处理时间
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(started))
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"desktop-ai-tools/models"
+)
+
+// RequestIDHeader 请求ID请求头，调用方未提供时自动生成，并在响应中返回
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID 调用方提供的请求ID只允许字母、数字和常见分隔符
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestID 为每个请求设置请求ID，MCP流量记录和日志据此关联到请求
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = NewRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(models.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// NewRequestID 生成随机的请求ID
+func NewRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
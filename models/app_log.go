@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"desktop-ai-tools/logging"
+)
+
+// AppLogListRequest 应用日志查询请求
+type AppLogListRequest struct {
+	Level     string    `json:"level" form:"level" binding:"omitempty,oneof=debug info warn error"` // 最低日志级别
+	Subsystem string    `json:"subsystem" form:"subsystem" binding:"omitempty,oneof=app api config database mcp backup"`
+	Search    string    `json:"search" form:"search"`
+	Since     time.Time `json:"since" form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until     time.Time `json:"until" form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+	Follow    bool      `json:"-" form:"follow"` // 为true时通过SSE持续推送新日志
+	Limit     int       `json:"limit" form:"limit,default=200" binding:"min=1,max=5000"`
+}
+
+// Filter 转换为日志过滤条件
+func (r *AppLogListRequest) Filter() logging.Filter {
+	return logging.Filter{
+		Level:     r.Level,
+		Subsystem: r.Subsystem,
+		Search:    r.Search,
+		Since:     r.Since,
+		Until:     r.Until,
+	}
+}
+
+// AppLogListResponse 应用日志列表响应，日志按时间从新到旧排列
+type AppLogListResponse struct {
+	Total      int             `json:"total"` // 满足条件的日志总数，可能多于返回的条数
+	Logs       []logging.Entry `json:"logs"`
+	Subsystems []string        `json:"subsystems"` // 所有子系统，供界面筛选
+}
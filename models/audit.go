@@ -0,0 +1,374 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 审计对象类型
+const (
+	AuditEntityServer = "server"
+	AuditEntityTool   = "tool"
+)
+
+// 审计操作类型
+const (
+	AuditActionCreate      = "create"
+	AuditActionUpdate      = "update"
+	AuditActionDelete      = "delete"
+	AuditActionToggle      = "toggle"
+	AuditActionBatchUpdate = "batch_update"
+)
+
+// 审计来源
+const (
+	AuditSourceUI     = "ui"     // 桌面界面
+	AuditSourceAPI    = "api"    // 外部调用HTTP API
+	AuditSourceImport = "import" // 从客户端配置文件导入
+	AuditSourceBackup = "backup" // 从备份恢复
+	AuditSourceSystem = "system" // 应用内部操作，如工具发现
+)
+
+// AuditLog 配置变更审计日志，记录服务器和工具变更前后的内容
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"size:20;index"`
+	EntityID   uint      `json:"entity_id" gorm:"index"`
+	EntityName string    `json:"entity_name" gorm:"size:100"`
+	Action     string    `json:"action" gorm:"size:20;index"`
+	Source     string    `json:"source" gorm:"size:20;index"`
+	Before     string    `json:"before" gorm:"type:text"` // 变更前的JSON，创建时为空，敏感字段已掩码
+	After      string    `json:"after" gorm:"type:text"`  // 变更后的JSON，删除时为空，敏感字段已掩码
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditLogListRequest 审计日志查询请求
+type AuditLogListRequest struct {
+	EntityType string    `form:"entity_type" binding:"omitempty,oneof=server tool"`
+	EntityID   uint      `form:"entity_id"`
+	Action     string    `form:"action" binding:"omitempty,oneof=create update delete toggle batch_update"`
+	Source     string    `form:"source" binding:"omitempty,oneof=ui api import backup system"`
+	Search     string    `form:"search"` // 按对象名称模糊匹配
+	Since      time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until      time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page       int       `form:"page,default=1" binding:"min=1"`
+	Size       int       `form:"size,default=50" binding:"min=1,max=200"`
+}
+
+// AuditLogListResponse 审计日志列表响应
+type AuditLogListResponse struct {
+	Total int64      `json:"total"`
+	Page  int        `json:"page"`
+	Size  int        `json:"size"`
+	Logs  []AuditLog `json:"logs"`
+}
+
+type auditContextKey string
+
+const (
+	auditSourceKey auditContextKey = "audit_source"
+	auditActionKey auditContextKey = "audit_action"
+)
+
+// WithAuditSource 在上下文中设置审计来源
+func WithAuditSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, auditSourceKey, source)
+}
+
+// WithAuditAction 在上下文中设置审计操作类型，用于区分切换启用状态、批量更新等更新操作
+func WithAuditAction(ctx context.Context, action string) context.Context {
+	return context.WithValue(ctx, auditActionKey, action)
+}
+
+// AuditSourceFrom 获取上下文中的审计来源，未设置时为系统操作
+func AuditSourceFrom(ctx context.Context) string {
+	if ctx != nil {
+		if source, ok := ctx.Value(auditSourceKey).(string); ok && source != "" {
+			return source
+		}
+	}
+	return AuditSourceSystem
+}
+
+// auditAction 获取上下文中的审计操作类型
+func auditAction(ctx context.Context, fallback string) string {
+	if ctx != nil {
+		if action, ok := ctx.Value(auditActionKey).(string); ok && action != "" {
+			return action
+		}
+	}
+	return fallback
+}
+
+// auditBeforeKey 变更前快照在语句设置中的键名
+const auditBeforeKey = "audit:before"
+
+// auditIgnoredFields 比较变更时忽略的字段，只有这些字段变化时不记录审计日志
+var auditIgnoredFields = []string{"status", "updated_at"}
+
+// AfterCreate 创建后记录审计日志
+func (m *MCPServer) AfterCreate(tx *gorm.DB) error {
+	return recordAuditCreate(tx, AuditEntityServer, m.ID, m.Name, *m)
+}
+
+// BeforeUpdate 更新前保存变更前的快照
+func (m *MCPServer) BeforeUpdate(tx *gorm.DB) error {
+	return loadAuditBefore[MCPServer](tx, m.ID)
+}
+
+// AfterUpdate 更新后记录审计日志
+func (m *MCPServer) AfterUpdate(tx *gorm.DB) error {
+	return recordAuditChanges(tx, AuditEntityServer, AuditActionUpdate, func(s MCPServer) (uint, string) { return s.ID, s.Name })
+}
+
+// BeforeDelete 删除前保存变更前的快照
+func (m *MCPServer) BeforeDelete(tx *gorm.DB) error {
+	return loadAuditBefore[MCPServer](tx, m.ID)
+}
+
+// AfterDelete 删除后记录审计日志
+func (m *MCPServer) AfterDelete(tx *gorm.DB) error {
+	return recordAuditChanges(tx, AuditEntityServer, AuditActionDelete, func(s MCPServer) (uint, string) { return s.ID, s.Name })
+}
+
+// AfterCreate 创建后记录审计日志
+func (m *MCPTool) AfterCreate(tx *gorm.DB) error {
+	return recordAuditCreate(tx, AuditEntityTool, m.ID, m.Name, *m)
+}
+
+// BeforeUpdate 更新前保存变更前的快照
+func (m *MCPTool) BeforeUpdate(tx *gorm.DB) error {
+	return loadAuditBefore[MCPTool](tx, m.ID)
+}
+
+// AfterUpdate 更新后记录审计日志
+func (m *MCPTool) AfterUpdate(tx *gorm.DB) error {
+	return recordAuditChanges(tx, AuditEntityTool, AuditActionUpdate, func(t MCPTool) (uint, string) { return t.ID, t.Name })
+}
+
+// BeforeDelete 删除前保存变更前的快照
+func (m *MCPTool) BeforeDelete(tx *gorm.DB) error {
+	return loadAuditBefore[MCPTool](tx, m.ID)
+}
+
+// AfterDelete 删除后记录审计日志
+func (m *MCPTool) AfterDelete(tx *gorm.DB) error {
+	return recordAuditChanges(tx, AuditEntityTool, AuditActionDelete, func(t MCPTool) (uint, string) { return t.ID, t.Name })
+}
+
+// loadAuditBefore 按语句的查询条件加载受影响的记录，保存为变更前的快照
+// 钩子中的 tx 与原语句共享 Statement，可以读取原语句的条件和设置
+func loadAuditBefore[T any](tx *gorm.DB, id uint) error {
+	if _, ok := tx.Statement.Settings.Load(auditBeforeKey); ok {
+		// 批量操作时每条记录都会调用钩子，只需加载一次
+		return nil
+	}
+
+	query := tx.Session(&gorm.Session{NewDB: true}).Model(new(T)).Preload("Tags")
+	if where, ok := tx.Statement.Clauses["WHERE"]; ok {
+		query = query.Clauses(where.Expression)
+	} else if id == 0 {
+		// 没有条件时只可能是全表操作
+		query = query.Where("1 = 1")
+	}
+	if id != 0 {
+		query = query.Where("id = ?", id)
+	}
+
+	var rows []T
+	if err := query.Find(&rows).Error; err != nil {
+		return fmt.Errorf("读取审计快照失败: %v", err)
+	}
+	tx.Statement.Settings.Store(auditBeforeKey, rows)
+	return nil
+}
+
+// recordAuditCreate 记录创建操作
+func recordAuditCreate(tx *gorm.DB, entityType string, id uint, name string, value interface{}) error {
+	after, err := auditSnapshot(value)
+	if err != nil {
+		return err
+	}
+	return saveAuditLog(tx, &AuditLog{
+		EntityType: entityType,
+		EntityID:   id,
+		EntityName: name,
+		Action:     AuditActionCreate,
+		After:      after,
+	})
+}
+
+// recordAuditChanges 对比变更前后的快照，为每条有变化的记录写入审计日志
+func recordAuditChanges[T any](tx *gorm.DB, entityType, action string, identify func(T) (uint, string)) error {
+	value, ok := tx.Statement.Settings.LoadAndDelete(auditBeforeKey)
+	if !ok {
+		return nil
+	}
+	if action == AuditActionUpdate {
+		action = auditAction(tx.Statement.Context, action)
+	}
+	return recordAuditDiff(tx, entityType, action, value.([]T), identify)
+}
+
+// recordAuditDiff 重新加载变更前快照中的记录，为有变化的记录写入审计日志，删除操作不重新加载
+func recordAuditDiff[T any](tx *gorm.DB, entityType, action string, before []T, identify func(T) (uint, string)) error {
+	if len(before) == 0 {
+		return nil
+	}
+
+	after := make(map[uint]T, len(before))
+	if action != AuditActionDelete {
+		ids := make([]uint, 0, len(before))
+		for _, row := range before {
+			id, _ := identify(row)
+			ids = append(ids, id)
+		}
+		rows, err := loadAuditRows[T](tx, ids)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			id, _ := identify(row)
+			after[id] = row
+		}
+	}
+
+	for _, row := range before {
+		id, name := identify(row)
+		entry := &AuditLog{EntityType: entityType, EntityID: id, EntityName: name, Action: action}
+
+		var err error
+		if entry.Before, err = auditSnapshot(row); err != nil {
+			return err
+		}
+		if next, ok := after[id]; ok {
+			if !auditChanged(row, next) {
+				continue
+			}
+			_, entry.EntityName = identify(next)
+			if entry.After, err = auditSnapshot(next); err != nil {
+				return err
+			}
+		}
+		if err := saveAuditLog(tx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadAuditRows 按ID加载记录及其标签，用于生成审计快照
+func loadAuditRows[T any](tx *gorm.DB, ids []uint) ([]T, error) {
+	var rows []T
+	if len(ids) == 0 {
+		return rows, nil
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true}).Preload("Tags").Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("读取审计快照失败: %v", err)
+	}
+	return rows, nil
+}
+
+// AuditTagChanges 记录标签变更：在 change 执行前后对比指定服务器和工具的标签，为有变化的记录写入审计日志
+// 标签保存在关联表中，修改关联表不会触发服务器和工具的更新钩子，需要由调用方指定受影响的记录
+func AuditTagChanges(tx *gorm.DB, serverIDs, toolIDs []uint, change func() error) error {
+	servers, err := loadAuditRows[MCPServer](tx, serverIDs)
+	if err != nil {
+		return err
+	}
+	tools, err := loadAuditRows[MCPTool](tx, toolIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	if err := recordAuditDiff(tx, AuditEntityServer, AuditActionUpdate, servers, func(s MCPServer) (uint, string) { return s.ID, s.Name }); err != nil {
+		return err
+	}
+	return recordAuditDiff(tx, AuditEntityTool, AuditActionUpdate, tools, func(t MCPTool) (uint, string) { return t.ID, t.Name })
+}
+
+// saveAuditLog 在原语句的事务中写入审计日志
+func saveAuditLog(tx *gorm.DB, entry *AuditLog) error {
+	entry.Source = AuditSourceFrom(tx.Statement.Context)
+	if err := tx.Session(&gorm.Session{NewDB: true}).Omit(clause.Associations).Create(entry).Error; err != nil {
+		return fmt.Errorf("写入审计日志失败: %v", err)
+	}
+	return nil
+}
+
+// auditSnapshot 序列化记录用于审计，去除关联数据并掩码敏感字段
+func auditSnapshot(value interface{}) (string, error) {
+	if server, ok := value.(MCPServer); ok {
+		server.Redact()
+		value = server
+	}
+	fields, err := auditFields(value)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("序列化审计快照失败: %v", err)
+	}
+	return string(data), nil
+}
+
+// auditChanged 判断记录是否有需要审计的变化，比较时使用未掩码的原始值
+func auditChanged(before, after interface{}) bool {
+	prev, err := auditFields(before)
+	if err != nil {
+		return true
+	}
+	next, err := auditFields(after)
+	if err != nil {
+		return true
+	}
+	for _, field := range auditIgnoredFields {
+		delete(prev, field)
+		delete(next, field)
+	}
+	return !reflect.DeepEqual(prev, next)
+}
+
+// auditFields 将记录转换为字段映射，去除关联数据，标签只保留排序后的名称
+func auditFields(value interface{}) (map[string]interface{}, error) {
+	var tags TagList
+	switch v := value.(type) {
+	case MCPServer:
+		tags = v.Tags
+	case MCPTool:
+		tags = v.Tags
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("序列化审计快照失败: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("序列化审计快照失败: %v", err)
+	}
+	for _, association := range []string{"tools", "tags", "server"} {
+		delete(fields, association)
+	}
+	names := tags.Names()
+	sort.Strings(names)
+	fields["tags"] = names
+	return fields, nil
+}
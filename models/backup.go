@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+// BackupFormatVersion 备份文件格式版本
+const BackupFormatVersion = 1
+
+// 恢复模式
+const (
+	RestoreModeMerge   = "merge"   // 按名称合并到现有数据
+	RestoreModeReplace = "replace" // 清空现有数据后恢复
+)
+
+// 备份中不包含的内容，记录在清单的 excludes 中
+const (
+	// BackupExcludesConfigFile 配置文件保存监听地址、数据库路径、日志目录等与本机环境相关的设置，不备份也不恢复
+	BackupExcludesConfigFile = "config_file"
+)
+
+// BackupManifest 备份文件的清单信息，保存在压缩包的 manifest.json 中
+type BackupManifest struct {
+	FormatVersion   int            `json:"format_version"`
+	SchemaVersion   int            `json:"schema_version"`
+	CreatedAt       time.Time      `json:"created_at"`
+	Automatic       bool           `json:"automatic"`
+	IncludesSecrets bool           `json:"includes_secrets"`
+	IncludesHistory bool           `json:"includes_history"`
+	Note            string         `json:"note,omitempty"`
+	Excludes        []string       `json:"excludes"` // 备份中不包含、恢复时不会改变的内容
+	Counts          map[string]int `json:"counts"`
+}
+
+// BackupData 备份的数据内容，保存在压缩包的 data.json 中
+type BackupData struct {
+	Workspaces     []Workspace     `json:"workspaces"` // 旧版本备份中没有工作区，恢复时服务器归入默认工作区
+	Servers        []MCPServer     `json:"servers"`
+	Tools          []MCPTool       `json:"tools"`
+	SamplingConfig *SamplingConfig `json:"sampling_config,omitempty"`
+	BackupSchedule *BackupSchedule `json:"backup_schedule,omitempty"`
+	Roots          []WorkspaceRoot `json:"roots"`
+	Secrets        []Secret        `json:"secrets"` // 不含敏感信息时只保留名称和描述
+	SamplingLogs   []SamplingLog   `json:"sampling_logs"`
+	ServerLogs     []MCPServerLog  `json:"server_logs"`
+	AuditLogs      []AuditLog      `json:"audit_logs"` // 旧版本备份中没有审计日志，恢复时保留现有的审计日志
+}
+
+// BackupSchedule 自动备份设置，全局只有一条记录
+type BackupSchedule struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Enabled         bool       `json:"enabled" gorm:"default:false"`
+	IntervalHours   int        `json:"interval_hours" gorm:"default:24"`
+	Keep            int        `json:"keep" gorm:"default:7"` // 保留的自动备份数量
+	IncludeSecrets  bool       `json:"include_secrets"`
+	IncludeHistory  bool       `json:"include_history"`
+	LastBackupAt    *time.Time `json:"last_backup_at"`
+	LastBackupError string     `json:"last_backup_error" gorm:"type:text"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// BackupInfo 备份文件信息
+type BackupInfo struct {
+	Name     string         `json:"name"`
+	Path     string         `json:"path"`
+	Size     int64          `json:"size"`
+	Manifest BackupManifest `json:"manifest"`
+}
+
+// BackupCreateRequest 创建备份请求
+type BackupCreateRequest struct {
+	IncludeSecrets bool   `json:"include_secrets"` // 为false时清除认证配置、API Key等敏感信息，为true时使用本机主密钥加密保存
+	ExcludeHistory bool   `json:"exclude_history"` // 为true时不备份采样日志、服务器日志和审计日志
+	Note           string `json:"note" binding:"max=200"`
+}
+
+// BackupRestoreRequest 恢复备份请求
+type BackupRestoreRequest struct {
+	Mode string `json:"mode" binding:"omitempty,oneof=merge replace"` // 为空时合并
+}
+
+// BackupRestoreResult 恢复结果
+type BackupRestoreResult struct {
+	Mode           string         `json:"mode"`
+	SafetyBackup   string         `json:"safety_backup"` // 恢复前自动创建的备份
+	Restored       map[string]int `json:"restored"`
+	SkippedHistory bool           `json:"skipped_history"` // 合并模式不恢复历史记录，避免重复
+}
+
+// BackupScheduleUpdateRequest 自动备份设置更新请求
+type BackupScheduleUpdateRequest struct {
+	Enabled        bool `json:"enabled"`
+	IntervalHours  int  `json:"interval_hours" binding:"min=1,max=720"`
+	Keep           int  `json:"keep" binding:"min=1,max=100"`
+	IncludeSecrets bool `json:"include_secrets"`
+	IncludeHistory bool `json:"include_history"`
+}
+
+// TableName 指定表名
+func (BackupSchedule) TableName() string {
+	return "backup_schedules"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ElicitationPendingRequest 等待用户填写的信息收集请求
+type ElicitationPendingRequest struct {
+	ID              string      `json:"id"`
+	ServerID        uint        `json:"server_id"`
+	ServerName      string      `json:"server_name"`
+	Message         string      `json:"message"`
+	RequestedSchema interface{} `json:"requested_schema"` // 服务器要求的JSON Schema
+	CreatedAt       time.Time   `json:"created_at"`
+	ExpiresAt       time.Time   `json:"expires_at"`
+}
+
+// ElicitationRespondRequest 用户对信息收集请求的响应
+type ElicitationRespondRequest struct {
+	Action  string                 `json:"action" binding:"required,oneof=accept decline cancel"`
+	Content map[string]interface{} `json:"content"`
+}
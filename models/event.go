@@ -0,0 +1,7 @@
+package models
+
+// EventStreamRequest 事件流订阅请求
+type EventStreamRequest struct {
+	// 订阅的主题，可重复传入或用逗号分隔，支持 tool_call.* 形式的前缀匹配，为空时订阅所有主题
+	Topics []string `form:"topics"`
+}
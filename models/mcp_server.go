@@ -3,26 +3,37 @@ package models
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
 	"gorm.io/gorm"
 )
 
 // MCPServer MCP服务器数据模型
 type MCPServer struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Name        string         `json:"name" gorm:"not null;size:100" binding:"required"`
-	Description string         `json:"description" gorm:"size:500"`
-	URL         string         `json:"url" gorm:"not null;size:255" binding:"required,url"`
-	AuthType    string         `json:"auth_type" gorm:"size:50;default:'none'"` // none, bearer, basic, api_key
-	AuthConfig  string         `json:"auth_config" gorm:"type:text"`            // JSON格式的认证配置
-	Status      string         `json:"status" gorm:"size:20;default:'inactive'"` // active, inactive, error
-	IsEnabled   bool           `json:"is_enabled" gorm:"default:true"`
-	Tags        string         `json:"tags" gorm:"size:255"`                    // 逗号分隔的标签
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
-	
+	ID             uint            `json:"id" gorm:"primaryKey"`
+	WorkspaceID    uint            `json:"workspace_id" gorm:"not null;index"` // 所属工作区
+	Name           string          `json:"name" gorm:"not null;size:100" binding:"required"`
+	Description    string          `json:"description" gorm:"size:500"`
+	URL            string          `json:"url" gorm:"not null;size:255" binding:"omitempty,url"`
+	Transport      string          `json:"transport" gorm:"size:20;default:'sse'"`   // sse, streamable_http, stdio
+	Command        string          `json:"command" gorm:"size:500"`                  // stdio传输方式的启动命令
+	Args           string          `json:"args" gorm:"type:text"`                    // JSON数组格式的命令参数
+	Env            EncryptedString `json:"env" gorm:"type:text"`                     // JSON对象格式的环境变量，加密存储
+	Headers        EncryptedString `json:"headers" gorm:"type:text"`                 // JSON对象格式的HTTP请求头，加密存储
+	AuthType       string          `json:"auth_type" gorm:"size:50;default:'none'"`  // none, bearer, basic, api_key
+	AuthConfig     EncryptedString `json:"auth_config" gorm:"type:text"`             // JSON格式的认证配置，加密存储
+	Status         string          `json:"status" gorm:"size:20;default:'inactive'"` // active, inactive, error
+	IsEnabled      bool            `json:"is_enabled" gorm:"default:true"`
+	SamplingPolicy string          `json:"sampling_policy" gorm:"size:20"` // allow, deny, ask，为空时使用全局默认策略
+	LogLevel       string          `json:"log_level" gorm:"size:20"`       // 通过 logging/setLevel 请求的日志级别，为空时不设置
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt  `json:"deleted_at" gorm:"index"`
+
 	// 关联的工具
 	Tools []MCPTool `json:"tools,omitempty" gorm:"foreignKey:ServerID"`
+	// 关联的标签
+	Tags TagList `json:"tags" gorm:"many2many:mcp_server_tags"`
 }
 
 // MCPTool MCP工具数据模型
@@ -34,33 +45,58 @@ type MCPTool struct {
 	Category    string         `json:"category" gorm:"size:50"`
 	Parameters  string         `json:"parameters" gorm:"type:text"` // JSON格式的参数定义
 	IsEnabled   bool           `json:"is_enabled" gorm:"default:true"`
+
+	// 工具注解（来自MCP服务器的 annotations）
+	ReadOnlyHint   bool `json:"read_only_hint" gorm:"default:false"`
+	IdempotentHint bool `json:"idempotent_hint" gorm:"default:false"`
+
+	// 结果缓存设置
+	CacheEnabled *bool `json:"cache_enabled"`              // 为空时根据注解决定是否缓存
+	CacheTTL     int   `json:"cache_ttl" gorm:"default:0"` // 缓存有效期（秒），0表示使用默认值
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 	
 	// 关联的服务器
 	Server MCPServer `json:"server,omitempty" gorm:"foreignKey:ServerID"`
+	// 关联的标签
+	Tags TagList `json:"tags" gorm:"many2many:mcp_tool_tags"`
 }
 
 // MCPServerCreateRequest 创建MCP服务器请求结构
 type MCPServerCreateRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=100"`
-	Description string `json:"description" binding:"max=500"`
-	URL         string `json:"url" binding:"required,url"`
-	AuthType    string `json:"auth_type" binding:"oneof=none bearer basic api_key"`
-	AuthConfig  string `json:"auth_config"`
-	Tags        string `json:"tags" binding:"max=255"`
+	Name        string            `json:"name" binding:"required,min=1,max=100"`
+	Description string            `json:"description" binding:"max=500"`
+	URL         string            `json:"url" binding:"max=255"` // 支持 ${env:NAME} 和 ${secret:name} 占位符
+	Transport   string            `json:"transport" binding:"omitempty,oneof=sse streamable_http stdio"`
+	Command     string            `json:"command" binding:"max=500"`
+	Args        []string          `json:"args"`
+	Env         map[string]string `json:"env"`
+	Headers     map[string]string `json:"headers"`
+	AuthType    string            `json:"auth_type" binding:"oneof=none bearer basic api_key"`
+	AuthConfig  string            `json:"auth_config"`
+	Tags        []string          `json:"tags" binding:"max=20,dive,max=50"`
 }
 
 // MCPServerUpdateRequest 更新MCP服务器请求结构
 type MCPServerUpdateRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=100"`
-	Description string `json:"description" binding:"max=500"`
-	URL         string `json:"url" binding:"required,url"`
-	AuthType    string `json:"auth_type" binding:"oneof=none bearer basic api_key"`
-	AuthConfig  string `json:"auth_config"`
-	IsEnabled   *bool  `json:"is_enabled"`
-	Tags        string `json:"tags" binding:"max=255"`
+	Name        string             `json:"name" binding:"required,min=1,max=100"`
+	Description string             `json:"description" binding:"max=500"`
+	URL         string             `json:"url" binding:"max=255"` // 支持 ${env:NAME} 和 ${secret:name} 占位符
+	Transport   string             `json:"transport" binding:"omitempty,oneof=sse streamable_http stdio"`
+	Command     *string            `json:"command" binding:"omitempty,max=500"` // 以下连接字段未提交时保持原值
+	Args        *[]string          `json:"args"`
+	Env         *map[string]string `json:"env"`
+	Headers     *map[string]string `json:"headers"`
+	AuthType    string             `json:"auth_type" binding:"oneof=none bearer basic api_key"`
+	AuthConfig  string             `json:"auth_config"`
+	IsEnabled   *bool              `json:"is_enabled"`
+	Tags        []string           `json:"tags" binding:"max=20,dive,max=50"`
+}
+
+// MCPServerStatusUpdateRequest 更新MCP服务器状态请求结构
+type MCPServerStatusUpdateRequest struct {
+	Status string `json:"status" binding:"required,oneof=active inactive error"`
 }
 
 // MCPServerListResponse 服务器列表响应结构
@@ -80,13 +116,15 @@ type MCPServerResponse struct {
 
 // MCPServerListRequest 服务器列表查询请求
 type MCPServerListRequest struct {
-	Page     int    `form:"page,default=1" binding:"min=1"`
-	Size     int    `form:"size,default=10" binding:"min=1,max=100"`
-	Search   string `form:"search"`
-	Status   string `form:"status" binding:"omitempty,oneof=active inactive error"`
-	Enabled  *bool  `form:"enabled"`
-	OrderBy  string `form:"order_by,default=created_at" binding:"oneof=created_at updated_at name"`
-	OrderDir string `form:"order_dir,default=desc" binding:"oneof=asc desc"`
+	Page     int      `json:"page" form:"page,default=1" binding:"min=1"`
+	Size     int      `json:"size" form:"size,default=10" binding:"min=1,max=100"`
+	Search   string   `json:"search" form:"search"`
+	Status   string   `json:"status" form:"status" binding:"omitempty,oneof=active inactive error"`
+	Enabled  *bool    `json:"enabled" form:"enabled"`
+	Tags     []string `json:"tags" form:"tags"`                                           // 按标签过滤，可重复传参或用逗号分隔
+	TagMode  string   `json:"tag_mode" form:"tag_mode,default=or" binding:"oneof=and or"` // and: 包含全部标签，or: 包含任意标签
+	OrderBy  string   `json:"order_by" form:"order_by,default=created_at" binding:"oneof=created_at updated_at name"`
+	OrderDir string   `json:"order_dir" form:"order_dir,default=desc" binding:"oneof=asc desc"`
 }
 
 // MCPToolDiscoveryRequest 工具发现请求
@@ -103,12 +141,14 @@ type MCPToolDiscoveryResponse struct {
 
 // MCPToolListRequest 工具列表查询请求
 type MCPToolListRequest struct {
-	ServerID uint   `form:"server_id"`
-	Category string `form:"category"`
-	Enabled  *bool  `form:"enabled"`
-	Search   string `form:"search"`
-	Page     int    `form:"page,default=1" binding:"min=1"`
-	Size     int    `form:"size,default=50" binding:"min=1,max=100"`
+	ServerID uint     `json:"server_id" form:"server_id"`
+	Category string   `json:"category" form:"category"`
+	Enabled  *bool    `json:"enabled" form:"enabled"`
+	Search   string   `json:"search" form:"search"`
+	Tags     []string `json:"tags" form:"tags"`                                           // 按标签过滤，可重复传参或用逗号分隔
+	TagMode  string   `json:"tag_mode" form:"tag_mode,default=or" binding:"oneof=and or"` // and: 包含全部标签，or: 包含任意标签
+	Page     int      `json:"page" form:"page,default=1" binding:"min=1"`
+	Size     int      `json:"size" form:"size,default=50" binding:"min=1,max=100"`
 }
 
 // MCPToolListResponse 工具列表响应
@@ -121,8 +161,26 @@ type MCPToolListResponse struct {
 
 // MCPToolUpdateRequest 工具更新请求
 type MCPToolUpdateRequest struct {
-	IsEnabled *bool  `json:"is_enabled"`
-	Category  string `json:"category" binding:"max=50"`
+	IsEnabled    *bool  `json:"is_enabled"`
+	Category     string `json:"category" binding:"max=50"`
+	CacheEnabled *bool  `json:"cache_enabled"`
+	CacheTTL     *int   `json:"cache_ttl" binding:"omitempty,min=0,max=86400"`
+}
+
+// MCPToolCallRequest 工具调用请求
+type MCPToolCallRequest struct {
+	Arguments   map[string]interface{} `json:"arguments"`
+	BypassCache bool                   `json:"bypass_cache"` // 为true时跳过缓存直接调用
+}
+
+// MCPToolCallResponse 工具调用响应
+type MCPToolCallResponse struct {
+	ToolID   uint                `json:"tool_id"`
+	ToolName string              `json:"tool_name"`
+	ServerID uint                `json:"server_id"`
+	Cached   bool                `json:"cached"`
+	CachedAt *time.Time          `json:"cached_at,omitempty"`
+	Result   *mcp.CallToolResult `json:"result"`
 }
 
 // MCPToolBatchUpdateRequest 工具批量更新请求
@@ -159,6 +217,15 @@ func (MCPTool) TableName() string {
 	return "mcp_tools"
 }
 
+// IsCacheable 判断工具结果是否可以缓存
+// 未显式设置时，只读且幂等的工具默认开启缓存
+func (m *MCPTool) IsCacheable() bool {
+	if m.CacheEnabled != nil {
+		return *m.CacheEnabled
+	}
+	return m.ReadOnlyHint && m.IdempotentHint
+}
+
 // GetParameters 解析工具参数
 func (m *MCPTool) GetParameters() ([]MCPToolParameter, error) {
 	if m.Parameters == "" {
@@ -190,14 +257,65 @@ func (m *MCPServer) BeforeCreate(tx *gorm.DB) error {
 	if m.Status == "" {
 		m.Status = "inactive"
 	}
+	if m.Transport == "" {
+		m.Transport = "sse"
+	}
 	return nil
 }
 
-// GetTagList 获取标签列表
-func (m *MCPServer) GetTagList() []string {
-	if m.Tags == "" {
-		return []string{}
+// AcceptsServerRequests 传输层是否支持接收服务器发起的请求（采样、根目录、信息收集），
+// SSE 传输层只能由客户端发起请求
+func (m *MCPServer) AcceptsServerRequests() bool {
+	return m.Transport == "stdio" || m.Transport == "streamable_http"
+}
+
+// GetArgs 解析命令参数
+func (m *MCPServer) GetArgs() []string {
+	var args []string
+	if m.Args != "" {
+		_ = json.Unmarshal([]byte(m.Args), &args)
 	}
-	// 这里可以实现标签分割逻辑
-	return []string{m.Tags}
-}
\ No newline at end of file
+	return args
+}
+
+// GetEnv 解析环境变量
+func (m *MCPServer) GetEnv() map[string]string {
+	env := map[string]string{}
+	if m.Env != "" {
+		_ = json.Unmarshal([]byte(m.Env), &env)
+	}
+	return env
+}
+
+// GetHeaders 解析HTTP请求头
+func (m *MCPServer) GetHeaders() map[string]string {
+	headers := map[string]string{}
+	if m.Headers != "" {
+		_ = json.Unmarshal([]byte(m.Headers), &headers)
+	}
+	return headers
+}
+
+// SetConnection 设置命令参数、环境变量和请求头，空值存储为空字符串
+func (m *MCPServer) SetConnection(args []string, env, headers map[string]string) {
+	m.Args = marshalOrEmpty(len(args) > 0, args)
+	m.Env = EncryptedString(marshalOrEmpty(len(env) > 0, env))
+	m.Headers = EncryptedString(marshalOrEmpty(len(headers) > 0, headers))
+}
+
+// marshalOrEmpty 序列化非空数据
+func marshalOrEmpty(notEmpty bool, v interface{}) string {
+	if !notEmpty {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// GetTagList 获取标签名称列表
+func (m *MCPServer) GetTagList() []string {
+	return m.Tags.Names()
+}
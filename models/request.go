@@ -0,0 +1,22 @@
+package models
+
+import "context"
+
+type requestContextKey string
+
+const requestIDKey requestContextKey = "request_id"
+
+// WithRequestID 在上下文中设置API请求ID，MCP流量记录据此关联到发起的请求
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFrom 获取上下文中的API请求ID，未设置时返回空字符串
+func RequestIDFrom(ctx context.Context) string {
+	if ctx != nil {
+		if id, ok := ctx.Value(requestIDKey).(string); ok {
+			return id
+		}
+	}
+	return ""
+}
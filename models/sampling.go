@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 采样策略
+const (
+	SamplingPolicyAllow = "allow" // 自动允许
+	SamplingPolicyDeny  = "deny"  // 总是拒绝
+	SamplingPolicyAsk   = "ask"   // 每次询问用户
+)
+
+// SamplingConfig 采样（sampling/createMessage）使用的LLM后端配置，全局只有一条记录
+type SamplingConfig struct {
+	ID            uint            `json:"id" gorm:"primaryKey"`
+	Enabled       bool            `json:"enabled" gorm:"default:false"`
+	BaseURL       string          `json:"base_url" gorm:"size:255"` // OpenAI兼容接口地址，如 https://api.openai.com/v1
+	APIKey        EncryptedString `json:"api_key" gorm:"type:text"` // 加密存储
+	Model         string          `json:"model" gorm:"size:100"`
+	MaxTokens     int             `json:"max_tokens" gorm:"default:1024"`              // 服务器未指定时的最大Token数
+	DefaultPolicy string          `json:"default_policy" gorm:"size:20;default:'ask'"` // allow, deny, ask
+	AskTimeout    int             `json:"ask_timeout" gorm:"default:60"`               // 等待用户确认的超时时间（秒）
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// SamplingLog 采样请求日志
+type SamplingLog struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	ServerID   uint           `json:"server_id" gorm:"index"`
+	Policy     string         `json:"policy" gorm:"size:20"`
+	Decision   string         `json:"decision" gorm:"size:20"` // approved, denied, timeout, error
+	Model      string         `json:"model" gorm:"size:100"`
+	Request    string         `json:"request" gorm:"type:text"`  // JSON格式的采样请求
+	Response   string         `json:"response" gorm:"type:text"` // JSON格式的采样结果
+	Error      string         `json:"error" gorm:"type:text"`
+	DurationMs int64          `json:"duration_ms"`
+	CreatedAt  time.Time      `json:"created_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// SamplingConfigUpdateRequest 采样配置更新请求
+type SamplingConfigUpdateRequest struct {
+	Enabled       bool   `json:"enabled"`
+	BaseURL       string `json:"base_url" binding:"omitempty,url"`
+	APIKey        string `json:"api_key"`
+	Model         string `json:"model" binding:"max=100"`
+	MaxTokens     int    `json:"max_tokens" binding:"min=0,max=200000"`
+	DefaultPolicy string `json:"default_policy" binding:"omitempty,oneof=allow deny ask"`
+	AskTimeout    int    `json:"ask_timeout" binding:"min=0,max=3600"`
+}
+
+// SamplingPolicyUpdateRequest 服务器采样策略更新请求，空字符串表示使用全局默认策略
+type SamplingPolicyUpdateRequest struct {
+	Policy string `json:"policy" binding:"omitempty,oneof=allow deny ask"`
+}
+
+// SamplingLogListRequest 采样日志查询请求
+type SamplingLogListRequest struct {
+	ServerID uint   `form:"server_id"`
+	Decision string `form:"decision" binding:"omitempty,oneof=approved denied timeout error"`
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	Size     int    `form:"size,default=20" binding:"min=1,max=100"`
+}
+
+// SamplingLogListResponse 采样日志列表响应
+type SamplingLogListResponse struct {
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Size  int           `json:"size"`
+	Logs  []SamplingLog `json:"logs"`
+}
+
+// SamplingPendingRequest 等待用户确认的采样请求
+type SamplingPendingRequest struct {
+	ID         string      `json:"id"`
+	ServerID   uint        `json:"server_id"`
+	ServerName string      `json:"server_name"`
+	Request    interface{} `json:"request"`
+	CreatedAt  time.Time   `json:"created_at"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+}
+
+// SamplingDecisionRequest 用户对采样请求的确认结果
+type SamplingDecisionRequest struct {
+	Approve bool `json:"approve"`
+}
+
+// TableName 指定表名
+func (SamplingConfig) TableName() string {
+	return "sampling_configs"
+}
+
+// TableName 指定表名
+func (SamplingLog) TableName() string {
+	return "sampling_logs"
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"desktop-ai-tools/security"
+)
+
+// SecretMask API响应和导出配置中敏感值的掩码，更新时提交该值表示保持不变
+const SecretMask = "******"
+
+// secretKeyPattern 判断环境变量、请求头或认证配置的键是否对应敏感信息
+var secretKeyPattern = regexp.MustCompile(`(?i)(token|secret|passw(or)?d|api[-_]?key|access[-_]?key|private[-_]?key|authorization|credential|cookie|auth)`)
+
+// placeholderOnlyPattern 匹配完全由 ${env:NAME} 或 ${secret:name} 占位符组成的值
+var placeholderOnlyPattern = regexp.MustCompile(`^(\$\{(env|secret):[A-Za-z0-9_.\-]+\})+$`)
+
+// IsSecretKey 判断键名是否对应敏感信息，API响应掩码、导出和备份脱敏使用同一规则
+func IsSecretKey(key string) bool {
+	return secretKeyPattern.MatchString(key)
+}
+
+// EncryptedString 加密存储的字符串，写入数据库时使用AES-GCM加密，读取时自动解密
+type EncryptedString string
+
+// Value 实现 driver.Valuer 接口
+func (s EncryptedString) Value() (driver.Value, error) {
+	return security.Encrypt(string(s))
+}
+
+// Scan 实现 sql.Scanner 接口
+func (s *EncryptedString) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		raw = ""
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("无法解析加密字段: %T", value)
+	}
+
+	plaintext, err := security.Decrypt(raw)
+	if err != nil {
+		return err
+	}
+	*s = EncryptedString(plaintext)
+	return nil
+}
+
+// Redact 将服务器的敏感配置替换为掩码，用于API响应
+func (s *MCPServer) Redact() {
+	s.AuthConfig = EncryptedString(maskJSONObject(string(s.AuthConfig), IsSecretKey))
+	s.Env = EncryptedString(maskJSONObject(string(s.Env), IsSecretKey))
+	s.Headers = EncryptedString(maskJSONObject(string(s.Headers), IsSecretKey))
+}
+
+// KeepMaskedSecrets 将更新内容中仍为掩码的敏感值还原为现有值，实现只写语义
+func KeepMaskedSecrets(updated, current map[string]string) map[string]string {
+	for k, v := range updated {
+		if v == SecretMask {
+			updated[k] = current[k]
+		}
+	}
+	return updated
+}
+
+// KeepMaskedAuthConfig 认证配置中仍为掩码的字段保持原值
+func KeepMaskedAuthConfig(updated, current string) string {
+	if updated == SecretMask {
+		return current
+	}
+
+	var next map[string]interface{}
+	if err := json.Unmarshal([]byte(updated), &next); err != nil {
+		return updated
+	}
+	var prev map[string]interface{}
+	_ = json.Unmarshal([]byte(current), &prev)
+
+	changed := false
+	for k, v := range next {
+		if v == SecretMask {
+			next[k] = prev[k]
+			changed = true
+		}
+	}
+	if !changed {
+		return updated
+	}
+	data, err := json.Marshal(next)
+	if err != nil {
+		return updated
+	}
+	return string(data)
+}
+
+// maskJSONObject 将JSON对象中符合条件的非空字符串值替换为掩码
+func maskJSONObject(value string, shouldMask func(key string) bool) string {
+	if value == "" {
+		return value
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &object); err != nil {
+		// 无法解析的内容整体视为敏感信息
+		return SecretMask
+	}
+	for k, v := range object {
+		if str, ok := v.(string); ok && str == "" {
+			continue
+		}
+		// 完全由占位符组成的值不是敏感信息，需要在界面上展示；混有字面值的仍需掩码
+		if str, ok := v.(string); ok && placeholderOnlyPattern.MatchString(str) {
+			continue
+		}
+		if shouldMask(k) {
+			object[k] = SecretMask
+		}
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return SecretMask
+	}
+	return string(data)
+}
+
+// Redact 将采样配置的API Key替换为掩码，用于API响应
+func (c *SamplingConfig) Redact() {
+	if c.APIKey != "" {
+		c.APIKey = SecretMask
+	}
+}
+
+// KeyRotationResult 主密钥轮换结果
+type KeyRotationResult struct {
+	KeyID       string `json:"key_id"`      // 新的密钥ID
+	Reencrypted int    `json:"reencrypted"` // 重新加密的记录数
+}
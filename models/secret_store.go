@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Secret 命名密钥，服务器配置中通过 ${secret:name} 引用，删除时直接物理删除
+type Secret struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Name        string          `json:"name" gorm:"uniqueIndex;not null;size:100"`
+	Description string          `json:"description" gorm:"size:500"`
+	Value       EncryptedString `json:"value" gorm:"type:text"` // 加密存储，API只返回掩码
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+
+	// 引用该密钥的服务器数量，仅用于列表展示
+	UsageCount int `json:"usage_count" gorm:"-"`
+}
+
+// SecretCreateRequest 创建密钥请求
+type SecretCreateRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Value       string `json:"value" binding:"required"`
+}
+
+// SecretUpdateRequest 更新密钥请求，Value 为掩码时保持不变
+type SecretUpdateRequest struct {
+	Description string `json:"description" binding:"max=500"`
+	Value       string `json:"value"`
+}
+
+// SecretReference 引用密钥的服务器
+type SecretReference struct {
+	ServerID   uint     `json:"server_id"`
+	ServerName string   `json:"server_name"`
+	Fields     []string `json:"fields"` // 引用所在的字段：url, auth_config, headers, env
+}
+
+// SecretUsage 密钥的引用情况
+type SecretUsage struct {
+	Name    string            `json:"name"`
+	Servers []SecretReference `json:"servers"`
+}
+
+// Redact 将密钥值替换为掩码，用于API响应
+func (s *Secret) Redact() {
+	if s.Value != "" {
+		s.Value = SecretMask
+	}
+}
+
+// TableName 指定表名
+func (Secret) TableName() string {
+	return "secrets"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "testing"
+
+// TestRedactPlaceholders 测试完全由占位符组成的值不掩码，混有字面值的敏感值仍然掩码
+func TestRedactPlaceholders(t *testing.T) {
+	server := MCPServer{AuthConfig: `{"token":"${secret:gh-token}"}`}
+	server.SetConnection(nil,
+		map[string]string{"GITHUB_TOKEN": "${env:GH}${secret:gh-token}", "API_KEY": "sk-live-abc"},
+		map[string]string{"Authorization": "Bearer sk-live-abc ${env:X}"})
+	server.Redact()
+
+	if server.AuthConfig != `{"token":"${secret:gh-token}"}` {
+		t.Fatalf("只包含占位符的认证配置不应掩码: %s", server.AuthConfig)
+	}
+	env := server.GetEnv()
+	if env["GITHUB_TOKEN"] != "${env:GH}${secret:gh-token}" || env["API_KEY"] != SecretMask {
+		t.Fatalf("环境变量掩码不正确: %v", env)
+	}
+	if headers := server.GetHeaders(); headers["Authorization"] != SecretMask {
+		t.Fatalf("混有字面值的请求头应掩码: %v", headers)
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+// 导出时的敏感信息处理方式
+const (
+	ExportSecretsKeep        = "keep"        // 原样导出
+	ExportSecretsRedact      = "redact"      // 替换为固定的掩码
+	ExportSecretsPlaceholder = "placeholder" // 替换为 ${env:NAME} 占位符
+)
+
+// MCPServerExportRequest 导出服务器为客户端配置的请求
+type MCPServerExportRequest struct {
+	Format      string   `json:"format" binding:"omitempty,oneof=claude_desktop cursor vscode generic"` // 为空时使用通用的 mcpServers 格式
+	IDs         []uint   `json:"ids"`                                                                   // 按ID选择服务器
+	Tags        []string `json:"tags"`                                                                  // 按标签选择服务器，与IDs同时为空时导出全部
+	Secrets     string   `json:"secrets" binding:"omitempty,oneof=keep redact placeholder"`             // 为空时原样导出
+	OnlyEnabled bool     `json:"only_enabled"`                                                          // 只导出已启用的服务器
+}
+
+// MCPServerExportResponse 服务器导出响应
+type MCPServerExportResponse struct {
+	Format       string                 `json:"format"`
+	Count        int                    `json:"count"`
+	Config       map[string]interface{} `json:"config"`
+	Content      string                 `json:"content"`                // 格式化后的JSON，可直接粘贴到客户端配置文件
+	Placeholders []string               `json:"placeholders,omitempty"` // 使用占位符时需要在客户端设置的环境变量
+	Warnings     []string               `json:"warnings,omitempty"`
+}
@@ -0,0 +1,68 @@
+package models
+
+// 客户端配置文件格式
+const (
+	ClientFormatClaudeDesktop = "claude_desktop"
+	ClientFormatCursor        = "cursor"
+	ClientFormatVSCode        = "vscode"
+	ClientFormatGeneric       = "generic" // 通用的 mcpServers 格式
+)
+
+// 导入时的名称冲突处理方式
+const (
+	ImportConflictSkip      = "skip"
+	ImportConflictRename    = "rename"
+	ImportConflictOverwrite = "overwrite"
+)
+
+// 导入预览中每个服务器的处理动作
+const (
+	ImportActionCreate    = "create"
+	ImportActionSkip      = "skip"
+	ImportActionRename    = "rename"
+	ImportActionOverwrite = "overwrite"
+	ImportActionInvalid   = "invalid"
+)
+
+// MCPServerImportRequest 从客户端配置文件导入服务器的请求
+type MCPServerImportRequest struct {
+	Format    string            `json:"format" binding:"omitempty,oneof=auto claude_desktop cursor vscode generic"`
+	Content   string            `json:"content"`                                                  // 配置文件内容
+	Path      string            `json:"path"`                                                     // 配置文件路径，Content为空时读取；都为空时读取该格式的默认路径
+	Conflict  string            `json:"conflict" binding:"omitempty,oneof=skip rename overwrite"` // 默认冲突处理方式，为空时跳过
+	Overrides map[string]string `json:"overrides"`                                                // 按服务器名称单独指定冲突处理方式
+	Names     []string          `json:"names"`                                                    // 只导入指定名称的服务器，为空时导入全部
+	Tags      []string          `json:"tags" binding:"max=20,dive,max=50"`                        // 为导入的服务器设置的标签
+}
+
+// MCPServerImportItem 单个服务器的导入结果
+type MCPServerImportItem struct {
+	Name       string `json:"name"`        // 配置文件中的名称
+	TargetName string `json:"target_name"` // 导入后使用的名称
+	Action     string `json:"action"`      // create, skip, rename, overwrite, invalid
+	Transport  string `json:"transport"`
+	URL        string `json:"url,omitempty"`
+	Command    string `json:"command,omitempty"`
+	ExistingID uint   `json:"existing_id,omitempty"` // 同名的已有服务器ID
+	ServerID   uint   `json:"server_id,omitempty"`   // 导入后的服务器ID
+	Error      string `json:"error,omitempty"`
+}
+
+// MCPServerImportResponse 服务器导入响应
+type MCPServerImportResponse struct {
+	Format      string                `json:"format"`
+	Source      string                `json:"source"` // 配置来源，文件路径或 request
+	DryRun      bool                  `json:"dry_run"`
+	Items       []MCPServerImportItem `json:"items"`
+	Created     int                   `json:"created"`
+	Overwritten int                   `json:"overwritten"`
+	Skipped     int                   `json:"skipped"`
+	Failed      int                   `json:"failed"`
+}
+
+// ClientConfigSource 本机检测到的客户端配置文件
+type ClientConfigSource struct {
+	Format string `json:"format"`
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
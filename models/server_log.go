@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// ServerLogLevels MCP日志级别，按严重程度从低到高排列（RFC-5424）
+var ServerLogLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// MCPServerLog MCP服务器通过 notifications/message 发送的日志
+type MCPServerLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ServerID  uint      `json:"server_id" gorm:"index"`
+	Level     string    `json:"level" gorm:"size:20"`
+	LevelRank int       `json:"-" gorm:"index"` // 日志级别序号，用于按最低级别过滤
+	Logger    string    `json:"logger" gorm:"size:100"`
+	Data      string    `json:"data" gorm:"type:text"` // JSON格式的日志内容
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// MCPServerLogListRequest 服务器日志查询请求
+type MCPServerLogListRequest struct {
+	Level  string    `form:"level" binding:"omitempty,oneof=debug info notice warning error critical alert emergency"` // 最低日志级别
+	Logger string    `form:"logger"`
+	Search string    `form:"search"`
+	Since  time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until  time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+	Follow bool      `form:"follow"` // 为true时通过SSE持续推送新日志
+	Page   int       `form:"page,default=1" binding:"min=1"`
+	Size   int       `form:"size,default=100" binding:"min=1,max=1000"`
+}
+
+// MCPServerLogListResponse 服务器日志列表响应
+type MCPServerLogListResponse struct {
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Size  int            `json:"size"`
+	Logs  []MCPServerLog `json:"logs"`
+}
+
+// MCPServerLogLevelRequest 服务器日志级别设置请求
+type MCPServerLogLevelRequest struct {
+	Level string `json:"level" binding:"omitempty,oneof=debug info notice warning error critical alert emergency"`
+}
+
+// TableName 指定表名
+func (MCPServerLog) TableName() string {
+	return "mcp_server_logs"
+}
+
+// ServerLogLevelRank 获取日志级别序号，未知级别返回0
+func ServerLogLevelRank(level string) int {
+	for i, l := range ServerLogLevels {
+		if l == level {
+			return i
+		}
+	}
+	return 0
+}
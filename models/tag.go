@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tag 标签，服务器和工具通过关联表共享同一组标签
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;not null;size:50"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// 使用该标签的服务器和工具数量，仅用于列表展示
+	ServerCount int64 `json:"server_count" gorm:"-"`
+	ToolCount   int64 `json:"tool_count" gorm:"-"`
+}
+
+// TableName 指定表名
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TagList 标签列表
+type TagList []Tag
+
+// Names 获取标签名称列表
+func (l TagList) Names() []string {
+	names := make([]string, 0, len(l))
+	for _, tag := range l {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// UnmarshalJSON 兼容旧版本备份中逗号分隔的标签字符串和标签名称数组
+func (l *TagList) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*l = tagsFromNames(strings.Split(text, ","))
+		return nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("无效的标签列表: %v", err)
+	}
+	tags := make(TagList, 0, len(raw))
+	for _, item := range raw {
+		var name string
+		if err := json.Unmarshal(item, &name); err == nil {
+			tags = append(tags, tagsFromNames([]string{name})...)
+			continue
+		}
+		var tag Tag
+		if err := json.Unmarshal(item, &tag); err != nil {
+			return fmt.Errorf("无效的标签: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	*l = tags
+	return nil
+}
+
+// tagsFromNames 根据名称创建标签列表，忽略空名称
+func tagsFromNames(names []string) TagList {
+	tags := make(TagList, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			tags = append(tags, Tag{Name: name})
+		}
+	}
+	return tags
+}
+
+// TagListRequest 标签列表查询请求
+type TagListRequest struct {
+	Search string `form:"search"`
+}
+
+// TagRenameRequest 重命名标签请求
+type TagRenameRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// TagMergeRequest 合并标签请求，源标签的服务器和工具全部转到目标标签后删除源标签
+type TagMergeRequest struct {
+	SourceIDs []uint `json:"source_ids" binding:"required,min=1"`
+	Target    string `json:"target" binding:"required,max=50"` // 目标标签名称，不存在时自动创建
+}
+
+// TagAssignRequest 设置标签请求
+type TagAssignRequest struct {
+	Tags []string `json:"tags"`
+}
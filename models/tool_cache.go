@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ToolCacheEntry 工具结果缓存条目信息
+type ToolCacheEntry struct {
+	ToolName  string    `json:"tool_name"`
+	Arguments string    `json:"arguments"` // 规范化后的JSON参数
+	SizeBytes int       `json:"size_bytes"`
+	Hits      int64     `json:"hits"`
+	CachedAt  time.Time `json:"cached_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ToolCacheStats 服务器的工具结果缓存统计
+type ToolCacheStats struct {
+	ServerID   uint             `json:"server_id"`
+	EntryCount int              `json:"entry_count"`
+	SizeBytes  int              `json:"size_bytes"`
+	Hits       int64            `json:"hits"`
+	Misses     int64            `json:"misses"`
+	Entries    []ToolCacheEntry `json:"entries"`
+}
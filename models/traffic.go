@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// JSON-RPC 消息的传输方向
+const (
+	TrafficOutgoing = "outgoing" // 客户端发往服务器
+	TrafficIncoming = "incoming" // 服务器发往客户端
+)
+
+// JSON-RPC 消息类型
+const (
+	TrafficRequest      = "request"
+	TrafficResponse     = "response"
+	TrafficNotification = "notification"
+)
+
+// MCPTrafficEntry 一条抓取到的JSON-RPC消息
+type MCPTrafficEntry struct {
+	ID        uint64          `json:"id"` // 全局递增的序号
+	ServerID  uint            `json:"server_id"`
+	Session   uint64          `json:"session"` // 连接序号，同一连接中的消息序号相同
+	Direction string          `json:"direction"`
+	Kind      string          `json:"kind"`
+	Method    string          `json:"method,omitempty"`     // 响应记录对应请求的方法名
+	RPCID     string          `json:"rpc_id,omitempty"`     // JSON-RPC 请求ID，用于关联请求和响应
+	RequestID string          `json:"request_id,omitempty"` // 发起该消息的API请求ID
+	Time      time.Time       `json:"time"`
+	Duration  float64         `json:"duration_ms,omitempty"` // 响应记录中请求的耗时（毫秒）
+	Error     string          `json:"error,omitempty"`       // JSON-RPC 错误或传输层错误
+	Truncated bool            `json:"truncated,omitempty"`   // 消息过大时只保留开头部分，此时 message 为字符串
+	Message   json.RawMessage `json:"message,omitempty"`     // 完整的JSON-RPC消息，传输层出错时为空
+}
+
+// MCPTrafficListRequest 流量记录查询请求
+type MCPTrafficListRequest struct {
+	Direction string `json:"direction" form:"direction" binding:"omitempty,oneof=outgoing incoming"`
+	Kind      string `json:"kind" form:"kind" binding:"omitempty,oneof=request response notification"`
+	Method    string `json:"method" form:"method"`         // 方法名前缀，例如 tools/
+	RequestID string `json:"request_id" form:"request_id"` // 只返回该API请求产生的消息
+	Search    string `json:"search" form:"search"`         // 在消息内容中搜索的文本
+	AfterID   uint64 `json:"after_id" form:"after_id"`     // 只返回序号大于该值的记录，用于增量获取
+	Follow    bool   `json:"-" form:"follow"`              // 为true时通过SSE持续推送新记录
+	Limit     int    `json:"limit" form:"limit,default=200" binding:"min=1,max=5000"`
+}
+
+// Match 判断流量记录是否满足查询条件
+func (r *MCPTrafficListRequest) Match(entry *MCPTrafficEntry) bool {
+	if r.Direction != "" && r.Direction != entry.Direction {
+		return false
+	}
+	if r.Kind != "" && r.Kind != entry.Kind {
+		return false
+	}
+	if r.Method != "" && !strings.HasPrefix(entry.Method, r.Method) {
+		return false
+	}
+	if r.RequestID != "" && r.RequestID != entry.RequestID {
+		return false
+	}
+	if entry.ID <= r.AfterID {
+		return false
+	}
+	if r.Search != "" && !strings.Contains(string(entry.Message), r.Search) && !strings.Contains(entry.Error, r.Search) {
+		return false
+	}
+	return true
+}
+
+// MCPTrafficStatus 服务器的流量抓取状态
+type MCPTrafficStatus struct {
+	ServerID uint   `json:"server_id"`
+	Enabled  bool   `json:"enabled"`
+	Capacity int    `json:"capacity"` // 缓冲区最多保留的记录数，超出后覆盖最早的记录
+	Count    int    `json:"count"`    // 缓冲区中的记录数
+	Dropped  uint64 `json:"dropped"`  // 因缓冲区已满被覆盖的记录数
+}
+
+// MCPTrafficListResponse 流量记录列表响应，记录按时间从旧到新排列
+type MCPTrafficListResponse struct {
+	MCPTrafficStatus
+	Total   int               `json:"total"` // 满足条件的记录数，可能多于返回的条数
+	Entries []MCPTrafficEntry `json:"entries"`
+}
+
+// MCPTrafficCaptureRequest 开启或关闭流量抓取请求
+type MCPTrafficCaptureRequest struct {
+	Enabled bool `json:"enabled"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Workspace 工作区，每个工作区拥有独立的服务器、工具设置和工作区级配置
+type Workspace struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Name           string    `json:"name" gorm:"uniqueIndex;not null;size:100"`
+	Description    string    `json:"description" gorm:"size:500"`
+	IsActive       bool      `json:"is_active"`                      // 同一时间只有一个工作区处于激活状态
+	SamplingPolicy string    `json:"sampling_policy" gorm:"size:20"` // 工作区默认采样策略，为空时使用全局默认策略
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	// 工作区中的服务器数量，仅用于列表展示
+	ServerCount int64 `json:"server_count" gorm:"-"`
+}
+
+// TableName 指定表名
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+// WorkspaceCreateRequest 创建工作区请求
+type WorkspaceCreateRequest struct {
+	Name           string `json:"name" binding:"required,max=100"`
+	Description    string `json:"description" binding:"max=500"`
+	SamplingPolicy string `json:"sampling_policy" binding:"omitempty,oneof=allow deny ask"`
+}
+
+// WorkspaceUpdateRequest 更新工作区请求
+type WorkspaceUpdateRequest struct {
+	Name           string `json:"name" binding:"required,max=100"`
+	Description    string `json:"description" binding:"max=500"`
+	SamplingPolicy string `json:"sampling_policy" binding:"omitempty,oneof=allow deny ask"`
+}
+
+// WorkspaceCloneRequest 复制工作区请求
+type WorkspaceCloneRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Activate    bool   `json:"activate"` // 复制完成后切换到新工作区
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WorkspaceRoot 工作区根目录，通过 roots/list 提供给MCP服务器
+type WorkspaceRoot struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint           `json:"workspace_id" gorm:"not null;index"` // 所属工作区
+	ServerID    *uint          `json:"server_id" gorm:"index"`             // 为空表示对工作区内所有服务器生效
+	Name        string         `json:"name" gorm:"size:100"`
+	Path        string         `json:"path" gorm:"not null;size:1024"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// WorkspaceRootCreateRequest 创建根目录请求
+type WorkspaceRootCreateRequest struct {
+	ServerID *uint  `json:"server_id"`
+	Name     string `json:"name" binding:"max=100"`
+	Path     string `json:"path" binding:"required,max=1024"`
+}
+
+// WorkspaceRootUpdateRequest 更新根目录请求
+type WorkspaceRootUpdateRequest struct {
+	Name string `json:"name" binding:"max=100"`
+	Path string `json:"path" binding:"required,max=1024"`
+}
+
+// WorkspaceRootListRequest 根目录查询请求
+type WorkspaceRootListRequest struct {
+	ServerID *uint `form:"server_id"`
+}
+
+// WorkspaceRootListResponse 根目录列表响应
+type WorkspaceRootListResponse struct {
+	Roots []WorkspaceRoot `json:"roots"`
+	// 当前工作区中无法接收根目录的服务器：SSE 传输层不支持服务器发起的 roots/list 请求
+	UnsupportedServers []WorkspaceRootServer `json:"unsupported_servers"`
+}
+
+// WorkspaceRootServer 根目录列表响应中的服务器信息
+type WorkspaceRootServer struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Transport string `json:"transport"`
+}
+
+// TableName 指定表名
+func (WorkspaceRoot) TableName() string {
+	return "workspace_roots"
+}
+
+// URI 将目录路径转换为 file:// URI
+func (r *WorkspaceRoot) URI() string {
+	path := filepath.ToSlash(r.Path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows 路径（如 C:/work）需要以 / 开头
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
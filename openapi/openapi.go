@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version 生成的文档遵循的 OpenAPI 版本
+const Version = "3.0.3"
+
+// Document OpenAPI 文档
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Servers    []Server                        `json:"servers,omitempty"`
+	Paths      map[string]map[string]*Endpoint `json:"paths"`
+	Components Components                      `json:"components"`
+	Security   []map[string][]string           `json:"security,omitempty"`
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server 接口服务地址
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Components 文档中可复用的定义
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// Endpoint 单个接口（路径 + 方法）的描述
+type Endpoint struct {
+	Summary     string                 `json:"summary,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	OperationID string                 `json:"operationId,omitempty"`
+	Parameters  []*Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+	Security    *[]map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter 路径或查询参数
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType 指定内容类型的结构
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Operation 路由的文档信息，由注册路由的一方维护
+type Operation struct {
+	Method   string
+	Path     string // gin 路由路径，例如 /api/mcp-servers/:id
+	Summary  string
+	Tag      string
+	Query    interface{}       // 查询参数结构体，字段使用 form 标签
+	Body     interface{}       // JSON 请求体结构体
+	FormFile string            // multipart 上传的文件字段名
+	Response interface{}       // 统一响应结构中 data 字段的类型，为空时不返回数据
+	Status   int               // 成功时的状态码，默认 200
+	Produces string            // 非 JSON 响应的内容类型，例如文件下载、SSE
+	Params   map[string]string // 路径参数类型，未指定时名称为 id 或以 ID 结尾的参数为整数，其余为字符串
+	Public   bool              // 无需API令牌即可访问
+}
+
+var pathParamPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// Key 返回路由的唯一标识，例如 "GET /api/health"
+func (op Operation) Key() string {
+	return op.Method + " " + op.Path
+}
+
+// Build 根据路由文档生成 OpenAPI 文档
+// envelope 为统一响应结构，各接口的 data 字段类型在此基础上替换
+func Build(info Info, envelope interface{}, operations []Operation) *Document {
+	g := newGenerator()
+	envelopeRef := g.schemaFor(reflect.TypeOf(envelope))
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Servers: []Server{{URL: "/"}},
+		Paths:   map[string]map[string]*Endpoint{},
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer"},
+			},
+		},
+		Security: []map[string][]string{{"bearerAuth": {}}},
+	}
+
+	for _, op := range operations {
+		path := pathParamPattern.ReplaceAllString(op.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*Endpoint{}
+		}
+		doc.Paths[path][strings.ToLower(op.Method)] = g.endpoint(op, envelopeRef)
+	}
+	return doc
+}
+
+// endpoint 生成单个接口的描述
+func (g *generator) endpoint(op Operation, envelope *Schema) *Endpoint {
+	ep := &Endpoint{
+		Summary:     op.Summary,
+		OperationID: operationID(op),
+		Responses:   map[string]*Response{},
+	}
+	if op.Tag != "" {
+		ep.Tags = []string{op.Tag}
+	}
+	if op.Public {
+		ep.Security = &[]map[string][]string{}
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(op.Path, -1) {
+		ep.Parameters = append(ep.Parameters, &Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   pathParamSchema(match[1], op.Params[match[1]]),
+		})
+	}
+	if op.Query != nil {
+		ep.Parameters = append(ep.Parameters, g.queryParameters(reflect.TypeOf(op.Query))...)
+	}
+
+	switch {
+	case op.Body != nil:
+		ep.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: g.schemaFor(reflect.TypeOf(op.Body))}},
+		}
+	case op.FormFile != "":
+		ep.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{"multipart/form-data": {Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{op.FormFile: {Type: "string", Format: "binary"}},
+				Required:   []string{op.FormFile},
+			}}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case op.Produces != "":
+		success.Content = map[string]*MediaType{op.Produces: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	default:
+		schema := envelope
+		if op.Response != nil {
+			schema = &Schema{AllOf: []*Schema{envelope, {
+				Type:       "object",
+				Properties: map[string]*Schema{"data": g.schemaFor(reflect.TypeOf(op.Response))},
+			}}}
+		}
+		success.Content = map[string]*MediaType{"application/json": {Schema: schema}}
+	}
+	ep.Responses[strconv.Itoa(status)] = success
+	ep.Responses["default"] = &Response{
+		Description: "错误响应，code 为稳定的错误码",
+		Content:     map[string]*MediaType{"application/json": {Schema: envelope}},
+	}
+	return ep
+}
+
+// pathParamSchema 返回路径参数的类型
+func pathParamSchema(name, typ string) *Schema {
+	if typ == "" {
+		typ = "string"
+		if name == "id" || strings.HasSuffix(name, "ID") {
+			typ = "integer"
+		}
+	}
+	return &Schema{Type: typ}
+}
+
+// operationID 根据方法和路径生成 operationId，例如 GET /api/mcp-servers/:id -> get_mcp_servers_id
+func operationID(op Operation) string {
+	path := strings.TrimPrefix(op.Path, "/api")
+	parts := []string{strings.ToLower(op.Method)}
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == ':' }) {
+		parts = append(parts, strings.ToLower(segment))
+	}
+	return strings.Join(parts, "_")
+}
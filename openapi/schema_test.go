@@ -0,0 +1,50 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBindingConstraints 测试 binding 标签转换为 Schema 约束
+func TestBindingConstraints(t *testing.T) {
+	type request struct {
+		Name   string   `json:"name" binding:"required,min=1,max=100"`
+		Mode   string   `json:"mode" binding:"omitempty,oneof=sse stdio"`
+		Tags   []string `json:"tags" binding:"max=20,dive,max=50"`
+		Size   int      `json:"size" binding:"min=1,max=100"`
+		Hidden string   `json:"-"`
+	}
+
+	g := newGenerator()
+	ref := g.schemaFor(reflect.TypeOf(request{}))
+	schema := g.schemas["request"]
+	if ref.Ref != "#/components/schemas/request" || schema == nil {
+		t.Fatalf("具名结构体应放入 components: %+v", ref)
+	}
+
+	if !reflect.DeepEqual(schema.Required, []string{"name"}) {
+		t.Fatalf("必填字段不正确: %v", schema.Required)
+	}
+	if name := schema.Properties["name"]; *name.MinLength != 1 || *name.MaxLength != 100 {
+		t.Fatalf("字符串长度约束不正确: %+v", name)
+	}
+	if mode := schema.Properties["mode"]; !reflect.DeepEqual(mode.Enum, []interface{}{"sse", "stdio"}) {
+		t.Fatalf("枚举约束不正确: %v", mode.Enum)
+	}
+	if tags := schema.Properties["tags"]; *tags.MaxItems != 20 || *tags.Items.MaxLength != 50 {
+		t.Fatalf("数组约束不正确: %+v", tags)
+	}
+	if size := schema.Properties["size"]; *size.Minimum != 1 || *size.Maximum != 100 {
+		t.Fatalf("数值范围约束不正确: %+v", size)
+	}
+	if _, ok := schema.Properties["Hidden"]; ok {
+		t.Fatal("json 标签为 - 的字段不应出现在文档中")
+	}
+
+	params := g.queryParameters(reflect.TypeOf(struct {
+		Page int `form:"page,default=1" binding:"min=1"`
+	}{}))
+	if len(params) != 1 || params[0].Name != "page" || params[0].Schema.Default != int64(1) {
+		t.Fatalf("查询参数不正确: %+v", params[0])
+	}
+}
@@ -0,0 +1,312 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// encryptedPrefix 密文前缀，完整格式为 enc:v1:<密钥ID>:<base64(nonce+密文)>
+	encryptedPrefix = "enc:v1:"
+	// keyFileName 主密钥文件名，与数据库分开存放
+	keyFileName = "master.key"
+	// MasterKeyEnv 通过环境变量提供主密钥（base64编码的32字节），设置后不读取密钥文件
+	MasterKeyEnv = "DESKTOP_AI_TOOLS_MASTER_KEY"
+)
+
+// keyFile 主密钥文件结构，轮换后旧密钥会保留到数据重新加密完成
+type keyFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"` // 密钥ID -> base64编码的密钥
+}
+
+// Keyring 管理用于加密敏感字段的AES-256主密钥
+type Keyring struct {
+	mu      sync.RWMutex
+	path    string // 为空表示密钥来自环境变量，不支持轮换
+	active  string
+	keys    map[string][]byte
+	ciphers map[string]cipher.AEAD
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultKeyring *Keyring
+)
+
+// InitKeyring 从 dir/master.key 加载主密钥，不存在时自动生成，并设置为默认密钥环
+func InitKeyring(dir string) (*Keyring, error) {
+	keyring, err := LoadKeyring(dir)
+	if err != nil {
+		return nil, err
+	}
+	defaultMu.Lock()
+	defaultKeyring = keyring
+	defaultMu.Unlock()
+	return keyring, nil
+}
+
+// Default 获取默认密钥环，未初始化时返回nil
+func Default() *Keyring {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultKeyring
+}
+
+// LoadKeyring 加载主密钥，优先使用环境变量
+func LoadKeyring(dir string) (*Keyring, error) {
+	if encoded := os.Getenv(MasterKeyEnv); encoded != "" {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("环境变量 %s 中的主密钥无效: %v", MasterKeyEnv, err)
+		}
+		k := &Keyring{keys: map[string][]byte{"env": key}, active: "env"}
+		if err := k.buildCiphers(); err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+
+	k := &Keyring{path: filepath.Join(dir, keyFileName)}
+	data, err := os.ReadFile(k.path)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("创建密钥目录失败: %v", err)
+		}
+		id, key, err := generateKey()
+		if err != nil {
+			return nil, err
+		}
+		k.active = id
+		k.keys = map[string][]byte{id: key}
+		if err := k.save(); err != nil {
+			return nil, err
+		}
+		return k, k.buildCiphers()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取主密钥文件失败: %v", err)
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("解析主密钥文件失败: %v", err)
+	}
+	k.active = file.Active
+	k.keys = make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("主密钥 %s 无效: %v", id, err)
+		}
+		k.keys[id] = key
+	}
+	if _, ok := k.keys[k.active]; !ok {
+		return nil, fmt.Errorf("主密钥文件中缺少当前密钥 %s", k.active)
+	}
+	return k, k.buildCiphers()
+}
+
+// ActiveKeyID 获取当前用于加密的密钥ID
+func (k *Keyring) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Encrypt 使用当前密钥加密，空字符串不加密
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	k.mu.RLock()
+	id := k.active
+	aead := k.ciphers[id]
+	k.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %v", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(id))
+	return encryptedPrefix + id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密密文，未加密的旧数据原样返回
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	id, payload, ok := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if !ok {
+		return "", fmt.Errorf("密文格式无效")
+	}
+
+	k.mu.RLock()
+	aead := k.ciphers[id]
+	k.mu.RUnlock()
+	if aead == nil {
+		return "", fmt.Errorf("找不到密钥 %s，无法解密", id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("密文格式无效")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("解密失败: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rotate 生成新的主密钥作为当前密钥，旧密钥保留用于解密，返回新密钥ID
+func (k *Keyring) Rotate() (string, error) {
+	if k.path == "" {
+		return "", fmt.Errorf("主密钥来自环境变量 %s，无法轮换", MasterKeyEnv)
+	}
+
+	id, key, err := generateKey()
+	if err != nil {
+		return "", err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	k.keys[id] = key
+	k.ciphers[id] = aead
+	k.active = id
+	if err := k.save(); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Retire 删除当前密钥以外的旧密钥，需在所有数据重新加密后调用
+func (k *Keyring) Retire() error {
+	if k.path == "" {
+		return nil
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for id := range k.keys {
+		if id != k.active {
+			delete(k.keys, id)
+			delete(k.ciphers, id)
+		}
+	}
+	return k.save()
+}
+
+// save 写入主密钥文件，调用方需持有写锁或处于初始化阶段
+func (k *Keyring) save() error {
+	file := keyFile{Active: k.active, Keys: make(map[string]string, len(k.keys))}
+	for id, key := range k.keys {
+		file.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化主密钥失败: %v", err)
+	}
+
+	// 先写临时文件再替换，避免写入中断导致密钥丢失
+	tmp := k.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("保存主密钥失败: %v", err)
+	}
+	if err := os.Rename(tmp, k.path); err != nil {
+		return fmt.Errorf("保存主密钥失败: %v", err)
+	}
+	return nil
+}
+
+// buildCiphers 为所有密钥创建AES-GCM实例
+func (k *Keyring) buildCiphers() error {
+	k.ciphers = make(map[string]cipher.AEAD, len(k.keys))
+	for id, key := range k.keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return err
+		}
+		k.ciphers[id] = aead
+	}
+	return nil
+}
+
+// Encrypt 使用默认密钥环加密
+func Encrypt(plaintext string) (string, error) {
+	keyring := Default()
+	if keyring == nil {
+		return "", fmt.Errorf("主密钥未初始化")
+	}
+	return keyring.Encrypt(plaintext)
+}
+
+// Decrypt 使用默认密钥环解密
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	keyring := Default()
+	if keyring == nil {
+		return "", fmt.Errorf("主密钥未初始化")
+	}
+	return keyring.Decrypt(value)
+}
+
+// IsEncrypted 判断值是否为密文
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// newAEAD 创建AES-GCM实例
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建加密器失败: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("创建加密器失败: %v", err)
+	}
+	return aead, nil
+}
+
+// generateKey 生成新的256位密钥，ID为生成时间
+func generateKey() (string, []byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", nil, fmt.Errorf("生成主密钥失败: %v", err)
+	}
+	return time.Now().UTC().Format("20060102150405.000000000"), key, nil
+}
+
+// decodeKey 解码base64格式的256位密钥
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("密钥长度必须为32字节")
+	}
+	return key, nil
+}
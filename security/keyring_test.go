@@ -0,0 +1,46 @@
+package security
+
+import (
+	"testing"
+)
+
+// TestKeyringRotate 测试加密、解密与密钥轮换
+func TestKeyringRotate(t *testing.T) {
+	dir := t.TempDir()
+	keyring, err := LoadKeyring(dir)
+	if err != nil {
+		t.Fatalf("加载主密钥失败: %v", err)
+	}
+
+	encrypted, err := keyring.Encrypt(`{"token":"abc"}`)
+	if err != nil {
+		t.Fatalf("加密失败: %v", err)
+	}
+	if !IsEncrypted(encrypted) {
+		t.Fatalf("密文格式错误: %s", encrypted)
+	}
+
+	// 明文数据原样返回
+	if plain, err := keyring.Decrypt("plain"); err != nil || plain != "plain" {
+		t.Fatalf("明文解密结果错误: %s, %v", plain, err)
+	}
+
+	// 轮换后旧密文仍可解密，直到旧密钥被删除
+	if _, err := keyring.Rotate(); err != nil {
+		t.Fatalf("轮换密钥失败: %v", err)
+	}
+	reloaded, err := LoadKeyring(dir)
+	if err != nil {
+		t.Fatalf("重新加载主密钥失败: %v", err)
+	}
+	if plain, err := reloaded.Decrypt(encrypted); err != nil || plain != `{"token":"abc"}` {
+		t.Fatalf("轮换后解密失败: %s, %v", plain, err)
+	}
+
+	if err := keyring.Retire(); err != nil {
+		t.Fatalf("删除旧密钥失败: %v", err)
+	}
+	if _, err := keyring.Decrypt(encrypted); err == nil {
+		t.Fatal("旧密钥删除后不应能解密旧密文")
+	}
+}
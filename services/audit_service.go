@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/models"
+)
+
+// AuditService 配置变更审计日志查询服务，日志由 MCPServer 和 MCPTool 的 GORM 钩子写入
+type AuditService struct {
+	db *gorm.DB
+}
+
+// NewAuditService 创建审计日志服务实例
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// List 按条件查询审计日志，按时间倒序
+func (s *AuditService) List(req *models.AuditLogListRequest) (*models.AuditLogListResponse, error) {
+	var logs []models.AuditLog
+	var total int64
+
+	query := s.db.Model(&models.AuditLog{})
+	if req.EntityType != "" {
+		query = query.Where("entity_type = ?", req.EntityType)
+	}
+	if req.EntityID > 0 {
+		query = query.Where("entity_id = ?", req.EntityID)
+	}
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+	if req.Source != "" {
+		query = query.Where("source = ?", req.Source)
+	}
+	if req.Search != "" {
+		query = query.Where("entity_name LIKE ?", "%"+req.Search+"%")
+	}
+	if !req.Since.IsZero() {
+		query = query.Where("created_at >= ?", req.Since)
+	}
+	if !req.Until.IsZero() {
+		query = query.Where("created_at <= ?", req.Until)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("获取总数失败: %v", err)
+	}
+
+	offset := (req.Page - 1) * req.Size
+	if err := query.Order("created_at desc, id desc").Offset(offset).Limit(req.Size).Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("查询审计日志失败: %v", err)
+	}
+
+	return &models.AuditLogListResponse{
+		Total: total,
+		Page:  req.Page,
+		Size:  req.Size,
+		Logs:  logs,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"desktop-ai-tools/models"
+)
+
+// TestAuditLog 测试服务器和工具变更写入审计日志
+func TestAuditLog(t *testing.T) {
+	db := newTestDB(t)
+	ctx := models.WithAuditSource(context.Background(), models.AuditSourceUI)
+	servers := (&MCPServerService{db: db}).WithContext(ctx)
+	tools := (&MCPToolService{db: db}).WithContext(ctx)
+	audit := NewAuditService(db)
+
+	server, err := servers.Create(&models.MCPServerCreateRequest{Name: "github", URL: "https://example.com/mcp", AuthType: "bearer", AuthConfig: `{"token":"secret"}`})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	for _, name := range []string{"search", "fetch"} {
+		if err := db.Create(&models.MCPTool{ServerID: server.ID, Name: name}).Error; err != nil {
+			t.Fatalf("创建工具失败: %v", err)
+		}
+	}
+
+	// 只修改运行状态不记录审计日志
+	if err := servers.UpdateStatus(server.ID, "active"); err != nil {
+		t.Fatalf("更新状态失败: %v", err)
+	}
+	if _, err := servers.ToggleEnabled(server.ID); err != nil {
+		t.Fatalf("切换启用状态失败: %v", err)
+	}
+	var toolIDs []uint
+	db.Model(&models.MCPTool{}).Pluck("id", &toolIDs)
+	if err := tools.BatchUpdateTools(&models.MCPToolBatchUpdateRequest{ToolIDs: toolIDs, Category: "代码"}); err != nil {
+		t.Fatalf("批量更新工具失败: %v", err)
+	}
+	if err := servers.Delete(server.ID); err != nil {
+		t.Fatalf("删除服务器失败: %v", err)
+	}
+
+	list := func(req models.AuditLogListRequest) []models.AuditLog {
+		req.Page, req.Size = 1, 50
+		resp, err := audit.List(&req)
+		if err != nil {
+			t.Fatalf("查询审计日志失败: %v", err)
+		}
+		return resp.Logs
+	}
+
+	var actions []string
+	for _, log := range list(models.AuditLogListRequest{EntityType: models.AuditEntityServer}) {
+		actions = append([]string{log.Action}, actions...)
+		if log.Source != models.AuditSourceUI {
+			t.Fatalf("审计来源不正确: %s", log.Source)
+		}
+		if strings.Contains(log.Before+log.After, "secret") {
+			t.Fatalf("审计日志不应包含敏感信息: %s %s", log.Before, log.After)
+		}
+	}
+	if got := strings.Join(actions, ","); got != "create,toggle,delete" {
+		t.Fatalf("服务器审计操作不正确: %s", got)
+	}
+
+	batch := list(models.AuditLogListRequest{EntityType: models.AuditEntityTool, Action: models.AuditActionBatchUpdate})
+	if len(batch) != 2 || !strings.Contains(batch[0].Before, `"category":""`) || !strings.Contains(batch[0].After, `"category":"代码"`) {
+		t.Fatalf("批量更新的审计日志不正确: %+v", batch)
+	}
+	if deleted := list(models.AuditLogListRequest{EntityType: models.AuditEntityTool, Action: models.AuditActionDelete}); len(deleted) != 2 || deleted[0].After != "" {
+		t.Fatalf("删除服务器时应记录关联工具的删除: %+v", deleted)
+	}
+	if created := list(models.AuditLogListRequest{EntityType: models.AuditEntityTool, Action: models.AuditActionCreate}); len(created) != 2 || created[0].Source != models.AuditSourceSystem {
+		t.Fatalf("未设置来源时应记录为系统操作: %+v", created)
+	}
+}
+
+// TestAuditTagChanges 测试设置、重命名、合并和删除标签时为受影响的服务器和工具写入审计日志
+func TestAuditTagChanges(t *testing.T) {
+	db := newTestDB(t)
+	ctx := models.WithAuditSource(context.Background(), models.AuditSourceUI)
+	servers := (&MCPServerService{db: db}).WithContext(ctx)
+	tags := NewTagService(db).WithContext(ctx)
+	audit := NewAuditService(db)
+
+	server, err := servers.Create(&models.MCPServerCreateRequest{Name: "github", URL: "https://example.com/mcp", AuthType: "none", Tags: []string{"beta", "alpha"}})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	tool := models.MCPTool{ServerID: server.ID, Name: "search"}
+	if err := db.Create(&tool).Error; err != nil {
+		t.Fatalf("创建工具失败: %v", err)
+	}
+	if _, err := tags.SetToolTags(tool.ID, []string{"alpha"}); err != nil {
+		t.Fatalf("设置工具标签失败: %v", err)
+	}
+
+	tagID := func(name string) uint {
+		var tag models.Tag
+		if err := db.Where("name = ?", name).First(&tag).Error; err != nil {
+			t.Fatalf("查询标签失败: %v", err)
+		}
+		return tag.ID
+	}
+	if _, err := tags.Rename(tagID("alpha"), &models.TagRenameRequest{Name: "prod"}); err != nil {
+		t.Fatalf("重命名标签失败: %v", err)
+	}
+	if _, err := tags.Merge(&models.TagMergeRequest{SourceIDs: []uint{tagID("beta")}, Target: "stable"}); err != nil {
+		t.Fatalf("合并标签失败: %v", err)
+	}
+	if err := tags.Delete(tagID("prod")); err != nil {
+		t.Fatalf("删除标签失败: %v", err)
+	}
+
+	// 按时间顺序返回对象的更新日志中标签的变化
+	changes := func(entityType string, id uint) []string {
+		resp, err := audit.List(&models.AuditLogListRequest{EntityType: entityType, EntityID: id, Action: models.AuditActionUpdate, Page: 1, Size: 50})
+		if err != nil {
+			t.Fatalf("查询审计日志失败: %v", err)
+		}
+		var result []string
+		for _, log := range resp.Logs {
+			if log.Source != models.AuditSourceUI {
+				t.Fatalf("审计来源不正确: %s", log.Source)
+			}
+			var before, after struct {
+				Tags []string `json:"tags"`
+			}
+			_ = json.Unmarshal([]byte(log.Before), &before)
+			_ = json.Unmarshal([]byte(log.After), &after)
+			result = append([]string{strings.Join(before.Tags, ",") + "->" + strings.Join(after.Tags, ",")}, result...)
+		}
+		return result
+	}
+
+	if got := strings.Join(changes(models.AuditEntityServer, server.ID), " "); got != "->alpha,beta alpha,beta->beta,prod beta,prod->prod,stable prod,stable->stable" {
+		t.Fatalf("服务器的标签审计不正确: %s", got)
+	}
+	if got := strings.Join(changes(models.AuditEntityTool, tool.ID), " "); got != "->alpha alpha->prod prod->" {
+		t.Fatalf("工具的标签审计不正确: %s", got)
+	}
+}
@@ -0,0 +1,991 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/database"
+	"desktop-ai-tools/models"
+	"desktop-ai-tools/security"
+)
+
+const (
+	// backupManifestFile 压缩包中的清单文件名
+	backupManifestFile = "manifest.json"
+	// backupDataFile 压缩包中的数据文件名
+	backupDataFile = "data.json"
+	// autoBackupPrefix 自动备份的文件名前缀，轮换时只清理这类备份
+	autoBackupPrefix = "auto-"
+	// backupCheckInterval 检查是否需要自动备份的间隔
+	backupCheckInterval = time.Minute
+)
+
+// BackupService 应用数据的备份、恢复与定时自动备份
+type BackupService struct {
+	db  *gorm.DB
+	dir string
+
+	mu       sync.Mutex // 备份与恢复串行执行
+	stop     chan struct{}
+	stopOnce sync.Once
+	running  sync.WaitGroup // 定时任务的后台协程
+}
+
+// NewBackupService 创建备份服务实例，dir 为空时使用 ~/.desktop-ai-tools/backups
+func NewBackupService(db *gorm.DB, dir string) *BackupService {
+	return &BackupService{
+		db:   db,
+		dir:  dir,
+		stop: make(chan struct{}),
+	}
+}
+
+// Create 手动创建备份
+func (s *BackupService) Create(req *models.BackupCreateRequest) (*models.BackupInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create("backup-", req.IncludeSecrets, !req.ExcludeHistory, false, req.Note)
+}
+
+// List 列出所有备份，按创建时间倒序
+func (s *BackupService) List() ([]models.BackupInfo, error) {
+	dir, err := s.backupDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取备份目录失败: %v", err)
+	}
+
+	backups := make([]models.BackupInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".zip") {
+			continue
+		}
+		info, err := s.describe(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			backupLog.Warn("读取备份失败", "file", entry.Name(), "error", err)
+			continue
+		}
+		backups = append(backups, *info)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Manifest.CreatedAt.After(backups[j].Manifest.CreatedAt)
+	})
+	return backups, nil
+}
+
+// Path 获取备份文件的完整路径
+func (s *BackupService) Path(name string) (string, error) {
+	if name == "" || filepath.Base(name) != name || !strings.HasSuffix(name, ".zip") {
+		return "", validationError("invalid_backup_name", "无效的备份文件名: %s", name)
+	}
+
+	dir, err := s.backupDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", apperrors.Newf(apperrors.KindNotFound, "backup_not_found", "备份不存在: %s", name)
+	}
+	return path, nil
+}
+
+// Delete 删除备份
+func (s *BackupService) Delete(name string) error {
+	path, err := s.Path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("删除备份失败: %v", err)
+	}
+	return nil
+}
+
+// Upload 保存上传的备份文件，校验通过后才会出现在备份列表中
+func (s *BackupService) Upload(name string, r io.Reader) (*models.BackupInfo, error) {
+	dir, err := s.backupDir()
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(dir, "upload-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("保存上传文件失败: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("保存上传文件失败: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("保存上传文件失败: %v", err)
+	}
+
+	if _, _, err := readBackupArchive(tmp.Name()); err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	if base == "" || base == "." || strings.HasPrefix(base, autoBackupPrefix) {
+		base = "uploaded-" + base
+	}
+	target := uniqueBackupPath(dir, base)
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, fmt.Errorf("保存上传文件失败: %v", err)
+	}
+	return s.describe(target)
+}
+
+// Restore 从备份恢复数据，恢复前会自动备份当前数据
+func (s *BackupService) Restore(name string, req *models.BackupRestoreRequest) (*models.BackupRestoreResult, error) {
+	path, err := s.Path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, data, err := readBackupArchive(path)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.FormatVersion > models.BackupFormatVersion {
+		return nil, validationError("backup_format_unsupported", "备份文件格式版本 %d 高于当前支持的版本 %d，请升级应用后再恢复", manifest.FormatVersion, models.BackupFormatVersion)
+	}
+	if manifest.SchemaVersion > database.SchemaVersion {
+		return nil, validationError("backup_schema_unsupported", "备份的数据库结构版本 %d 高于当前版本 %d，请升级应用后再恢复", manifest.SchemaVersion, database.SchemaVersion)
+	}
+
+	if manifest.IncludesSecrets {
+		if err := openBackupSecrets(data); err != nil {
+			return nil, validationError("backup_secrets_undecryptable", "无法解密备份中的敏感信息，备份可能来自其他设备或加密密钥已删除: %v", err)
+		}
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.RestoreModeMerge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	safety, err := s.create("pre-restore-", true, true, false, "恢复 "+name+" 前自动创建")
+	if err != nil {
+		return nil, fmt.Errorf("创建恢复前备份失败: %v", err)
+	}
+
+	result := &models.BackupRestoreResult{
+		Mode:         mode,
+		SafetyBackup: safety.Name,
+		Restored:     make(map[string]int),
+	}
+
+	// 恢复产生的变更在审计日志中记录为备份来源
+	ctx := models.WithAuditSource(context.Background(), models.AuditSourceBackup)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if mode == models.RestoreModeReplace {
+			return restoreReplace(tx, manifest, data, result)
+		}
+		return restoreMerge(tx, manifest, data, result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("恢复备份失败: %w", err)
+	}
+	return result, nil
+}
+
+// GetSchedule 获取自动备份设置
+func (s *BackupService) GetSchedule() (*models.BackupSchedule, error) {
+	var schedule models.BackupSchedule
+	err := s.db.First(&schedule).Error
+	if err == gorm.ErrRecordNotFound {
+		return &models.BackupSchedule{
+			IntervalHours:  24,
+			Keep:           7,
+			IncludeHistory: true,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询自动备份设置失败: %v", err)
+	}
+	return &schedule, nil
+}
+
+// UpdateSchedule 更新自动备份设置
+func (s *BackupService) UpdateSchedule(req *models.BackupScheduleUpdateRequest) (*models.BackupSchedule, error) {
+	schedule, err := s.GetSchedule()
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Enabled = req.Enabled
+	schedule.IntervalHours = req.IntervalHours
+	schedule.Keep = req.Keep
+	schedule.IncludeSecrets = req.IncludeSecrets
+	schedule.IncludeHistory = req.IncludeHistory
+
+	if err := s.db.Save(schedule).Error; err != nil {
+		return nil, fmt.Errorf("保存自动备份设置失败: %v", err)
+	}
+	return schedule, nil
+}
+
+// StartScheduler 启动定时自动备份
+func (s *BackupService) StartScheduler() {
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		ticker := time.NewTicker(backupCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runScheduled(time.Now())
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopScheduler 停止定时自动备份，正在执行的自动备份完成后返回
+func (s *BackupService) StopScheduler() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.running.Wait()
+}
+
+// runScheduled 到期时执行自动备份并轮换旧备份
+func (s *BackupService) runScheduled(now time.Time) {
+	schedule, err := s.GetSchedule()
+	if err != nil || !schedule.Enabled || schedule.ID == 0 {
+		return
+	}
+	if schedule.LastBackupAt != nil && now.Sub(*schedule.LastBackupAt) < time.Duration(schedule.IntervalHours)*time.Hour {
+		return
+	}
+
+	s.mu.Lock()
+	_, err = s.create(autoBackupPrefix, schedule.IncludeSecrets, schedule.IncludeHistory, true, "")
+	if err == nil {
+		err = s.rotate(schedule.Keep)
+	}
+	s.mu.Unlock()
+
+	updates := map[string]interface{}{
+		"last_backup_at":    now,
+		"last_backup_error": "",
+	}
+	if err != nil {
+		backupLog.Error("自动备份失败", "error", err)
+		updates["last_backup_error"] = err.Error()
+	}
+	if err := s.db.Model(schedule).Updates(updates).Error; err != nil {
+		backupLog.Error("更新自动备份状态失败", "error", err)
+	}
+}
+
+// rotate 只保留最近的 keep 个自动备份
+func (s *BackupService) rotate(keep int) error {
+	backups, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	for _, backup := range backups {
+		if !strings.HasPrefix(backup.Name, autoBackupPrefix) {
+			continue
+		}
+		kept++
+		if kept <= keep {
+			continue
+		}
+		if err := os.Remove(backup.Path); err != nil {
+			return fmt.Errorf("清理旧备份 %s 失败: %v", backup.Name, err)
+		}
+	}
+	return nil
+}
+
+// create 导出数据并写入备份压缩包，调用方需持有 s.mu
+func (s *BackupService) create(prefix string, includeSecrets, includeHistory, automatic bool, note string) (*models.BackupInfo, error) {
+	dir, err := s.backupDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.collect(includeSecrets, includeHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := models.BackupManifest{
+		FormatVersion:   models.BackupFormatVersion,
+		SchemaVersion:   database.SchemaVersion,
+		CreatedAt:       time.Now(),
+		Automatic:       automatic,
+		IncludesSecrets: includeSecrets,
+		IncludesHistory: includeHistory,
+		Note:            note,
+		Excludes:        []string{models.BackupExcludesConfigFile},
+		Counts: map[string]int{
+			"servers":       len(data.Servers),
+			"tools":         len(data.Tools),
+			"roots":         len(data.Roots),
+			"secrets":       len(data.Secrets),
+			"sampling_logs": len(data.SamplingLogs),
+			"server_logs":   len(data.ServerLogs),
+			"audit_logs":    len(data.AuditLogs),
+		},
+	}
+
+	path := uniqueBackupPath(dir, prefix+manifest.CreatedAt.Format("20060102-150405"))
+	if err := writeBackupArchive(path, &manifest, data); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return s.describe(path)
+}
+
+// collect 从数据库读取需要备份的数据
+func (s *BackupService) collect(includeSecrets, includeHistory bool) (*models.BackupData, error) {
+	data := &models.BackupData{}
+
+	if err := s.db.Order("id").Find(&data.Workspaces).Error; err != nil {
+		return nil, fmt.Errorf("查询工作区失败: %v", err)
+	}
+	if err := s.db.Preload("Tags").Order("id").Find(&data.Servers).Error; err != nil {
+		return nil, fmt.Errorf("查询服务器失败: %v", err)
+	}
+	if err := s.db.Preload("Tags").Order("id").Find(&data.Tools).Error; err != nil {
+		return nil, fmt.Errorf("查询工具失败: %v", err)
+	}
+	if err := s.db.Order("id").Find(&data.Roots).Error; err != nil {
+		return nil, fmt.Errorf("查询根目录失败: %v", err)
+	}
+	if err := s.db.Order("id").Find(&data.Secrets).Error; err != nil {
+		return nil, fmt.Errorf("查询密钥失败: %v", err)
+	}
+
+	var sampling models.SamplingConfig
+	if err := s.db.First(&sampling).Error; err == nil {
+		data.SamplingConfig = &sampling
+	}
+	var schedule models.BackupSchedule
+	if err := s.db.First(&schedule).Error; err == nil {
+		data.BackupSchedule = &schedule
+	}
+
+	if includeHistory {
+		if err := s.db.Order("id").Find(&data.SamplingLogs).Error; err != nil {
+			return nil, fmt.Errorf("查询采样日志失败: %v", err)
+		}
+		if err := s.db.Order("id").Find(&data.ServerLogs).Error; err != nil {
+			return nil, fmt.Errorf("查询服务器日志失败: %v", err)
+		}
+		if err := s.db.Order("id").Find(&data.AuditLogs).Error; err != nil {
+			return nil, fmt.Errorf("查询审计日志失败: %v", err)
+		}
+	}
+
+	if includeSecrets {
+		// 敏感信息使用主密钥加密后写入压缩包，不保存明文
+		if err := sealBackupSecrets(data); err != nil {
+			return nil, fmt.Errorf("加密备份中的敏感信息失败: %v", err)
+		}
+	} else {
+		for i := range data.Servers {
+			stripServerSecrets(&data.Servers[i])
+		}
+		if data.SamplingConfig != nil {
+			data.SamplingConfig.APIKey = ""
+		}
+		for i := range data.Secrets {
+			data.Secrets[i].Value = ""
+		}
+	}
+	return data, nil
+}
+
+// describe 读取备份文件信息
+func (s *BackupService) describe(path string) (*models.BackupInfo, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取备份文件失败: %v", err)
+	}
+	manifest, err := readBackupManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BackupInfo{
+		Name:     filepath.Base(path),
+		Path:     path,
+		Size:     stat.Size(),
+		Manifest: *manifest,
+	}, nil
+}
+
+// backupDir 获取备份目录，不存在时自动创建
+func (s *BackupService) backupDir() (string, error) {
+	dir := s.dir
+	if dir == "" {
+		appDataDir, err := database.AppDataDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(appDataDir, "backups")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("创建备份目录失败: %v", err)
+	}
+	return dir, nil
+}
+
+// restoreReplace 清空现有数据后按原ID恢复
+func restoreReplace(tx *gorm.DB, manifest *models.BackupManifest, data *models.BackupData, result *models.BackupRestoreResult) error {
+	// 先恢复审计日志，之后清空和恢复数据产生的变更记录在备份的审计日志之后
+	if manifest.IncludesHistory && data.AuditLogs != nil {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.AuditLog{}).Error; err != nil {
+			return err
+		}
+		if err := insertRows(tx, data.AuditLogs); err != nil {
+			return err
+		}
+		result.Restored["audit_logs"] = len(data.AuditLogs)
+	}
+
+	tables := []interface{}{&models.MCPTool{}, &models.MCPServer{}, &models.WorkspaceRoot{}, &models.SamplingConfig{}, &models.Secret{}, &models.Workspace{}}
+	if manifest.IncludesHistory {
+		tables = append(tables, &models.SamplingLog{}, &models.MCPServerLog{})
+	} else {
+		result.SkippedHistory = true
+	}
+	for _, table := range tables {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(table).Error; err != nil {
+			return err
+		}
+	}
+	for _, link := range tagLinkTables {
+		if err := tx.Exec("DELETE FROM " + link.table).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.Tag{}).Error; err != nil {
+		return err
+	}
+
+	for i := range data.ServerLogs {
+		data.ServerLogs[i].LevelRank = models.ServerLogLevelRank(data.ServerLogs[i].Level)
+	}
+
+	if err := restoreWorkspaces(tx, data); err != nil {
+		return err
+	}
+	if err := insertRows(tx, data.Servers); err != nil {
+		return err
+	}
+	if err := insertRows(tx, data.Tools); err != nil {
+		return err
+	}
+	// 标签按名称重建，不沿用备份中的标签ID
+	for i := range data.Servers {
+		if err := replaceTags(tx, &models.MCPServer{ID: data.Servers[i].ID}, data.Servers[i].GetTagList()); err != nil {
+			return err
+		}
+	}
+	for i := range data.Tools {
+		if err := replaceTags(tx, &models.MCPTool{ID: data.Tools[i].ID}, data.Tools[i].Tags.Names()); err != nil {
+			return err
+		}
+	}
+	if err := insertRows(tx, data.Roots); err != nil {
+		return err
+	}
+	if err := insertRows(tx, data.Secrets); err != nil {
+		return err
+	}
+	if manifest.IncludesHistory {
+		if err := insertRows(tx, data.SamplingLogs); err != nil {
+			return err
+		}
+		if err := insertRows(tx, data.ServerLogs); err != nil {
+			return err
+		}
+	}
+	if data.SamplingConfig != nil {
+		if err := tx.Create(data.SamplingConfig).Error; err != nil {
+			return err
+		}
+		result.Restored["sampling_config"] = 1
+	}
+	if err := restoreSchedule(tx, data.BackupSchedule); err != nil {
+		return err
+	}
+
+	result.Restored["workspaces"] = len(data.Workspaces)
+	result.Restored["servers"] = len(data.Servers)
+	result.Restored["tools"] = len(data.Tools)
+	result.Restored["roots"] = len(data.Roots)
+	result.Restored["secrets"] = len(data.Secrets)
+	result.Restored["sampling_logs"] = len(data.SamplingLogs)
+	result.Restored["server_logs"] = len(data.ServerLogs)
+	return nil
+}
+
+// restoreWorkspaces 按原ID恢复工作区，旧版本备份没有工作区时创建默认工作区并将服务器归入其中
+func restoreWorkspaces(tx *gorm.DB, data *models.BackupData) error {
+	if len(data.Workspaces) == 0 {
+		data.Workspaces = []models.Workspace{{Name: "默认工作区", IsActive: true}}
+		if err := tx.Create(&data.Workspaces[0]).Error; err != nil {
+			return err
+		}
+	} else {
+		// 保证恢复后恰好有一个激活的工作区
+		active := -1
+		for i := range data.Workspaces {
+			if data.Workspaces[i].IsActive && active < 0 {
+				active = i
+			}
+			data.Workspaces[i].IsActive = false
+		}
+		if active < 0 {
+			active = 0
+		}
+		data.Workspaces[active].IsActive = true
+		if err := insertRows(tx, data.Workspaces); err != nil {
+			return err
+		}
+	}
+
+	known := make(map[uint]bool, len(data.Workspaces))
+	var fallback uint
+	for _, workspace := range data.Workspaces {
+		known[workspace.ID] = true
+		if workspace.IsActive {
+			fallback = workspace.ID
+		}
+	}
+	serverWorkspaces := make(map[uint]uint, len(data.Servers))
+	for i := range data.Servers {
+		if !known[data.Servers[i].WorkspaceID] {
+			data.Servers[i].WorkspaceID = fallback
+		}
+		serverWorkspaces[data.Servers[i].ID] = data.Servers[i].WorkspaceID
+	}
+	// 服务器专属的根目录跟随服务器所在的工作区，旧版本备份中的全局根目录归入当前工作区
+	for i := range data.Roots {
+		root := &data.Roots[i]
+		if root.ServerID != nil {
+			if workspaceID, ok := serverWorkspaces[*root.ServerID]; ok {
+				root.WorkspaceID = workspaceID
+				continue
+			}
+		}
+		if !known[root.WorkspaceID] {
+			root.WorkspaceID = fallback
+		}
+	}
+	return nil
+}
+
+// restoreMerge 按名称合并到现有数据，不恢复历史记录
+func restoreMerge(tx *gorm.DB, manifest *models.BackupManifest, data *models.BackupData, result *models.BackupRestoreResult) error {
+	result.SkippedHistory = true
+
+	// 工作区按名称匹配，不改变当前激活的工作区
+	activeID, err := activeWorkspaceID(tx)
+	if err != nil {
+		return err
+	}
+	workspaceIDs := make(map[uint]uint, len(data.Workspaces))
+	for _, backup := range data.Workspaces {
+		var existing models.Workspace
+		err := tx.Where("name = ?", backup.Name).First(&existing).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			oldID := backup.ID
+			backup.ID = 0
+			backup.IsActive = false
+			if err := tx.Create(&backup).Error; err != nil {
+				return err
+			}
+			workspaceIDs[oldID] = backup.ID
+			result.Restored["workspaces"]++
+		case err != nil:
+			return err
+		default:
+			workspaceIDs[backup.ID] = existing.ID
+		}
+	}
+
+	// 服务器在所属工作区内按名称匹配，旧版本备份中的服务器归入当前工作区
+	serverIDs := make(map[uint]uint, len(data.Servers))
+	serverWorkspaces := make(map[uint]uint, len(data.Servers))
+	for _, backup := range data.Servers {
+		oldID := backup.ID
+		workspaceID, ok := workspaceIDs[backup.WorkspaceID]
+		if !ok {
+			workspaceID = activeID
+		}
+		var existing models.MCPServer
+		err := tx.Where("workspace_id = ? AND name = ?", workspaceID, backup.Name).First(&existing).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			backup.ID = 0
+			backup.WorkspaceID = workspaceID
+			backup.Tools = nil
+			if err := tx.Omit(clause.Associations).Create(&backup).Error; err != nil {
+				return err
+			}
+			serverIDs[oldID] = backup.ID
+		case err != nil:
+			return err
+		default:
+			if !manifest.IncludesSecrets {
+				keepServerSecrets(&backup, &existing)
+			}
+			if err := tx.Model(&existing).Updates(map[string]interface{}{
+				"description":     backup.Description,
+				"url":             backup.URL,
+				"transport":       backup.Transport,
+				"command":         backup.Command,
+				"args":            backup.Args,
+				"env":             backup.Env,
+				"headers":         backup.Headers,
+				"auth_type":       backup.AuthType,
+				"auth_config":     backup.AuthConfig,
+				"is_enabled":      backup.IsEnabled,
+				"sampling_policy": backup.SamplingPolicy,
+				"log_level":       backup.LogLevel,
+			}).Error; err != nil {
+				return err
+			}
+			serverIDs[oldID] = existing.ID
+		}
+		serverWorkspaces[serverIDs[oldID]] = workspaceID
+		if err := replaceTags(tx, &models.MCPServer{ID: serverIDs[oldID]}, backup.GetTagList()); err != nil {
+			return err
+		}
+		result.Restored["servers"]++
+	}
+
+	// 工具按服务器和名称匹配，保留用户的启用、分类和缓存设置
+	for _, backup := range data.Tools {
+		serverID, ok := serverIDs[backup.ServerID]
+		if !ok {
+			continue
+		}
+		var existing models.MCPTool
+		err := tx.Where("server_id = ? AND name = ?", serverID, backup.Name).First(&existing).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			backup.ID = 0
+			backup.ServerID = serverID
+			if err := tx.Omit(clause.Associations).Create(&backup).Error; err != nil {
+				return err
+			}
+			existing.ID = backup.ID
+		case err != nil:
+			return err
+		default:
+			if err := tx.Model(&existing).Updates(map[string]interface{}{
+				"is_enabled":    backup.IsEnabled,
+				"category":      backup.Category,
+				"cache_enabled": backup.CacheEnabled,
+				"cache_ttl":     backup.CacheTTL,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if err := replaceTags(tx, &models.MCPTool{ID: existing.ID}, backup.Tags.Names()); err != nil {
+			return err
+		}
+		result.Restored["tools"]++
+	}
+
+	// 根目录按工作区、服务器和路径去重，服务器专属的根目录跟随服务器所在的工作区
+	for _, backup := range data.Roots {
+		workspaceID, ok := workspaceIDs[backup.WorkspaceID]
+		if !ok {
+			workspaceID = activeID
+		}
+		if backup.ServerID != nil {
+			serverID, ok := serverIDs[*backup.ServerID]
+			if !ok {
+				continue
+			}
+			backup.ServerID = &serverID
+			workspaceID = serverWorkspaces[serverID]
+		}
+		backup.WorkspaceID = workspaceID
+
+		query := tx.Model(&models.WorkspaceRoot{}).Where("workspace_id = ? AND path = ?", workspaceID, backup.Path)
+		if backup.ServerID == nil {
+			query = query.Where("server_id IS NULL")
+		} else {
+			query = query.Where("server_id = ?", *backup.ServerID)
+		}
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		backup.ID = 0
+		if err := tx.Create(&backup).Error; err != nil {
+			return err
+		}
+		result.Restored["roots"]++
+	}
+
+	// 密钥按名称合并，备份中没有值时保留现有值
+	for _, backup := range data.Secrets {
+		var existing models.Secret
+		err := tx.Where("name = ?", backup.Name).First(&existing).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			backup.ID = 0
+			if err := tx.Create(&backup).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			updates := map[string]interface{}{"description": backup.Description}
+			if backup.Value != "" {
+				updates["value"] = backup.Value
+			}
+			if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		result.Restored["secrets"]++
+	}
+
+	if data.SamplingConfig != nil {
+		var existing models.SamplingConfig
+		err := tx.First(&existing).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		config := *data.SamplingConfig
+		config.ID = existing.ID
+		if config.APIKey == "" {
+			config.APIKey = existing.APIKey
+		}
+		if err := tx.Save(&config).Error; err != nil {
+			return err
+		}
+		result.Restored["sampling_config"] = 1
+	}
+
+	return restoreSchedule(tx, data.BackupSchedule)
+}
+
+// restoreSchedule 恢复自动备份设置，保留本机的上次备份时间
+func restoreSchedule(tx *gorm.DB, backup *models.BackupSchedule) error {
+	if backup == nil {
+		return nil
+	}
+
+	var existing models.BackupSchedule
+	err := tx.First(&existing).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	existing.Enabled = backup.Enabled
+	existing.IntervalHours = backup.IntervalHours
+	existing.Keep = backup.Keep
+	existing.IncludeSecrets = backup.IncludeSecrets
+	existing.IncludeHistory = backup.IncludeHistory
+	return tx.Save(&existing).Error
+}
+
+// insertRows 按原ID批量插入数据
+func insertRows[T any](tx *gorm.DB, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).CreateInBatches(rows, 100).Error
+}
+
+// sealBackupSecrets 加密备份中的敏感信息
+func sealBackupSecrets(data *models.BackupData) error {
+	return convertBackupSecrets(data, security.Encrypt)
+}
+
+// openBackupSecrets 解密备份中的敏感信息，旧版本备份中的明文原样保留
+func openBackupSecrets(data *models.BackupData) error {
+	return convertBackupSecrets(data, security.Decrypt)
+}
+
+// convertBackupSecrets 转换服务器的认证配置、环境变量和请求头，以及密钥值和采样API Key
+func convertBackupSecrets(data *models.BackupData, convert func(string) (string, error)) error {
+	var fields []*models.EncryptedString
+	for i := range data.Servers {
+		fields = append(fields, &data.Servers[i].AuthConfig, &data.Servers[i].Env, &data.Servers[i].Headers)
+	}
+	for i := range data.Secrets {
+		fields = append(fields, &data.Secrets[i].Value)
+	}
+	if data.SamplingConfig != nil {
+		fields = append(fields, &data.SamplingConfig.APIKey)
+	}
+	for _, field := range fields {
+		value, err := convert(string(*field))
+		if err != nil {
+			return err
+		}
+		*field = models.EncryptedString(value)
+	}
+	return nil
+}
+
+// stripServerSecrets 清除服务器配置中的敏感信息
+func stripServerSecrets(server *models.MCPServer) {
+	server.AuthConfig = ""
+	server.SetConnection(server.GetArgs(), blankSecretValues(server.GetEnv()), blankSecretValues(server.GetHeaders()))
+}
+
+// keepServerSecrets 备份不含敏感信息时，合并恢复保留现有的敏感配置
+func keepServerSecrets(backup, existing *models.MCPServer) {
+	if backup.AuthConfig == "" {
+		backup.AuthConfig = existing.AuthConfig
+	}
+	env := backup.GetEnv()
+	for k, v := range existing.GetEnv() {
+		if current, ok := env[k]; ok && current == "" {
+			env[k] = v
+		}
+	}
+	headers := backup.GetHeaders()
+	for k, v := range existing.GetHeaders() {
+		if current, ok := headers[k]; ok && current == "" {
+			headers[k] = v
+		}
+	}
+	backup.SetConnection(backup.GetArgs(), env, headers)
+}
+
+// blankSecretValues 清空敏感键对应的值，保留键名以便恢复后补填
+func blankSecretValues(values map[string]string) map[string]string {
+	for k := range values {
+		if models.IsSecretKey(k) {
+			values[k] = ""
+		}
+	}
+	return values
+}
+
+// writeBackupArchive 写入备份压缩包
+func writeBackupArchive(path string, manifest *models.BackupManifest, data *models.BackupData) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("创建备份文件失败: %v", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	for name, content := range map[string]interface{}{backupManifestFile: manifest, backupDataFile: data} {
+		w, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("写入备份文件失败: %v", err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(content); err != nil {
+			return fmt.Errorf("写入备份文件失败: %v", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("写入备份文件失败: %v", err)
+	}
+	return file.Sync()
+}
+
+// readBackupManifest 只读取备份的清单信息
+func readBackupManifest(path string) (*models.BackupManifest, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, validationError("invalid_backup_file", "打开备份文件失败: %v", err)
+	}
+	defer archive.Close()
+
+	var manifest models.BackupManifest
+	if err := readArchiveJSON(&archive.Reader, backupManifestFile, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// readBackupArchive 读取备份的清单和数据
+func readBackupArchive(path string) (*models.BackupManifest, *models.BackupData, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, validationError("invalid_backup_file", "打开备份文件失败: %v", err)
+	}
+	defer archive.Close()
+
+	var manifest models.BackupManifest
+	if err := readArchiveJSON(&archive.Reader, backupManifestFile, &manifest); err != nil {
+		return nil, nil, err
+	}
+	if manifest.FormatVersion == 0 {
+		return nil, nil, validationError("backup_missing_format_version", "无效的备份文件：缺少格式版本")
+	}
+
+	var data models.BackupData
+	if err := readArchiveJSON(&archive.Reader, backupDataFile, &data); err != nil {
+		return nil, nil, err
+	}
+	return &manifest, &data, nil
+}
+
+// readArchiveJSON 读取压缩包中的JSON文件
+func readArchiveJSON(archive *zip.Reader, name string, v interface{}) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return validationError("backup_missing_entry", "无效的备份文件：缺少 %s", name)
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(v); err != nil {
+		return validationError("invalid_backup_entry", "解析 %s 失败: %v", name, err)
+	}
+	return nil
+}
+
+// uniqueBackupPath 生成不与现有文件冲突的备份路径
+func uniqueBackupPath(dir, base string) string {
+	path := filepath.Join(dir, base+".zip")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.zip", base, i))
+	}
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"desktop-ai-tools/database"
+	"desktop-ai-tools/models"
+	"desktop-ai-tools/security"
+)
+
+// newTestDB 创建测试使用的临时数据库
+func newTestDB(t *testing.T) *gorm.DB {
+	if _, err := security.InitKeyring(t.TempDir()); err != nil {
+		t.Fatalf("初始化主密钥失败: %v", err)
+	}
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	if _, err := database.Migrate(db, ""); err != nil {
+		t.Fatalf("迁移数据库失败: %v", err)
+	}
+	return db
+}
+
+// TestBackupRestore 测试备份与两种恢复模式
+func TestBackupRestore(t *testing.T) {
+	db := newTestDB(t)
+	service := NewBackupService(db, t.TempDir())
+
+	workspaceID, err := activeWorkspaceID(db)
+	if err != nil {
+		t.Fatalf("查询默认工作区失败: %v", err)
+	}
+	server := models.MCPServer{WorkspaceID: workspaceID, Name: "github", URL: "https://example.com/mcp", AuthType: "bearer", AuthConfig: `{"token":"secret"}`}
+	server.SetConnection(nil, map[string]string{"GITHUB_TOKEN": "ghp_x", "REGION": "cn"}, nil)
+	if err := db.Create(&server).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	disabled := false
+	cacheTTL := 30
+	tool := models.MCPTool{ServerID: server.ID, Name: "search", Category: "自定义", CacheEnabled: &disabled, CacheTTL: cacheTTL}
+	if err := db.Create(&tool).Error; err != nil {
+		t.Fatalf("创建工具失败: %v", err)
+	}
+	db.Create(&models.MCPServerLog{ServerID: server.ID, Level: "error", Data: `"boom"`})
+
+	// 认证配置在数据库中应为密文
+	var raw string
+	db.Table("mcp_servers").Select("auth_config").Where("id = ?", server.ID).Scan(&raw)
+	if !security.IsEncrypted(raw) {
+		t.Fatalf("认证配置未加密: %s", raw)
+	}
+
+	backup, err := service.Create(&models.BackupCreateRequest{})
+	if err != nil {
+		t.Fatalf("创建备份失败: %v", err)
+	}
+	if backup.Manifest.IncludesSecrets || backup.Manifest.Counts["servers"] != 1 {
+		t.Fatalf("备份清单错误: %+v", backup.Manifest)
+	}
+
+	// 合并恢复：不含敏感信息的备份不应覆盖现有的Token
+	db.Model(&tool).Update("category", "已修改")
+	if _, err := service.Restore(backup.Name, &models.BackupRestoreRequest{Mode: models.RestoreModeMerge}); err != nil {
+		t.Fatalf("合并恢复失败: %v", err)
+	}
+	var merged models.MCPServer
+	db.First(&merged, server.ID)
+	if merged.AuthConfig != server.AuthConfig || merged.GetEnv()["GITHUB_TOKEN"] != "ghp_x" {
+		t.Fatalf("合并恢复丢失了敏感配置: %+v", merged)
+	}
+	var restoredTool models.MCPTool
+	db.First(&restoredTool, tool.ID)
+	if restoredTool.Category != "自定义" {
+		t.Fatalf("工具设置未恢复: %s", restoredTool.Category)
+	}
+
+	// 覆盖恢复：删除的服务器和日志应按原ID恢复
+	db.Unscoped().Where("1 = 1").Delete(&models.MCPServer{})
+	db.Unscoped().Where("1 = 1").Delete(&models.MCPServerLog{})
+	result, err := service.Restore(backup.Name, &models.BackupRestoreRequest{Mode: models.RestoreModeReplace})
+	if err != nil {
+		t.Fatalf("覆盖恢复失败: %v", err)
+	}
+	if result.SafetyBackup == "" {
+		t.Fatal("恢复前应自动创建备份")
+	}
+	var logs []models.MCPServerLog
+	db.Find(&logs)
+	if len(logs) != 1 || logs[0].LevelRank != models.ServerLogLevelRank("error") {
+		t.Fatalf("服务器日志恢复错误: %+v", logs)
+	}
+	var restored models.MCPServer
+	if err := db.First(&restored, server.ID).Error; err != nil {
+		t.Fatalf("服务器未恢复: %v", err)
+	}
+}
+
+// TestBackupRestoreAuditLogs 测试备份包含审计日志且不包含配置文件：覆盖恢复回到备份时的审计日志，
+// 并在其后记录恢复产生的变更，合并恢复不改变审计日志
+func TestBackupRestoreAuditLogs(t *testing.T) {
+	db := newTestDB(t)
+	service := NewBackupService(db, t.TempDir())
+	servers := &MCPServerService{db: db}
+
+	first, err := servers.Create(&models.MCPServerCreateRequest{Name: "first", URL: "https://example.com/mcp", AuthType: "none"})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	backup, err := service.Create(&models.BackupCreateRequest{})
+	if err != nil {
+		t.Fatalf("创建备份失败: %v", err)
+	}
+	if backup.Manifest.Counts["audit_logs"] != 1 || len(backup.Manifest.Excludes) != 1 || backup.Manifest.Excludes[0] != models.BackupExcludesConfigFile {
+		t.Fatalf("备份清单应包含审计日志并注明不包含配置文件: %+v", backup.Manifest)
+	}
+
+	// 备份之后的变更
+	if _, err := servers.Create(&models.MCPServerCreateRequest{Name: "second", URL: "https://example.com/mcp", AuthType: "none"}); err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	var before []models.AuditLog
+	db.Order("id").Find(&before)
+
+	if _, err := service.Restore(backup.Name, &models.BackupRestoreRequest{Mode: models.RestoreModeMerge}); err != nil {
+		t.Fatalf("合并恢复失败: %v", err)
+	}
+	var merged []models.AuditLog
+	db.Order("id").Find(&merged)
+	if len(merged) != len(before) {
+		t.Fatalf("合并恢复不应改变审计日志: %d -> %d", len(before), len(merged))
+	}
+
+	result, err := service.Restore(backup.Name, &models.BackupRestoreRequest{Mode: models.RestoreModeReplace})
+	if err != nil {
+		t.Fatalf("覆盖恢复失败: %v", err)
+	}
+	if result.Restored["audit_logs"] != 1 {
+		t.Fatalf("应恢复1条审计日志: %+v", result.Restored)
+	}
+
+	var logs []models.AuditLog
+	db.Order("id").Find(&logs)
+	if len(logs) == 0 || logs[0].ID != before[0].ID || logs[0].EntityID != first.ID || logs[0].Action != models.AuditActionCreate || logs[0].Source == models.AuditSourceBackup {
+		t.Fatalf("应按原ID恢复备份中的审计日志: %+v", logs)
+	}
+	for _, log := range logs[1:] {
+		if log.Source != models.AuditSourceBackup {
+			t.Fatalf("备份之后的审计日志应被替换为恢复产生的记录: %+v", log)
+		}
+	}
+	var deletedSecond bool
+	for _, log := range logs {
+		deletedSecond = deletedSecond || (log.EntityName == "second" && log.Action == models.AuditActionDelete)
+	}
+	if !deletedSecond {
+		t.Fatalf("恢复删除备份之后创建的服务器时应记录审计日志: %+v", logs)
+	}
+}
+
+// TestBackupSecretsEncrypted 测试恢复前自动创建的备份中敏感信息以密文保存，恢复该备份时解密还原
+func TestBackupSecretsEncrypted(t *testing.T) {
+	db := newTestDB(t)
+	service := NewBackupService(db, t.TempDir())
+
+	workspaceID, err := activeWorkspaceID(db)
+	if err != nil {
+		t.Fatalf("查询默认工作区失败: %v", err)
+	}
+	server := models.MCPServer{WorkspaceID: workspaceID, Name: "github", URL: "https://example.com/mcp", AuthType: "bearer", AuthConfig: `{"token":"tok-plain"}`}
+	server.SetConnection(nil, map[string]string{"GITHUB_TOKEN": "ghp-plain"}, map[string]string{"X-Api-Key": "key-plain"})
+	if err := db.Create(&server).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	if err := db.Create(&models.Secret{Name: "gh", Value: "secret-plain"}).Error; err != nil {
+		t.Fatalf("创建密钥失败: %v", err)
+	}
+
+	backup, err := service.Create(&models.BackupCreateRequest{})
+	if err != nil {
+		t.Fatalf("创建备份失败: %v", err)
+	}
+	result, err := service.Restore(backup.Name, &models.BackupRestoreRequest{Mode: models.RestoreModeReplace})
+	if err != nil {
+		t.Fatalf("覆盖恢复失败: %v", err)
+	}
+
+	// 压缩包中的数据不应包含任何明文敏感信息
+	path, err := service.Path(result.SafetyBackup)
+	if err != nil {
+		t.Fatalf("获取备份路径失败: %v", err)
+	}
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("打开备份失败: %v", err)
+	}
+	defer archive.Close()
+	file, err := archive.Open(backupDataFile)
+	if err != nil {
+		t.Fatalf("读取备份数据失败: %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	for _, plain := range []string{"tok-plain", "ghp-plain", "key-plain", "secret-plain"} {
+		if strings.Contains(string(content), plain) {
+			t.Fatalf("恢复前备份中不应出现明文 %s", plain)
+		}
+	}
+
+	// 覆盖恢复后敏感信息已被清除，恢复自动备份应还原
+	if _, err := service.Restore(result.SafetyBackup, &models.BackupRestoreRequest{Mode: models.RestoreModeReplace}); err != nil {
+		t.Fatalf("恢复自动备份失败: %v", err)
+	}
+	var restored models.MCPServer
+	db.First(&restored, server.ID)
+	if restored.AuthConfig != server.AuthConfig || restored.GetEnv()["GITHUB_TOKEN"] != "ghp-plain" || restored.GetHeaders()["X-Api-Key"] != "key-plain" {
+		t.Fatalf("恢复后敏感配置不正确: %+v", restored)
+	}
+	var secret models.Secret
+	db.Where("name = ?", "gh").First(&secret)
+	if secret.Value != "secret-plain" {
+		t.Fatalf("恢复后密钥值不正确: %q", secret.Value)
+	}
+}
+
+// TestBackupPathValidation 测试备份文件名校验
+func TestBackupPathValidation(t *testing.T) {
+	service := NewBackupService(nil, t.TempDir())
+	for _, name := range []string{"", "../app.db", "a/b.zip", "backup.txt"} {
+		if _, err := service.Path(name); err == nil {
+			t.Fatalf("文件名 %q 应校验失败", name)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"desktop-ai-tools/models"
+	"desktop-ai-tools/utils"
+)
+
+// clientServerEntry 客户端配置文件中的单个服务器配置
+// Claude Desktop、Cursor 使用 mcpServers，VS Code 使用 servers，字段基本一致
+type clientServerEntry struct {
+	Type    string            `json:"type,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// rawClientServerEntry 解析用的宽松结构，环境变量和请求头的值可能不是字符串
+type rawClientServerEntry struct {
+	Type    string                 `json:"type"`
+	Command string                 `json:"command"`
+	Args    []interface{}          `json:"args"`
+	Env     map[string]interface{} `json:"env"`
+	URL     string                 `json:"url"`
+	Headers map[string]interface{} `json:"headers"`
+}
+
+// parsedClientConfig 解析后的客户端配置
+type parsedClientConfig struct {
+	Format  string
+	Names   []string // 按名称排序，保证预览结果稳定
+	Servers map[string]clientServerEntry
+}
+
+// parseClientConfig 解析客户端配置文件内容，format 为空或 auto 时自动识别
+func parseClientConfig(format string, data []byte) (*parsedClientConfig, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(utils.StripJSONComments(data), &root); err != nil {
+		return nil, validationError("invalid_client_config", "解析配置文件失败: %v", err)
+	}
+
+	serversRaw, detected := findServersSection(root)
+	if serversRaw == nil {
+		return nil, validationError("client_config_no_servers", "配置文件中未找到 mcpServers 或 servers 配置")
+	}
+	if format == "" || format == "auto" {
+		format = detected
+	}
+
+	var entries map[string]rawClientServerEntry
+	if err := json.Unmarshal(serversRaw, &entries); err != nil {
+		return nil, validationError("invalid_client_server_config", "解析服务器配置失败: %v", err)
+	}
+
+	config := &parsedClientConfig{
+		Format:  format,
+		Names:   make([]string, 0, len(entries)),
+		Servers: make(map[string]clientServerEntry, len(entries)),
+	}
+	for name, raw := range entries {
+		config.Names = append(config.Names, name)
+		config.Servers[name] = clientServerEntry{
+			Type:    raw.Type,
+			Command: raw.Command,
+			Args:    stringifySlice(raw.Args),
+			Env:     stringifyMap(raw.Env),
+			URL:     raw.URL,
+			Headers: stringifyMap(raw.Headers),
+		}
+	}
+	sort.Strings(config.Names)
+	return config, nil
+}
+
+// findServersSection 查找服务器配置所在的节点，并返回推断出的格式
+func findServersSection(root map[string]json.RawMessage) (json.RawMessage, string) {
+	if raw, ok := root["mcpServers"]; ok {
+		return raw, models.ClientFormatGeneric
+	}
+	if raw, ok := root["servers"]; ok {
+		return raw, models.ClientFormatVSCode
+	}
+	// VS Code 的 settings.json 中服务器配置位于 mcp.servers 下
+	if raw, ok := root["mcp"]; ok {
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &nested); err == nil {
+			if servers, ok := nested["servers"]; ok {
+				return servers, models.ClientFormatVSCode
+			}
+		}
+	}
+	return nil, ""
+}
+
+// toCreateRequest 将客户端配置转换为服务器创建请求
+func (e clientServerEntry) toCreateRequest(name string) *models.MCPServerCreateRequest {
+	return &models.MCPServerCreateRequest{
+		Name:      name,
+		URL:       e.URL,
+		Transport: e.transport(),
+		Command:   e.Command,
+		Args:      e.Args,
+		Env:       e.Env,
+		Headers:   e.Headers,
+		AuthType:  "none",
+	}
+}
+
+// transport 推断传输方式，未声明类型的URL以 /sse 结尾时视为SSE
+func (e clientServerEntry) transport() string {
+	switch strings.ToLower(e.Type) {
+	case "stdio":
+		return "stdio"
+	case "sse":
+		return "sse"
+	case "http", "streamable-http", "streamable_http", "streamablehttp":
+		return "streamable_http"
+	}
+
+	if e.Command != "" {
+		return "stdio"
+	}
+	if strings.HasSuffix(strings.TrimRight(e.URL, "/"), "/sse") {
+		return "sse"
+	}
+	return "streamable_http"
+}
+
+// defaultClientConfigPath 获取各客户端在本机的默认配置文件路径
+func defaultClientConfigPath(format string) (string, error) {
+	switch format {
+	case models.ClientFormatClaudeDesktop:
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("获取用户配置目录失败: %v", err)
+		}
+		return filepath.Join(dir, "Claude", "claude_desktop_config.json"), nil
+	case models.ClientFormatCursor:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("获取用户主目录失败: %v", err)
+		}
+		return filepath.Join(home, ".cursor", "mcp.json"), nil
+	case models.ClientFormatVSCode:
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("获取用户配置目录失败: %v", err)
+		}
+		return filepath.Join(dir, "Code", "User", "mcp.json"), nil
+	default:
+		return "", validationError("no_default_config_path", "格式 %s 没有默认配置文件路径", format)
+	}
+}
+
+// expandHome 展开路径开头的 ~
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// stringifySlice 将任意值的数组转换为字符串数组
+func stringifySlice(values []interface{}) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		result = append(result, stringifyValue(v))
+	}
+	return result
+}
+
+// stringifyMap 将任意值的对象转换为字符串映射
+func stringifyMap(values map[string]interface{}) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = stringifyValue(v)
+	}
+	return result
+}
+
+// stringifyValue 将JSON值转换为字符串
+func stringifyValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case nil:
+		return ""
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
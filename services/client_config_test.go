@@ -0,0 +1,94 @@
+package services
+
+import (
+	"testing"
+
+	"desktop-ai-tools/models"
+)
+
+// TestParseClientConfig 测试解析各客户端的配置文件格式
+func TestParseClientConfig(t *testing.T) {
+	claude := []byte(`{
+  "mcpServers": {
+    "filesystem": {
+      "command": "npx",
+      "args": ["-y", "@modelcontextprotocol/server-filesystem", "/tmp"],
+      "env": {"DEBUG": "1", "PORT": 8080}
+    },
+    "remote": {"url": "https://example.com/mcp/sse", "headers": {"Authorization": "Bearer x"}}
+  }
+}`)
+
+	config, err := parseClientConfig("auto", claude)
+	if err != nil {
+		t.Fatalf("解析mcpServers配置失败: %v", err)
+	}
+	if config.Format != models.ClientFormatGeneric || len(config.Names) != 2 {
+		t.Fatalf("解析结果错误: %+v", config)
+	}
+
+	fs := config.Servers["filesystem"].toCreateRequest("filesystem")
+	if fs.Transport != "stdio" || fs.Command != "npx" || len(fs.Args) != 3 || fs.Env["PORT"] != "8080" {
+		t.Fatalf("stdio服务器转换错误: %+v", fs)
+	}
+	remote := config.Servers["remote"].toCreateRequest("remote")
+	if remote.Transport != "sse" || remote.Headers["Authorization"] != "Bearer x" {
+		t.Fatalf("SSE服务器转换错误: %+v", remote)
+	}
+
+	// VS Code 的 settings.json 允许注释，服务器位于 mcp.servers 下
+	vscode := []byte(`{
+  // 编辑器设置
+  "editor.fontSize": 14,
+  "mcp": {
+    "servers": {
+      "github": {"type": "http", "url": "https://api.example.com/mcp",},
+    }
+  }
+}`)
+	config, err = parseClientConfig("", vscode)
+	if err != nil {
+		t.Fatalf("解析VS Code配置失败: %v", err)
+	}
+	if config.Format != models.ClientFormatVSCode {
+		t.Fatalf("格式识别错误: %s", config.Format)
+	}
+	if got := config.Servers["github"].transport(); got != "streamable_http" {
+		t.Fatalf("传输方式识别错误: %s", got)
+	}
+
+	if _, err := parseClientConfig("auto", []byte(`{"foo": {}}`)); err == nil {
+		t.Fatal("缺少服务器配置时应返回错误")
+	}
+}
+
+// TestUniqueServerName 测试重命名时生成不冲突的名称
+func TestUniqueServerName(t *testing.T) {
+	taken := map[string]bool{"fs": true, "fs (2)": true}
+	if got := uniqueServerName("fs", taken); got != "fs (3)" {
+		t.Fatalf("生成的名称错误: %s", got)
+	}
+}
+
+// TestSecretExporter 测试导出时的敏感值处理
+func TestSecretExporter(t *testing.T) {
+	values := map[string]string{
+		"Authorization": "Bearer abc",
+		"X-Region":      "cn",
+	}
+
+	redacted := (&secretExporter{mode: models.ExportSecretsRedact}).mapValues("github", values, false)
+	if redacted["Authorization"] != "Bearer "+models.SecretMask || redacted["X-Region"] != "cn" {
+		t.Fatalf("脱敏结果错误: %v", redacted)
+	}
+
+	exporter := &secretExporter{mode: models.ExportSecretsPlaceholder, placeholders: make(map[string]bool)}
+	replaced := exporter.mapValues("github", values, false)
+	if replaced["Authorization"] != "Bearer ${env:GITHUB_AUTHORIZATION}" {
+		t.Fatalf("占位符替换错误: %v", replaced)
+	}
+	env := exporter.mapValues("github", map[string]string{"GITHUB_TOKEN": "ghp_x"}, true)
+	if env["GITHUB_TOKEN"] != "${env:GITHUB_TOKEN}" || !exporter.placeholders["GITHUB_TOKEN"] {
+		t.Fatalf("环境变量占位符错误: %v", env)
+	}
+}
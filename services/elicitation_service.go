@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/events"
+	"desktop-ai-tools/models"
+)
+
+// DefaultElicitationTimeout 信息收集请求的默认超时时间，超时后自动拒绝
+const DefaultElicitationTimeout = 2 * time.Minute
+
+// pendingElicitation 等待用户响应的信息收集请求
+type pendingElicitation struct {
+	info     models.ElicitationPendingRequest
+	response chan *mcp.ElicitationResult
+}
+
+// ElicitationService 处理MCP服务器发起的信息收集（elicitation）请求
+type ElicitationService struct {
+	db       *gorm.DB
+	timeout  time.Duration
+	mu       sync.Mutex
+	pending  map[string]*pendingElicitation
+	notifier EventNotifier
+}
+
+// NewElicitationService 创建信息收集服务实例
+func NewElicitationService(db *gorm.DB) *ElicitationService {
+	return &ElicitationService{
+		db:      db,
+		timeout: DefaultElicitationTimeout,
+		pending: make(map[string]*pendingElicitation),
+	}
+}
+
+// SetNotifier 设置事件推送回调，用于把请求发送到桌面端界面
+func (s *ElicitationService) SetNotifier(notifier EventNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = notifier
+}
+
+// SetTimeout 设置等待用户响应的超时时间
+func (s *ElicitationService) SetTimeout(timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timeout > 0 {
+		s.timeout = timeout
+	}
+}
+
+// HandlerFor 创建绑定到指定服务器的信息收集处理器
+func (s *ElicitationService) HandlerFor(serverID uint) *ElicitationHandler {
+	return &ElicitationHandler{service: s, serverID: serverID}
+}
+
+// GetPending 获取所有等待用户响应的请求
+func (s *ElicitationService) GetPending() []models.ElicitationPendingRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]models.ElicitationPendingRequest, 0, len(s.pending))
+	for _, p := range s.pending {
+		result = append(result, p.info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Respond 提交用户对信息收集请求的响应
+// 查找、校验和移除在同一次加锁中完成，同一请求只会被响应一次；校验失败时请求保留，用户可以重新提交
+func (s *ElicitationService) Respond(id string, req *models.ElicitationRespondRequest) error {
+	action := mcp.ElicitationResponseAction(req.Action)
+	result := &mcp.ElicitationResult{
+		ElicitationResponse: mcp.ElicitationResponse{Action: action},
+	}
+
+	s.mu.Lock()
+	p, ok := s.pending[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrElicitationNotFound
+	}
+	if action == mcp.ElicitationResponseActionAccept {
+		if err := validateElicitationContent(p.info.RequestedSchema, req.Content); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		result.Content = req.Content
+	}
+	delete(s.pending, id)
+	s.mu.Unlock()
+
+	// 等待方已超时或取消时丢弃响应，不阻塞调用方
+	select {
+	case p.response <- result:
+	default:
+	}
+	return nil
+}
+
+// elicit 将请求推送到界面并等待用户响应，超时自动拒绝
+func (s *ElicitationService) elicit(ctx context.Context, serverID uint, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	var server models.MCPServer
+	if err := s.db.First(&server, serverID).Error; err != nil {
+		return nil, ErrServerNotFound
+	}
+
+	s.mu.Lock()
+	timeout := s.timeout
+	notifier := s.notifier
+	s.mu.Unlock()
+
+	now := time.Now()
+	p := &pendingElicitation{
+		info: models.ElicitationPendingRequest{
+			ID:              newRequestID(),
+			ServerID:        serverID,
+			ServerName:      server.Name,
+			Message:         request.Params.Message,
+			RequestedSchema: request.Params.RequestedSchema,
+			CreatedAt:       now,
+			ExpiresAt:       now.Add(timeout),
+		},
+		response: make(chan *mcp.ElicitationResult, 1),
+	}
+
+	s.mu.Lock()
+	s.pending[p.info.ID] = p
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, p.info.ID)
+		s.mu.Unlock()
+	}()
+
+	if notifier != nil {
+		notifier(events.TopicElicitationRequested, p.info)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-p.response:
+		return result, nil
+	case <-timer.C:
+		mcpLog.Warn("信息收集请求等待超时，已自动拒绝", "server", server.Name)
+		if notifier != nil {
+			notifier(events.TopicElicitationExpired, p.info.ID)
+		}
+		return &mcp.ElicitationResult{
+			ElicitationResponse: mcp.ElicitationResponse{
+				Action: mcp.ElicitationResponseActionDecline,
+			},
+		}, nil
+	case <-ctx.Done():
+		if notifier != nil {
+			notifier(events.TopicElicitationExpired, p.info.ID)
+		}
+		return nil, fmt.Errorf("信息收集请求已取消: %v", ctx.Err())
+	}
+}
+
+// validateElicitationContent 按请求的Schema校验必填字段
+func validateElicitationContent(schema interface{}, content map[string]interface{}) error {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+
+	var parsed struct {
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil
+	}
+
+	for _, field := range parsed.Required {
+		if _, ok := content[field]; !ok {
+			return validationError("elicitation_field_required", "缺少必填字段: %s", field)
+		}
+	}
+	return nil
+}
+
+// ElicitationHandler 绑定到单个服务器的信息收集处理器，实现 client.ElicitationHandler 接口
+type ElicitationHandler struct {
+	service  *ElicitationService
+	serverID uint
+}
+
+// Elicit 处理服务器发起的 elicitation/create 请求
+func (h *ElicitationHandler) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	return h.service.elicit(ctx, h.serverID, request)
+}
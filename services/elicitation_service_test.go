@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/events"
+	"desktop-ai-tools/models"
+)
+
+// elicitationResult 后台信息收集请求的返回结果
+type elicitationResult struct {
+	result *mcp.ElicitationResult
+	err    error
+}
+
+// startElicitation 在后台发起信息收集请求，返回请求ID和结果通道
+func startElicitation(t *testing.T, db *gorm.DB, service *ElicitationService) (string, <-chan elicitationResult) {
+	t.Helper()
+
+	server := models.MCPServer{Name: "form", URL: "https://example.com/mcp"}
+	if err := db.Create(&server).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+
+	requested := make(chan string, 1)
+	service.SetNotifier(func(topic events.Topic, data interface{}) {
+		if topic == events.TopicElicitationRequested {
+			requested <- data.(models.ElicitationPendingRequest).ID
+		}
+	})
+
+	request := mcp.ElicitationRequest{}
+	request.Params.Message = "请输入名称"
+	request.Params.RequestedSchema = map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+		"required":   []string{"name"},
+	}
+
+	done := make(chan elicitationResult, 1)
+	go func() {
+		result, err := service.HandlerFor(server.ID).Elicit(context.Background(), request)
+		done <- elicitationResult{result, err}
+	}()
+
+	select {
+	case id := <-requested:
+		return id, done
+	case <-time.After(time.Second):
+		t.Fatal("未收到信息收集请求事件")
+		return "", nil
+	}
+}
+
+// TestElicitationRespond 测试按请求的Schema校验必填字段，校验失败时请求保留，响应后不能重复响应
+func TestElicitationRespond(t *testing.T) {
+	db := newTestDB(t)
+	service := NewElicitationService(db)
+	id, done := startElicitation(t, db, service)
+
+	err := service.Respond(id, &models.ElicitationRespondRequest{Action: "accept", Content: map[string]interface{}{}})
+	if apperrors.From(err).Code != "elicitation_field_required" {
+		t.Fatalf("缺少必填字段时应返回校验错误，实际: %v", err)
+	}
+	if len(service.GetPending()) != 1 {
+		t.Fatal("校验失败后请求应继续等待响应")
+	}
+
+	content := map[string]interface{}{"name": "demo"}
+	if err := service.Respond(id, &models.ElicitationRespondRequest{Action: "accept", Content: content}); err != nil {
+		t.Fatalf("提交响应失败: %v", err)
+	}
+	got := <-done
+	if got.err != nil || got.result.Action != mcp.ElicitationResponseActionAccept || got.result.Content.(map[string]interface{})["name"] != "demo" {
+		t.Fatalf("服务器应收到用户填写的内容: %+v %v", got.result, got.err)
+	}
+
+	if err := service.Respond(id, &models.ElicitationRespondRequest{Action: "decline"}); !errors.Is(err, ErrElicitationNotFound) {
+		t.Fatalf("重复响应应返回请求不存在，实际: %v", err)
+	}
+}
+
+// TestElicitationTimeout 测试超时后自动拒绝，之后提交的响应返回请求不存在且不会阻塞
+func TestElicitationTimeout(t *testing.T) {
+	db := newTestDB(t)
+	service := NewElicitationService(db)
+	service.SetTimeout(50 * time.Millisecond)
+	id, done := startElicitation(t, db, service)
+
+	got := <-done
+	if got.err != nil || got.result.Action != mcp.ElicitationResponseActionDecline {
+		t.Fatalf("超时后应自动拒绝: %+v %v", got.result, got.err)
+	}
+	if len(service.GetPending()) != 0 {
+		t.Fatal("超时后请求应被移除")
+	}
+
+	responded := make(chan error, 1)
+	go func() {
+		responded <- service.Respond(id, &models.ElicitationRespondRequest{Action: "decline"})
+	}()
+	select {
+	case err := <-responded:
+		if !errors.Is(err, ErrElicitationNotFound) {
+			t.Fatalf("超时后响应应返回请求不存在，实际: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("超时后提交响应不应阻塞")
+	}
+}
+
+// TestElicitationConcurrentRespond 测试同一请求同时收到多个响应时只有一个生效，其余立即返回
+func TestElicitationConcurrentRespond(t *testing.T) {
+	db := newTestDB(t)
+	service := NewElicitationService(db)
+	id, done := startElicitation(t, db, service)
+
+	const responders = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, responders)
+	for i := 0; i < responders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- service.Respond(id, &models.ElicitationRespondRequest{Action: "decline"})
+		}()
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("并发响应不应阻塞")
+	}
+	close(errs)
+
+	accepted := 0
+	for err := range errs {
+		if err == nil {
+			accepted++
+		} else if !errors.Is(err, ErrElicitationNotFound) {
+			t.Fatalf("其余响应应返回请求不存在，实际: %v", err)
+		}
+	}
+	if accepted != 1 {
+		t.Fatalf("应只有1个响应生效，实际 %d 个", accepted)
+	}
+	if got := <-done; got.err != nil || got.result.Action != mcp.ElicitationResponseActionDecline {
+		t.Fatalf("服务器应收到拒绝响应: %+v %v", got.result, got.err)
+	}
+}
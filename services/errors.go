@@ -0,0 +1,38 @@
+package services
+
+import "desktop-ai-tools/apperrors"
+
+// 服务层预定义的业务错误，错误码供前端和外部调用方识别
+var (
+	ErrServerNotFound          = apperrors.NotFound("server_not_found", "服务器不存在")
+	ErrServerNameExists        = apperrors.Conflict("server_name_exists", "服务器名称已存在")
+	ErrServerDisabled          = apperrors.Conflict("server_disabled", "服务器已禁用")
+	ErrServerInactive          = apperrors.Conflict("server_inactive", "服务器未激活，请先连接服务器")
+	ErrToolNotFound            = apperrors.NotFound("tool_not_found", "工具不存在")
+	ErrToolDisabled            = apperrors.Conflict("tool_disabled", "工具已禁用")
+	ErrTagNotFound             = apperrors.NotFound("tag_not_found", "标签不存在")
+	ErrSourceTagNotFound       = apperrors.NotFound("source_tag_not_found", "部分源标签不存在")
+	ErrRootNotFound            = apperrors.NotFound("root_not_found", "根目录不存在")
+	ErrSecretNotFound          = apperrors.NotFound("secret_not_found", "密钥不存在")
+	ErrSecretNameExists        = apperrors.Conflict("secret_name_exists", "密钥名称已存在")
+	ErrWorkspaceNotFound       = apperrors.NotFound("workspace_not_found", "工作区不存在")
+	ErrWorkspaceNameExists     = apperrors.Conflict("workspace_name_exists", "工作区名称已存在")
+	ErrWorkspaceActive         = apperrors.Conflict("workspace_active", "不能删除当前激活的工作区，请先切换到其他工作区")
+	ErrNoActiveWorkspace       = apperrors.NotFound("no_active_workspace", "没有激活的工作区")
+	ErrSamplingRequestNotFound = apperrors.NotFound("sampling_request_not_found", "采样请求不存在或已过期")
+	ErrSamplingTimeout         = apperrors.Timeout("sampling_timeout", "等待用户确认超时")
+	ErrElicitationNotFound     = apperrors.NotFound("elicitation_not_found", "信息收集请求不存在或已过期")
+	ErrClientNotConnected      = apperrors.Upstream("mcp_not_connected", "客户端未连接", nil)
+	ErrMasterKeyNotInitialized = &apperrors.Error{Kind: apperrors.KindInternal, Code: "master_key_not_initialized", Message: "主密钥未初始化"}
+)
+
+// 外部服务调用失败的错误码
+const (
+	codeMCPConnectFailed = "mcp_connect_failed"
+	codeMCPRequestFailed = "mcp_request_failed"
+)
+
+// validationError 创建参数校验错误，code 同时是消息目录中的键
+func validationError(code, format string, args ...interface{}) error {
+	return apperrors.Newf(apperrors.KindValidation, code, format, args...)
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/models"
+	"desktop-ai-tools/utils"
+)
+
+// nonIdentifierPattern 用于生成环境变量名
+var nonIdentifierPattern = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// ExportService 将MCP服务器导出为 Claude Desktop、Cursor、VS Code 等客户端的配置格式
+type ExportService struct {
+	db *gorm.DB
+}
+
+// NewExportService 创建导出服务实例
+func NewExportService(db *gorm.DB) *ExportService {
+	return &ExportService{db: db}
+}
+
+// Export 生成客户端配置
+func (s *ExportService) Export(req *models.MCPServerExportRequest) (*models.MCPServerExportResponse, error) {
+	format := req.Format
+	if format == "" {
+		format = models.ClientFormatGeneric
+	}
+
+	servers, err := s.selectServers(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := &secretExporter{mode: req.Secrets, placeholders: make(map[string]bool)}
+	resp := &models.MCPServerExportResponse{
+		Format: format,
+		Count:  len(servers),
+	}
+
+	entries := make(map[string]clientServerEntry, len(servers))
+	for i := range servers {
+		server := &servers[i]
+		entry := clientServerEntry{
+			Command: server.Command,
+			Args:    server.GetArgs(),
+			Env:     exporter.mapValues(server.Name, server.GetEnv(), true),
+		}
+		if server.Transport != "stdio" {
+			entry.URL = server.URL
+			entry.Headers = exporter.mapValues(server.Name, mergeHeaders(server.GetHeaders(), authHeaders(server)), false)
+		}
+
+		entry, warning := formatClientEntry(format, server, entry)
+		if warning != "" {
+			resp.Warnings = append(resp.Warnings, warning)
+		}
+		entries[server.Name] = entry
+	}
+
+	section := "mcpServers"
+	if format == models.ClientFormatVSCode {
+		section = "servers"
+	}
+	resp.Config = map[string]interface{}{section: entries}
+
+	content, err := utils.ToJSON(resp.Config)
+	if err != nil {
+		return nil, fmt.Errorf("生成配置失败: %v", err)
+	}
+	resp.Content = content
+
+	for name := range exporter.placeholders {
+		resp.Placeholders = append(resp.Placeholders, name)
+	}
+	sort.Strings(resp.Placeholders)
+	return resp, nil
+}
+
+// selectServers 按ID或标签选择要导出的服务器
+func (s *ExportService) selectServers(req *models.MCPServerExportRequest) ([]models.MCPServer, error) {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []models.MCPServer
+	query := s.db.Scopes(inWorkspace(workspaceID)).Preload("Tags").Order("name asc")
+	if req.OnlyEnabled {
+		query = query.Where("is_enabled = ?", true)
+	}
+	if err := query.Find(&servers).Error; err != nil {
+		return nil, fmt.Errorf("查询服务器失败: %v", err)
+	}
+
+	if len(req.IDs) == 0 && len(req.Tags) == 0 {
+		return servers, nil
+	}
+
+	ids := make(map[uint]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		ids[id] = true
+	}
+	tags := make(map[string]bool, len(req.Tags))
+	for _, tag := range req.Tags {
+		tags[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+
+	selected := make([]models.MCPServer, 0, len(servers))
+	for _, server := range servers {
+		if ids[server.ID] || hasAnyTag(server.GetTagList(), tags) {
+			selected = append(selected, server)
+		}
+	}
+	return selected, nil
+}
+
+// formatClientEntry 按客户端格式调整服务器配置
+func formatClientEntry(format string, server *models.MCPServer, entry clientServerEntry) (clientServerEntry, string) {
+	remoteType := "http"
+	if server.Transport == "sse" {
+		remoteType = "sse"
+	}
+
+	switch format {
+	case models.ClientFormatClaudeDesktop:
+		if server.Transport == "stdio" {
+			return entry, ""
+		}
+		// Claude Desktop 的配置文件只支持本地命令，远程服务器通过 mcp-remote 桥接
+		args := []string{"-y", "mcp-remote", entry.URL, "--transport", remoteType + "-only"}
+		for _, key := range sortedKeys(entry.Headers) {
+			args = append(args, "--header", key+":"+entry.Headers[key])
+		}
+		return clientServerEntry{Command: "npx", Args: args},
+			fmt.Sprintf("服务器 %s 为远程服务器，已转换为通过 mcp-remote 连接", server.Name)
+	case models.ClientFormatCursor:
+		// Cursor 根据 command 和 url 自动识别传输方式
+		return entry, ""
+	default:
+		if server.Transport == "stdio" {
+			entry.Type = "stdio"
+		} else {
+			entry.Type = remoteType
+		}
+		return entry, ""
+	}
+}
+
+// authHeaders 根据服务器的认证配置生成HTTP请求头
+func authHeaders(server *models.MCPServer) map[string]string {
+	if server.AuthConfig == "" {
+		return nil
+	}
+	var config map[string]string
+	if err := json.Unmarshal([]byte(server.AuthConfig), &config); err != nil {
+		return nil
+	}
+
+	switch server.AuthType {
+	case "bearer":
+		if config["token"] != "" {
+			return map[string]string{"Authorization": "Bearer " + config["token"]}
+		}
+	case "basic":
+		if config["username"] != "" {
+			credentials := base64.StdEncoding.EncodeToString([]byte(config["username"] + ":" + config["password"]))
+			return map[string]string{"Authorization": "Basic " + credentials}
+		}
+	case "api_key":
+		if config["api_key"] != "" {
+			return map[string]string{"X-API-Key": config["api_key"]}
+		}
+	}
+	return nil
+}
+
+// mergeHeaders 合并请求头，显式配置的请求头优先
+func mergeHeaders(headers, auth map[string]string) map[string]string {
+	if len(auth) == 0 {
+		return headers
+	}
+	merged := make(map[string]string, len(headers)+len(auth))
+	for k, v := range auth {
+		merged[k] = v
+	}
+	for k, v := range headers {
+		merged[k] = v
+	}
+	return merged
+}
+
+// secretExporter 按导出设置处理敏感值
+type secretExporter struct {
+	mode         string
+	placeholders map[string]bool
+}
+
+// mapValues 处理环境变量或请求头中的敏感值
+func (e *secretExporter) mapValues(serverName string, values map[string]string, isEnv bool) map[string]string {
+	if len(values) == 0 || e.mode == "" || e.mode == models.ExportSecretsKeep {
+		return values
+	}
+
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		if !models.IsSecretKey(key) || value == "" {
+			result[key] = value
+			continue
+		}
+
+		// 保留 Bearer、Basic 等认证方案前缀
+		scheme := ""
+		if parts := strings.SplitN(value, " ", 2); len(parts) == 2 && (parts[0] == "Bearer" || parts[0] == "Basic") {
+			scheme = parts[0] + " "
+		}
+
+		if e.mode == models.ExportSecretsRedact {
+			result[key] = scheme + models.SecretMask
+			continue
+		}
+
+		name := envVarName(key)
+		if !isEnv {
+			name = envVarName(serverName + "_" + key)
+		}
+		e.placeholders[name] = true
+		result[key] = scheme + "${env:" + name + "}"
+	}
+	return result
+}
+
+// envVarName 将任意名称转换为环境变量名，例如 "github server_Authorization" -> "GITHUB_SERVER_AUTHORIZATION"
+func envVarName(name string) string {
+	return strings.Trim(strings.ToUpper(nonIdentifierPattern.ReplaceAllString(name, "_")), "_")
+}
+
+// hasAnyTag 判断标签中是否包含任意指定标签（忽略大小写）
+func hasAnyTag(tags []string, wanted map[string]bool) bool {
+	if len(wanted) == 0 {
+		return false
+	}
+	for _, tag := range tags {
+		if wanted[strings.ToLower(tag)] {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys 获取排序后的键列表
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/models"
+)
+
+// ImportService 从 Claude Desktop、Cursor、VS Code 等客户端配置文件导入MCP服务器
+type ImportService struct {
+	db      *gorm.DB
+	servers *MCPServerService
+}
+
+// NewImportService 创建导入服务实例，导入的变更在审计日志中记录为导入来源
+func NewImportService(db *gorm.DB, servers *MCPServerService) *ImportService {
+	return &ImportService{
+		db:      db,
+		servers: servers.WithContext(models.WithAuditSource(context.Background(), models.AuditSourceImport)),
+	}
+}
+
+// DetectSources 列出本机各客户端的默认配置文件
+func (s *ImportService) DetectSources() []models.ClientConfigSource {
+	formats := []string{models.ClientFormatClaudeDesktop, models.ClientFormatCursor, models.ClientFormatVSCode}
+	sources := make([]models.ClientConfigSource, 0, len(formats))
+	for _, format := range formats {
+		path, err := defaultClientConfigPath(format)
+		if err != nil {
+			continue
+		}
+		_, statErr := os.Stat(path)
+		sources = append(sources, models.ClientConfigSource{
+			Format: format,
+			Path:   path,
+			Exists: statErr == nil,
+		})
+	}
+	return sources
+}
+
+// Preview 预览导入结果，不写入数据库
+func (s *ImportService) Preview(req *models.MCPServerImportRequest) (*models.MCPServerImportResponse, error) {
+	return s.run(req, true)
+}
+
+// Import 按预览结果执行导入
+func (s *ImportService) Import(req *models.MCPServerImportRequest) (*models.MCPServerImportResponse, error) {
+	return s.run(req, false)
+}
+
+// run 解析配置并逐个处理服务器，dryRun 为true时只计算处理动作
+func (s *ImportService) run(req *models.MCPServerImportRequest, dryRun bool) (*models.MCPServerImportResponse, error) {
+	data, source, err := s.loadContent(req)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := parseClientConfig(req.Format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	// 当前工作区中已存在的服务器名称
+	var existing []models.MCPServer
+	if err := s.db.Scopes(inWorkspace(workspaceID)).Select("id", "name").Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("查询已有服务器失败: %v", err)
+	}
+	existingIDs := make(map[string]uint, len(existing))
+	taken := make(map[string]bool, len(existing))
+	for _, server := range existing {
+		existingIDs[server.Name] = server.ID
+		taken[server.Name] = true
+	}
+
+	selected := make(map[string]bool, len(req.Names))
+	for _, name := range req.Names {
+		selected[name] = true
+	}
+
+	resp := &models.MCPServerImportResponse{
+		Format: config.Format,
+		Source: source,
+		DryRun: dryRun,
+		Items:  make([]models.MCPServerImportItem, 0, len(config.Names)),
+	}
+
+	for _, name := range config.Names {
+		if len(selected) > 0 && !selected[name] {
+			continue
+		}
+
+		createReq := config.Servers[name].toCreateRequest(name)
+		createReq.Description = fmt.Sprintf("从 %s 配置导入", config.Format)
+		createReq.Tags = req.Tags
+
+		item := models.MCPServerImportItem{
+			Name:       name,
+			TargetName: name,
+			Action:     models.ImportActionCreate,
+			Transport:  createReq.Transport,
+			URL:        createReq.URL,
+			Command:    createReq.Command,
+		}
+
+		if taken[name] {
+			item.ExistingID = existingIDs[name]
+			switch s.conflictStrategy(req, name) {
+			case models.ImportConflictRename:
+				item.Action = models.ImportActionRename
+				item.TargetName = uniqueServerName(name, taken)
+				createReq.Name = item.TargetName
+			case models.ImportConflictOverwrite:
+				item.Action = models.ImportActionOverwrite
+			default:
+				item.Action = models.ImportActionSkip
+			}
+		}
+
+		if item.Action != models.ImportActionSkip {
+			if err := s.servers.Validate(createReq); err != nil {
+				item.Action = models.ImportActionInvalid
+				item.Error = err.Error()
+			}
+		}
+
+		switch item.Action {
+		case models.ImportActionSkip:
+			resp.Skipped++
+		case models.ImportActionInvalid:
+			resp.Failed++
+		default:
+			taken[item.TargetName] = true
+			if !dryRun {
+				if err := s.apply(&item, createReq); err != nil {
+					item.Error = err.Error()
+					resp.Failed++
+					break
+				}
+			}
+			if item.Action == models.ImportActionOverwrite {
+				resp.Overwritten++
+			} else {
+				resp.Created++
+			}
+		}
+
+		resp.Items = append(resp.Items, item)
+	}
+
+	return resp, nil
+}
+
+// apply 创建新服务器或覆盖同名服务器
+func (s *ImportService) apply(item *models.MCPServerImportItem, createReq *models.MCPServerCreateRequest) error {
+	if item.Action != models.ImportActionOverwrite {
+		server, err := s.servers.Create(createReq)
+		if err != nil {
+			return err
+		}
+		item.ServerID = server.ID
+		return nil
+	}
+
+	// 覆盖连接配置，保留原有的描述、标签和启用状态
+	current, err := s.servers.GetByID(item.ExistingID)
+	if err != nil {
+		return err
+	}
+	tags := createReq.Tags
+	if len(tags) == 0 {
+		tags = current.GetTagList()
+	}
+	server, err := s.servers.Update(item.ExistingID, &models.MCPServerUpdateRequest{
+		Name:        current.Name,
+		Description: current.Description,
+		URL:         createReq.URL,
+		Transport:   createReq.Transport,
+		Command:     &createReq.Command,
+		Args:        &createReq.Args,
+		Env:         &createReq.Env,
+		Headers:     &createReq.Headers,
+		AuthType:    createReq.AuthType,
+		Tags:        tags,
+	})
+	if err != nil {
+		return err
+	}
+	item.ServerID = server.ID
+	return nil
+}
+
+// loadContent 获取配置内容，优先使用请求中的内容，其次是指定路径和默认路径
+func (s *ImportService) loadContent(req *models.MCPServerImportRequest) ([]byte, string, error) {
+	if req.Content != "" {
+		return []byte(req.Content), "request", nil
+	}
+
+	path := expandHome(req.Path)
+	if path == "" {
+		if req.Format == "" || req.Format == "auto" {
+			return nil, "", validationError("import_source_required", "请提供配置内容、文件路径或指定配置格式")
+		}
+		defaultPath, err := defaultClientConfigPath(req.Format)
+		if err != nil {
+			return nil, "", err
+		}
+		path = defaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", validationError("client_config_unreadable", "读取配置文件失败: %v", err)
+	}
+	return data, path, nil
+}
+
+// conflictStrategy 获取指定服务器的冲突处理方式
+func (s *ImportService) conflictStrategy(req *models.MCPServerImportRequest, name string) string {
+	if strategy, ok := req.Overrides[name]; ok {
+		return strategy
+	}
+	if req.Conflict != "" {
+		return req.Conflict
+	}
+	return models.ImportConflictSkip
+}
+
+// uniqueServerName 生成不与已有名称冲突的新名称，例如 "filesystem (2)"
+func uniqueServerName(name string, taken map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)", name, i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
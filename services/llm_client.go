@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ChatMessage OpenAI兼容接口的对话消息
+type ChatMessage struct {
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"` // 字符串或多模态内容数组
+}
+
+// ChatCompletionRequest OpenAI兼容接口的对话补全请求
+type ChatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	Stop        []string      `json:"stop,omitempty"`
+}
+
+// ChatCompletionResponse OpenAI兼容接口的对话补全响应
+type ChatCompletionResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// LLMClient OpenAI兼容接口客户端
+type LLMClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewLLMClient 创建OpenAI兼容接口客户端
+func NewLLMClient(baseURL, apiKey string) *LLMClient {
+	return &LLMClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+// CreateChatCompletion 调用 /chat/completions 接口
+func (c *LLMClient) CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %v", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求LLM接口失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取LLM响应失败: %v", err)
+	}
+
+	var result ChatCompletionResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("解析LLM响应失败 (HTTP %d): %v", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if result.Error != nil && result.Error.Message != "" {
+			return nil, fmt.Errorf("LLM接口返回错误 (HTTP %d): %s", resp.StatusCode, result.Error.Message)
+		}
+		return nil, fmt.Errorf("LLM接口返回错误 (HTTP %d)", resp.StatusCode)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("LLM接口未返回任何结果")
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,9 @@
+package services
+
+import "desktop-ai-tools/logging"
+
+// 服务层各子系统的日志记录器
+var (
+	mcpLog    = logging.For(logging.SubsystemMCP)
+	backupLog = logging.For(logging.SubsystemBackup)
+)
@@ -4,38 +4,176 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
+
 	"desktop-ai-tools/models"
 )
 
 // MCPClient MCP客户端结构体
 type MCPClient struct {
-	client *client.Client
-	url    string
+	client          *client.Client
+	url             string
+	transport       string
+	command         string
+	args            []string
+	env             map[string]string
+	headers         map[string]string
+	samplingHandler client.SamplingHandler
+	elicitHandler   client.ElicitationHandler
+	rootsProvider   func(ctx context.Context) (*mcp.ListRootsResult, error)
+	rootsEnabled    bool
+	onNotification  func(mcp.JSONRPCNotification)
+	logLevel        string
+	onClose         func(*MCPClient)
+	recordTraffic   func(models.MCPTrafficEntry)
+	cmd             *exec.Cmd // stdio传输方式启动的子进程
+	mu              sync.RWMutex
+}
+
+// processExitTimeout 关闭stdio连接后等待子进程退出的时间，超时后强制结束子进程
+const processExitTimeout = 5 * time.Second
+
+// MCPClientOption MCP客户端配置项
+type MCPClientOption func(*MCPClient)
+
+// WithTransport 设置传输方式（sse 或 streamable_http）
+func WithTransport(transportType string) MCPClientOption {
+	return func(c *MCPClient) {
+		c.transport = transportType
+	}
+}
+
+// WithCommand 设置stdio传输方式的启动命令、参数和环境变量
+func WithCommand(command string, args []string, env map[string]string) MCPClientOption {
+	return func(c *MCPClient) {
+		c.command = command
+		c.args = args
+		c.env = env
+	}
+}
+
+// WithHeaders 设置HTTP传输方式的请求头
+func WithHeaders(headers map[string]string) MCPClientOption {
+	return func(c *MCPClient) {
+		c.headers = headers
+	}
+}
+
+// WithSamplingHandler 设置采样请求处理器
+func WithSamplingHandler(handler client.SamplingHandler) MCPClientOption {
+	return func(c *MCPClient) {
+		c.samplingHandler = handler
+	}
+}
+
+// WithElicitationHandler 设置信息收集请求处理器
+func WithElicitationHandler(handler client.ElicitationHandler) MCPClientOption {
+	return func(c *MCPClient) {
+		c.elicitHandler = handler
+	}
+}
+
+// WithRootsProvider 设置 roots/list 请求的数据来源
+func WithRootsProvider(provider func(ctx context.Context) (*mcp.ListRootsResult, error)) MCPClientOption {
+	return func(c *MCPClient) {
+		c.rootsProvider = provider
+	}
+}
+
+// WithNotificationHandler 设置服务器通知的处理回调
+func WithNotificationHandler(handler func(mcp.JSONRPCNotification)) MCPClientOption {
+	return func(c *MCPClient) {
+		c.onNotification = handler
+	}
+}
+
+// WithLogLevel 设置连接后通过 logging/setLevel 请求的日志级别
+func WithLogLevel(level string) MCPClientOption {
+	return func(c *MCPClient) {
+		c.logLevel = level
+	}
+}
+
+// WithOnClose 设置连接关闭时的回调
+func WithOnClose(handler func(*MCPClient)) MCPClientOption {
+	return func(c *MCPClient) {
+		c.onClose = handler
+	}
+}
+
+// WithTrafficRecorder 设置JSON-RPC消息的记录函数，用于流量抓取
+func WithTrafficRecorder(record func(models.MCPTrafficEntry)) MCPClientOption {
+	return func(c *MCPClient) {
+		c.recordTraffic = record
+	}
 }
 
 // NewMCPClient 创建新的MCP客户端
-func NewMCPClient(url string) *MCPClient {
-	return &MCPClient{
-		url: url,
+func NewMCPClient(url string, opts ...MCPClientOption) *MCPClient {
+	c := &MCPClient{
+		url:       url,
+		transport: "sse",
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // Connect 连接到MCP服务器
 func (c *MCPClient) Connect(ctx context.Context) error {
-	// 创建SSE客户端
-	mcpClient, err := client.NewSSEMCPClient(c.url)
+	// 创建传输层
+	mcpTransport, err := c.newTransport()
 	if err != nil {
 		return fmt.Errorf("创建MCP客户端失败: %w", err)
 	}
-	
-	c.client = mcpClient
-	
+
+	// 记录包装在最内层，拦截器处理的请求同样会被记录
+	if c.recordTraffic != nil {
+		mcpTransport = newRecordingTransport(mcpTransport, c.recordTraffic, models.RequestIDFrom(ctx))
+	}
+
+	// 只有支持双向通信的传输层才能接收服务器发起的请求，
+	// 否则声明采样、根目录等能力会导致服务器一直等待响应
+	var clientOptions []client.ClientOption
+	capabilities := mcp.ClientCapabilities{}
+	if bidirectional, ok := mcpTransport.(transport.BidirectionalInterface); ok {
+		if c.samplingHandler != nil {
+			clientOptions = append(clientOptions, client.WithSamplingHandler(c.samplingHandler))
+		}
+		if c.elicitHandler != nil {
+			clientOptions = append(clientOptions, client.WithElicitationHandler(c.elicitHandler))
+		}
+
+		var interceptors []RequestInterceptor
+		if c.rootsProvider != nil {
+			interceptors = append(interceptors, rootsInterceptor(c.rootsProvider))
+			c.rootsEnabled = true
+			capabilities.Roots = &struct {
+				ListChanged bool `json:"listChanged,omitempty"`
+			}{
+				ListChanged: true,
+			}
+		}
+		// 始终包装双向传输层：mcp-go 客户端不会启动 *transport.Stdio，
+		// 包装后由 client.Start 统一启动子进程
+		mcpTransport = newInterceptingTransport(bidirectional, interceptors...)
+	}
+
+	mcpClient := client.NewClient(mcpTransport, clientOptions...)
+	if c.onNotification != nil {
+		mcpClient.OnNotification(c.onNotification)
+	}
+
 	// 启动客户端
-	err = c.client.Start(ctx)
+	err = mcpClient.Start(ctx)
 	if err != nil {
 		return fmt.Errorf("启动MCP客户端失败: %w", err)
 	}
@@ -43,14 +181,8 @@ func (c *MCPClient) Connect(ctx context.Context) error {
 	// 初始化连接
 	initRequest := mcp.InitializeRequest{
 		Params: mcp.InitializeParams{
-			ProtocolVersion: "2024-11-05",
-			Capabilities: mcp.ClientCapabilities{
-				Roots: &struct {
-					ListChanged bool `json:"listChanged,omitempty"`
-				}{
-					ListChanged: true,
-				},
-			},
+			ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+			Capabilities:    capabilities,
 			ClientInfo: mcp.Implementation{
 				Name:    "desktop-ai-tools",
 				Version: "1.0.0",
@@ -58,22 +190,35 @@ func (c *MCPClient) Connect(ctx context.Context) error {
 		},
 	}
 	
-	_, err = c.client.Initialize(ctx, initRequest)
+	_, err = mcpClient.Initialize(ctx, initRequest)
 	if err != nil {
+		mcpClient.Close()
 		return fmt.Errorf("初始化MCP客户端失败: %w", err)
 	}
-	
+
+	c.mu.Lock()
+	c.client = mcpClient
+	c.mu.Unlock()
+
+	// 设置服务器日志级别，失败不影响连接
+	if c.logLevel != "" {
+		if err := c.SetLogLevel(ctx, c.logLevel); err != nil {
+			mcpLog.Warn("设置服务器日志级别失败", "url", c.url, "level", c.logLevel, "error", err)
+		}
+	}
+
 	return nil
 }
 
 // ListTools 获取可用工具列表
 func (c *MCPClient) ListTools(ctx context.Context) ([]models.MCPTool, error) {
-	if c.client == nil {
-		return nil, fmt.Errorf("客户端未连接")
+	mcpClient := c.getClient()
+	if mcpClient == nil {
+		return nil, ErrClientNotConnected
 	}
 	
 	// 获取工具列表
-	toolsResponse, err := c.client.ListTools(ctx, mcp.ListToolsRequest{})
+	toolsResponse, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
 	if err != nil {
 		return nil, fmt.Errorf("获取工具列表失败: %w", err)
 	}
@@ -121,7 +266,7 @@ func (c *MCPClient) ListTools(ctx context.Context) ([]models.MCPTool, error) {
 		// 序列化参数
 		parametersJSON, err := json.Marshal(parameters)
 		if err != nil {
-			log.Printf("序列化参数失败: %v", err)
+			mcpLog.Warn("序列化参数失败", "error", err)
 			parametersJSON = []byte("[]")
 		}
 		
@@ -131,6 +276,14 @@ func (c *MCPClient) ListTools(ctx context.Context) ([]models.MCPTool, error) {
 			Parameters:  string(parametersJSON),
 			IsEnabled:   true, // 默认启用
 		}
+
+		// 记录工具注解，用于判断是否可缓存
+		if tool.Annotations.ReadOnlyHint != nil {
+			mcpTool.ReadOnlyHint = *tool.Annotations.ReadOnlyHint
+		}
+		if tool.Annotations.IdempotentHint != nil {
+			mcpTool.IdempotentHint = *tool.Annotations.IdempotentHint
+		}
 		
 		tools = append(tools, mcpTool)
 	}
@@ -138,10 +291,11 @@ func (c *MCPClient) ListTools(ctx context.Context) ([]models.MCPTool, error) {
 	return tools, nil
 }
 
-// CallTool 调用工具
-func (c *MCPClient) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-	if c.client == nil {
-		return nil, fmt.Errorf("客户端未连接")
+// CallTool 调用工具，progressToken 不为空时请求服务器通过 notifications/progress 报告进度
+func (c *MCPClient) CallTool(ctx context.Context, name string, arguments map[string]interface{}, progressToken string) (*mcp.CallToolResult, error) {
+	mcpClient := c.getClient()
+	if mcpClient == nil {
+		return nil, ErrClientNotConnected
 	}
 	
 	request := mcp.CallToolRequest{
@@ -150,8 +304,11 @@ func (c *MCPClient) CallTool(ctx context.Context, name string, arguments map[str
 			Arguments: arguments,
 		},
 	}
+	if progressToken != "" {
+		request.Params.Meta = &mcp.Meta{ProgressToken: progressToken}
+	}
 	
-	result, err := c.client.CallTool(ctx, request)
+	result, err := mcpClient.CallTool(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("调用工具失败: %w", err)
 	}
@@ -159,13 +316,102 @@ func (c *MCPClient) CallTool(ctx context.Context, name string, arguments map[str
 	return result, nil
 }
 
-// Close 关闭连接
+// SetLogLevel 通过 logging/setLevel 设置服务器发送日志的最低级别
+func (c *MCPClient) SetLogLevel(ctx context.Context, level string) error {
+	mcpClient := c.getClient()
+	if mcpClient == nil {
+		return ErrClientNotConnected
+	}
+	// 服务器未声明日志能力时跳过
+	if mcpClient.GetServerCapabilities().Logging == nil {
+		return nil
+	}
+
+	loggingLevel, err := normalizeLogLevel(level)
+	if err != nil {
+		return err
+	}
+
+	request := mcp.SetLevelRequest{
+		Params: mcp.SetLevelParams{Level: loggingLevel},
+	}
+	return mcpClient.SetLevel(ctx, request)
+}
+
+// NotifyRootsListChanged 通知服务器根目录列表已变更
+func (c *MCPClient) NotifyRootsListChanged(ctx context.Context) error {
+	mcpClient := c.getClient()
+	if mcpClient == nil {
+		return ErrClientNotConnected
+	}
+	// 未声明根目录能力的连接无需通知
+	if !c.rootsEnabled {
+		return nil
+	}
+
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: methodRootsListChanged,
+		},
+	}
+	return mcpClient.GetTransport().SendNotification(ctx, notification)
+}
+
+// startCommand 创建stdio子进程，记录进程以便关闭连接时确保其退出
+func (c *MCPClient) startCommand(ctx context.Context, command string, env []string, args []string) (*exec.Cmd, error) {
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = append(os.Environ(), env...)
+
+	c.mu.Lock()
+	c.cmd = cmd
+	c.mu.Unlock()
+	return cmd, nil
+}
+
+// Close 关闭连接，stdio子进程在关闭输入后未及时退出时强制结束
 func (c *MCPClient) Close() error {
-	// mark3labs/mcp-go的客户端没有Close方法，这里暂时不做处理
-	if c.client != nil {
-		c.client = nil
+	c.mu.Lock()
+	mcpClient := c.client
+	cmd := c.cmd
+	c.client = nil
+	c.mu.Unlock()
+
+	if mcpClient == nil {
+		return nil
 	}
-	return nil
+
+	done := make(chan error, 1)
+	go func() {
+		done <- mcpClient.Close()
+	}()
+
+	var err error
+	if cmd == nil {
+		err = <-done
+	} else {
+		select {
+		case err = <-done:
+		case <-time.After(processExitTimeout):
+			mcpLog.Warn("子进程未及时退出，强制结束", "command", c.command, "timeout", processExitTimeout)
+			if cmd.Process != nil {
+				_ = cmd.Process.Kill()
+			}
+			err = <-done
+		}
+	}
+
+	if c.onClose != nil {
+		c.onClose(c)
+	}
+	return err
+}
+
+// getClient 获取底层的 mcp-go 客户端，未连接时返回nil
+func (c *MCPClient) getClient() *client.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
 }
 
 // getStringValue 从map中获取字符串值的辅助函数
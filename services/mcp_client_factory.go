@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"desktop-ai-tools/events"
+	"desktop-ai-tools/models"
+)
+
+// MCPClientFactory 根据服务器配置创建MCP客户端，统一挂载采样、根目录等客户端能力，
+// 并记录当前处于连接状态的会话
+type MCPClientFactory struct {
+	sampling    *SamplingService
+	elicitation *ElicitationService
+	roots       *RootsService
+	serverLogs  *ServerLogService
+	secrets     *SecretService
+	traffic     *TrafficService
+
+	mu       sync.Mutex
+	sessions map[uint]map[*MCPClient]struct{}
+	notifier EventNotifier
+}
+
+// NewMCPClientFactory 创建MCP客户端工厂
+func NewMCPClientFactory(sampling *SamplingService, elicitation *ElicitationService, roots *RootsService, serverLogs *ServerLogService, secrets *SecretService, traffic *TrafficService) *MCPClientFactory {
+	f := &MCPClientFactory{
+		sampling:    sampling,
+		elicitation: elicitation,
+		roots:       roots,
+		serverLogs:  serverLogs,
+		secrets:     secrets,
+		traffic:     traffic,
+		sessions:    make(map[uint]map[*MCPClient]struct{}),
+	}
+
+	// 根目录变更时通知相关的已连接会话
+	if roots != nil {
+		roots.OnChange(f.notifyRootsChanged)
+	}
+	return f
+}
+
+// Connect 创建并连接到指定服务器的MCP客户端，使用完毕后需调用 Close
+func (f *MCPClientFactory) Connect(ctx context.Context, server *models.MCPServer) (*MCPClient, error) {
+	// 连接时才解析 ${env:NAME} 和 ${secret:name} 占位符
+	if f.secrets != nil {
+		resolved, err := f.secrets.ResolveServer(server)
+		if err != nil {
+			return nil, fmt.Errorf("解析服务器配置失败: %v", err)
+		}
+		server = resolved
+	}
+
+	serverID := server.ID
+	opts := []MCPClientOption{
+		WithTransport(server.Transport),
+		WithCommand(server.Command, server.GetArgs(), server.GetEnv()),
+		WithHeaders(mergeHeaders(server.GetHeaders(), authHeaders(server))),
+		WithOnClose(func(c *MCPClient) {
+			f.unregister(serverID, c)
+		}),
+	}
+	if f.sampling != nil {
+		opts = append(opts, WithSamplingHandler(f.sampling.HandlerFor(serverID)))
+	}
+	if f.elicitation != nil {
+		opts = append(opts, WithElicitationHandler(f.elicitation.HandlerFor(serverID)))
+	}
+	if f.roots != nil {
+		opts = append(opts, WithRootsProvider(func(ctx context.Context) (*mcp.ListRootsResult, error) {
+			return f.roots.ListRoots(serverID)
+		}))
+	}
+
+	opts = append(opts, WithNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		if f.serverLogs != nil {
+			f.serverLogs.HandleNotification(serverID, notification)
+		}
+		f.handleProgress(serverID, notification)
+	}))
+	if f.serverLogs != nil {
+		opts = append(opts, WithLogLevel(server.LogLevel))
+	}
+	if f.traffic != nil && f.traffic.Capturing(serverID) {
+		opts = append(opts, WithTrafficRecorder(f.traffic.RecorderFor(serverID)))
+	}
+
+	mcpClient := NewMCPClient(server.URL, opts...)
+	if err := mcpClient.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	f.register(serverID, mcpClient)
+	return mcpClient, nil
+}
+
+// SetNotifier 设置事件发布回调，服务器报告的工具调用进度通过事件发布
+func (f *MCPClientFactory) SetNotifier(notifier EventNotifier) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.notifier = notifier
+}
+
+// handleProgress 将服务器发送的 notifications/progress 通知发布为工具调用进度事件
+// 进度令牌即工具调用ID，订阅者据此关联到 tool_call.started 事件
+func (f *MCPClientFactory) handleProgress(serverID uint, notification mcp.JSONRPCNotification) {
+	if notification.Method != methodNotificationProgress {
+		return
+	}
+	f.mu.Lock()
+	notifier := f.notifier
+	f.mu.Unlock()
+	if notifier == nil {
+		return
+	}
+
+	fields := notification.Params.AdditionalFields
+	progress, _ := fields["progress"].(float64)
+	total, _ := fields["total"].(float64)
+	message, _ := fields["message"].(string)
+	notifier(events.TopicToolCallProgress, events.ToolCallProgress{
+		CallID:   fmt.Sprint(fields["progressToken"]),
+		ServerID: serverID,
+		Progress: progress,
+		Total:    total,
+		Message:  message,
+	})
+}
+
+// Sessions 获取指定服务器当前的已连接会话
+func (f *MCPClientFactory) Sessions(serverID uint) []*MCPClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	clients := make([]*MCPClient, 0, len(f.sessions[serverID]))
+	for c := range f.sessions[serverID] {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// SetLogLevel 为指定服务器的所有已连接会话设置日志级别
+func (f *MCPClientFactory) SetLogLevel(serverID uint, level string) {
+	for _, c := range f.Sessions(serverID) {
+		if err := c.SetLogLevel(context.Background(), level); err != nil {
+			mcpLog.Warn("设置服务器日志级别失败", "server_id", serverID, "level", level, "error", err)
+		}
+	}
+}
+
+// CloseAll 关闭所有已连接的会话，stdio服务器的子进程随之结束，应用退出时调用
+func (f *MCPClientFactory) CloseAll() {
+	f.mu.Lock()
+	targets := make(map[*MCPClient]uint)
+	for id, clients := range f.sessions {
+		for c := range clients {
+			targets[c] = id
+		}
+	}
+	f.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for c, id := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Close(); err != nil {
+				mcpLog.Warn("关闭服务器会话失败", "server_id", id, "error", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// register 记录已连接的会话
+func (f *MCPClientFactory) register(serverID uint, c *MCPClient) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.sessions[serverID] == nil {
+		f.sessions[serverID] = make(map[*MCPClient]struct{})
+	}
+	f.sessions[serverID][c] = struct{}{}
+}
+
+// unregister 移除已关闭的会话
+func (f *MCPClientFactory) unregister(serverID uint, c *MCPClient) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.sessions[serverID], c)
+	if len(f.sessions[serverID]) == 0 {
+		delete(f.sessions, serverID)
+	}
+}
+
+// notifyRootsChanged 向受影响的会话发送 notifications/roots/list_changed
+func (f *MCPClientFactory) notifyRootsChanged(serverID *uint) {
+	f.mu.Lock()
+	targets := make(map[*MCPClient]uint)
+	for id, clients := range f.sessions {
+		if serverID != nil && *serverID != id {
+			continue
+		}
+		for c := range clients {
+			targets[c] = id
+		}
+	}
+	f.mu.Unlock()
+
+	for c, id := range targets {
+		if err := c.NotifyRootsListChanged(context.Background()); err != nil {
+			mcpLog.Warn("通知服务器根目录变更失败", "server_id", id, "error", err)
+		}
+	}
+}
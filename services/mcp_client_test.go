@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"desktop-ai-tools/models"
+)
+
+// rootsTestServer 最小的 streamable HTTP MCP服务器：通过GET监听流向客户端发送 roots/list 请求，
+// 并记录客户端声明的能力、返回的根目录和收到的通知
+type rootsTestServer struct {
+	capabilities chan mcp.ClientCapabilities
+	roots        chan mcp.ListRootsResult
+	notified     chan string
+	requests     chan int
+	nextID       int
+}
+
+func newRootsTestServer(t *testing.T) (*rootsTestServer, string) {
+	t.Helper()
+
+	s := &rootsTestServer{
+		capabilities: make(chan mcp.ClientCapabilities, 1),
+		roots:        make(chan mcp.ListRootsResult, 4),
+		notified:     make(chan string, 4),
+		requests:     make(chan int, 4),
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, server.URL
+}
+
+// requestRoots 通过已建立的GET监听流向客户端发送一次 roots/list 请求
+func (s *rootsTestServer) requestRoots() {
+	s.nextID++
+	s.requests <- s.nextID
+}
+
+func (s *rootsTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case id := <-s.requests:
+				fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":%d,\"method\":\"roots/list\"}\n\n", id)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	case http.MethodDelete:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var message struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case message.Method == "initialize":
+		var params mcp.InitializeParams
+		_ = json.Unmarshal(message.Params, &params)
+		s.capabilities <- params.Capabilities
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Mcp-Session-Id", "roots-test")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":%q,"capabilities":{},"serverInfo":{"name":"roots-test","version":"1.0.0"}}}`, message.ID, mcp.LATEST_PROTOCOL_VERSION)
+	case message.Method != "":
+		// 通知只需确认收到
+		s.notified <- message.Method
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		// 客户端对 roots/list 请求的响应
+		var result mcp.ListRootsResult
+		_ = json.Unmarshal(message.Result, &result)
+		w.WriteHeader(http.StatusAccepted)
+		s.roots <- result
+	}
+}
+
+// waitFor 在超时时间内从通道读取一个值
+func waitFor[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatalf("等待%s超时", what)
+		var zero T
+		return zero
+	}
+}
+
+// TestConnectProvidesRoots 测试连接 streamable_http 服务器后声明根目录能力，
+// roots/list 返回为该服务器配置的根目录，根目录变更时通知已连接的会话
+func TestConnectProvidesRoots(t *testing.T) {
+	db := newTestDB(t)
+	roots := NewRootsService(db)
+	factory := NewMCPClientFactory(nil, nil, roots, nil, nil, nil)
+	testServer, url := newRootsTestServer(t)
+
+	workspaceID, err := activeWorkspaceID(db)
+	if err != nil {
+		t.Fatalf("获取当前工作区失败: %v", err)
+	}
+	server := models.MCPServer{WorkspaceID: workspaceID, Name: "roots", URL: url, Transport: "streamable_http"}
+	other := models.MCPServer{WorkspaceID: workspaceID, Name: "other", URL: url, Transport: "streamable_http"}
+	if err := db.Create(&[]*models.MCPServer{&server, &other}).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	global := t.TempDir()
+	if _, err := roots.Create(&models.WorkspaceRootCreateRequest{Name: "global", Path: global}); err != nil {
+		t.Fatalf("创建根目录失败: %v", err)
+	}
+	if _, err := roots.Create(&models.WorkspaceRootCreateRequest{ServerID: &other.ID, Name: "other", Path: t.TempDir()}); err != nil {
+		t.Fatalf("创建根目录失败: %v", err)
+	}
+
+	client, err := factory.Connect(context.Background(), &server)
+	if err != nil {
+		t.Fatalf("连接服务器失败: %v", err)
+	}
+	defer client.Close()
+
+	if capabilities := waitFor(t, testServer.capabilities, "初始化请求"); capabilities.Roots == nil || !capabilities.Roots.ListChanged {
+		t.Fatalf("应声明根目录能力: %+v", capabilities)
+	}
+
+	testServer.requestRoots()
+	result := waitFor(t, testServer.roots, "roots/list 响应")
+	want := (&models.WorkspaceRoot{Path: global}).URI()
+	if len(result.Roots) != 1 || result.Roots[0].URI != want || result.Roots[0].Name != "global" {
+		t.Fatalf("应只返回对该服务器生效的根目录 %s，实际: %+v", want, result.Roots)
+	}
+
+	own := t.TempDir()
+	if _, err := roots.Create(&models.WorkspaceRootCreateRequest{ServerID: &server.ID, Name: "own", Path: own}); err != nil {
+		t.Fatalf("创建根目录失败: %v", err)
+	}
+	// 先收到的是初始化完成通知
+	for waitFor(t, testServer.notified, "根目录变更通知") != "notifications/roots/list_changed" {
+	}
+
+	testServer.requestRoots()
+	result = waitFor(t, testServer.roots, "roots/list 响应")
+	if len(result.Roots) != 2 || result.Roots[1].URI != (&models.WorkspaceRoot{Path: own}).URI() {
+		t.Fatalf("变更后应返回新增的根目录，实际: %+v", result.Roots)
+	}
+}
+
+// TestRootsListUnsupportedServers 测试根目录列表返回当前工作区中无法接收根目录的SSE服务器
+func TestRootsListUnsupportedServers(t *testing.T) {
+	db := newTestDB(t)
+	workspaceID, err := activeWorkspaceID(db)
+	if err != nil {
+		t.Fatalf("获取当前工作区失败: %v", err)
+	}
+	servers := []*models.MCPServer{
+		{WorkspaceID: workspaceID, Name: "sse", URL: "https://example.com/sse", Transport: "sse"},
+		{WorkspaceID: workspaceID, Name: "http", URL: "https://example.com/mcp", Transport: "streamable_http"},
+		{WorkspaceID: workspaceID + 1, Name: "elsewhere", URL: "https://example.com/sse", Transport: "sse"},
+	}
+	if err := db.Create(&servers).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+
+	response, err := NewRootsService(db).GetList(&models.WorkspaceRootListRequest{})
+	if err != nil {
+		t.Fatalf("查询根目录失败: %v", err)
+	}
+	if len(response.UnsupportedServers) != 1 || response.UnsupportedServers[0].ID != servers[0].ID {
+		t.Fatalf("应只列出当前工作区的SSE服务器，实际: %+v", response.UnsupportedServers)
+	}
+}
@@ -1,18 +1,23 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strings"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
 
 	"desktop-ai-tools/database"
+	"desktop-ai-tools/events"
 	"desktop-ai-tools/models"
 )
 
 // MCPServerService MCP服务器服务
 type MCPServerService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	notifier EventNotifier
 }
 
 // NewMCPServerService 创建新的MCP服务器服务实例
@@ -22,19 +27,65 @@ func NewMCPServerService() *MCPServerService {
 	}
 }
 
+// WithContext 返回使用指定上下文的服务副本，上下文中的审计来源会记录到审计日志
+func (s *MCPServerService) WithContext(ctx context.Context) *MCPServerService {
+	return &MCPServerService{db: s.db.WithContext(ctx), notifier: s.notifier}
+}
+
+// SetNotifier 设置事件发布回调，服务器状态或启用状态变化时发布事件
+func (s *MCPServerService) SetNotifier(notifier EventNotifier) {
+	s.notifier = notifier
+}
+
+// publishStatus 发布服务器状态变化事件
+func (s *MCPServerService) publishStatus(server *models.MCPServer) {
+	if s.notifier != nil {
+		s.notifier(events.TopicServerStatusChanged, events.ServerStatusChanged{
+			ServerID:  server.ID,
+			Status:    server.Status,
+			IsEnabled: server.IsEnabled,
+		})
+	}
+}
+
+// scoped 获取限定在当前工作区的查询
+func (s *MCPServerService) scoped() (*gorm.DB, uint, error) {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 使用新会话，返回的查询可以安全地重复使用
+	return s.db.Scopes(inWorkspace(workspaceID)).Session(&gorm.Session{}), workspaceID, nil
+}
+
 // GetList 获取MCP服务器列表
 func (s *MCPServerService) GetList(req *models.MCPServerListRequest) (*models.MCPServerListResponse, error) {
 	var servers []models.MCPServer
 	var total int64
 
+	db, _, err := s.scoped()
+	if err != nil {
+		return nil, err
+	}
+
 	// 构建查询
-	query := s.db.Model(&models.MCPServer{})
+	query := db.Model(&models.MCPServer{})
 
 	// 搜索条件
 	if req.Search != "" {
 		searchTerm := "%" + req.Search + "%"
-		query = query.Where("name LIKE ? OR description LIKE ? OR tags LIKE ?", 
-			searchTerm, searchTerm, searchTerm)
+		// 标签按名称精确匹配，避免 "dev" 匹配到 "devops"
+		tagged := s.db.Table("mcp_server_tags").
+			Select("mcp_server_tags.mcp_server_id").
+			Joins("JOIN tags ON tags.id = mcp_server_tags.tag_id").
+			Where("LOWER(tags.name) = LOWER(?)", strings.TrimSpace(req.Search))
+		query = query.Where("name LIKE ? OR description LIKE ? OR mcp_servers.id IN (?)",
+			searchTerm, searchTerm, tagged)
+	}
+
+	// 标签过滤
+	if len(req.Tags) > 0 {
+		query = filterByTags(query, "mcp_servers", req.Tags, req.TagMode)
 	}
 
 	// 状态过滤
@@ -58,10 +109,15 @@ func (s *MCPServerService) GetList(req *models.MCPServerListRequest) (*models.MC
 
 	// 分页
 	offset := (req.Page - 1) * req.Size
-	if err := query.Offset(offset).Limit(req.Size).Find(&servers).Error; err != nil {
+	if err := query.Preload("Tags").Offset(offset).Limit(req.Size).Find(&servers).Error; err != nil {
 		return nil, fmt.Errorf("查询服务器列表失败: %v", err)
 	}
 
+	// 敏感配置只写不读
+	for i := range servers {
+		servers[i].Redact()
+	}
+
 	return &models.MCPServerListResponse{
 		Total:   total,
 		Page:    req.Page,
@@ -72,64 +128,166 @@ func (s *MCPServerService) GetList(req *models.MCPServerListRequest) (*models.MC
 
 // GetByID 根据ID获取MCP服务器
 func (s *MCPServerService) GetByID(id uint) (*models.MCPServer, error) {
+	db, _, err := s.scoped()
+	if err != nil {
+		return nil, err
+	}
+
 	var server models.MCPServer
-	if err := s.db.Preload("Tools").First(&server, id).Error; err != nil {
+	if err := db.Preload("Tools").Preload("Tags").First(&server, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("服务器不存在")
+			return nil, ErrServerNotFound
 		}
 		return nil, fmt.Errorf("查询服务器失败: %v", err)
 	}
+	server.Redact()
 	return &server, nil
 }
 
+// ListStates 获取所有工作区中服务器的连接状态和启用状态，供指标输出
+func (s *MCPServerService) ListStates() ([]models.MCPServer, error) {
+	var servers []models.MCPServer
+	if err := s.db.Select("id", "name", "status", "is_enabled").Order("id").Find(&servers).Error; err != nil {
+		return nil, fmt.Errorf("查询服务器失败: %v", err)
+	}
+	return servers, nil
+}
+
+// Validate 校验服务器配置，创建、更新和导入时共用
+func (s *MCPServerService) Validate(req *models.MCPServerCreateRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return validationError("server_name_required", "服务器名称不能为空")
+	}
+	if utf8.RuneCountInString(req.Name) > 100 {
+		return validationError("server_name_too_long", "服务器名称不能超过%d个字符", 100)
+	}
+	if utf8.RuneCountInString(req.Description) > 500 {
+		return validationError("server_description_too_long", "服务器描述不能超过%d个字符", 500)
+	}
+	if _, err := normalizeTagNames(req.Tags); err != nil {
+		return err
+	}
+
+	switch req.Transport {
+	case "stdio":
+		if strings.TrimSpace(req.Command) == "" {
+			return validationError("server_command_required", "stdio服务器必须配置启动命令")
+		}
+	case "", "sse", "streamable_http":
+		if req.URL == "" {
+			return validationError("server_url_required", "服务器地址不能为空")
+		}
+		// 占位符在连接时才解析，校验时替换为示例值
+		u, err := url.Parse(placeholderPattern.ReplaceAllString(req.URL, "placeholder"))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return validationError("invalid_server_url", "无效的服务器地址: %s", req.URL)
+		}
+	default:
+		return validationError("invalid_transport", "无效的传输方式: %s", req.Transport)
+	}
+
+	switch req.AuthType {
+	case "", "none", "bearer", "basic", "api_key":
+	default:
+		return validationError("invalid_auth_type", "无效的认证方式: %s", req.AuthType)
+	}
+
+	return nil
+}
+
 // Create 创建MCP服务器
 func (s *MCPServerService) Create(req *models.MCPServerCreateRequest) (*models.MCPServer, error) {
-	// 检查名称是否重复
+	if err := s.Validate(req); err != nil {
+		return nil, err
+	}
+
+	db, workspaceID, err := s.scoped()
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查名称是否重复（同一工作区内）
 	var count int64
-	if err := s.db.Model(&models.MCPServer{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
+	if err := db.Model(&models.MCPServer{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
 		return nil, fmt.Errorf("检查名称重复失败: %v", err)
 	}
 	if count > 0 {
-		return nil, fmt.Errorf("服务器名称已存在")
+		return nil, ErrServerNameExists
 	}
 
 	// 创建服务器
 	server := &models.MCPServer{
+		WorkspaceID: workspaceID,
 		Name:        req.Name,
 		Description: req.Description,
 		URL:         req.URL,
+		Transport:   req.Transport,
+		Command:     req.Command,
 		AuthType:    req.AuthType,
-		AuthConfig:  req.AuthConfig,
+		AuthConfig:  models.EncryptedString(req.AuthConfig),
 		Status:      "inactive", // 默认为非活跃状态
 		IsEnabled:   true,       // 默认启用
-		Tags:        req.Tags,
 	}
+	server.SetConnection(req.Args, req.Env, req.Headers)
 
-	if err := s.db.Create(server).Error; err != nil {
-		return nil, fmt.Errorf("创建服务器失败: %v", err)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags").Create(server).Error; err != nil {
+			return fmt.Errorf("创建服务器失败: %v", err)
+		}
+		return replaceTags(tx, server, req.Tags)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	server.Redact()
 	return server, nil
 }
 
 // Update 更新MCP服务器
 func (s *MCPServerService) Update(id uint, req *models.MCPServerUpdateRequest) (*models.MCPServer, error) {
+	db, _, err := s.scoped()
+	if err != nil {
+		return nil, err
+	}
+
 	// 检查服务器是否存在
 	var server models.MCPServer
-	if err := s.db.First(&server, id).Error; err != nil {
+	if err := db.First(&server, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("服务器不存在")
+			return nil, ErrServerNotFound
 		}
 		return nil, fmt.Errorf("查询服务器失败: %v", err)
 	}
 
-	// 检查名称是否重复（排除当前记录）
+	// 未指定传输方式和启动命令时沿用原有配置
+	transport := req.Transport
+	if transport == "" {
+		transport = server.Transport
+	}
+	command := server.Command
+	if req.Command != nil {
+		command = *req.Command
+	}
+	if err := s.Validate(&models.MCPServerCreateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		URL:         req.URL,
+		Transport:   transport,
+		Command:     command,
+		AuthType:    req.AuthType,
+		Tags:        req.Tags,
+	}); err != nil {
+		return nil, err
+	}
+
+	// 检查名称是否重复（同一工作区内，排除当前记录）
 	var count int64
-	if err := s.db.Model(&models.MCPServer{}).Where("name = ? AND id != ?", req.Name, id).Count(&count).Error; err != nil {
+	if err := db.Model(&models.MCPServer{}).Where("name = ? AND id != ?", req.Name, id).Count(&count).Error; err != nil {
 		return nil, fmt.Errorf("检查名称重复失败: %v", err)
 	}
 	if count > 0 {
-		return nil, fmt.Errorf("服务器名称已存在")
+		return nil, ErrServerNameExists
 	}
 
 	// 更新字段
@@ -138,33 +296,67 @@ func (s *MCPServerService) Update(id uint, req *models.MCPServerUpdateRequest) (
 		"description": req.Description,
 		"url":         req.URL,
 		"auth_type":   req.AuthType,
-		"auth_config": req.AuthConfig,
-		"tags":        req.Tags,
+		"auth_config": models.EncryptedString(models.KeepMaskedAuthConfig(req.AuthConfig, string(server.AuthConfig))),
 	}
 
 	if req.IsEnabled != nil {
 		updates["is_enabled"] = *req.IsEnabled
 	}
 
-	if err := s.db.Model(&server).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("更新服务器失败: %v", err)
+	if req.Transport != "" {
+		updates["transport"] = req.Transport
+	}
+
+	// 命令参数、环境变量和请求头只在提交时更新，仍为掩码的敏感值保持不变
+	args, env, headers := server.GetArgs(), server.GetEnv(), server.GetHeaders()
+	if req.Args != nil {
+		args = *req.Args
+	}
+	if req.Env != nil {
+		env = models.KeepMaskedSecrets(*req.Env, env)
+	}
+	if req.Headers != nil {
+		headers = models.KeepMaskedSecrets(*req.Headers, headers)
+	}
+	connection := models.MCPServer{}
+	connection.SetConnection(args, env, headers)
+	updates["command"] = command
+	updates["args"] = connection.Args
+	updates["env"] = connection.Env
+	updates["headers"] = connection.Headers
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&server).Updates(updates).Error; err != nil {
+			return fmt.Errorf("更新服务器失败: %v", err)
+		}
+		return replaceTags(tx, &server, req.Tags)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// 重新查询更新后的数据
-	if err := s.db.First(&server, id).Error; err != nil {
+	server = models.MCPServer{}
+	if err := s.db.Preload("Tags").First(&server, id).Error; err != nil {
 		return nil, fmt.Errorf("查询更新后的服务器失败: %v", err)
 	}
 
+	server.Redact()
 	return &server, nil
 }
 
 // Delete 删除MCP服务器
 func (s *MCPServerService) Delete(id uint) error {
+	db, _, err := s.scoped()
+	if err != nil {
+		return err
+	}
+
 	// 检查服务器是否存在
 	var server models.MCPServer
-	if err := s.db.First(&server, id).Error; err != nil {
+	if err := db.First(&server, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("服务器不存在")
+			return ErrServerNotFound
 		}
 		return fmt.Errorf("查询服务器失败: %v", err)
 	}
@@ -189,65 +381,121 @@ func (s *MCPServerService) UpdateStatus(id uint, status string) error {
 		}
 	}
 	if !isValid {
-		return fmt.Errorf("无效的状态值: %s", status)
+		return validationError("invalid_server_status", "无效的状态值: %s", status)
+	}
+
+	db, _, err := s.scoped()
+	if err != nil {
+		return err
 	}
 
 	// 更新状态
-	result := s.db.Model(&models.MCPServer{}).Where("id = ?", id).Update("status", status)
+	result := db.Model(&models.MCPServer{}).Where("id = ?", id).Update("status", status)
 	if result.Error != nil {
 		return fmt.Errorf("更新状态失败: %v", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("服务器不存在")
+		return ErrServerNotFound
 	}
 
+	if s.notifier != nil {
+		var server models.MCPServer
+		if err := db.Select("id", "status", "is_enabled").First(&server, id).Error; err == nil {
+			s.publishStatus(&server)
+		}
+	}
 	return nil
 }
 
 // ToggleEnabled 切换服务器启用状态
 func (s *MCPServerService) ToggleEnabled(id uint) (*models.MCPServer, error) {
+	db, _, err := s.scoped()
+	if err != nil {
+		return nil, err
+	}
+
 	var server models.MCPServer
-	if err := s.db.First(&server, id).Error; err != nil {
+	if err := db.Preload("Tags").First(&server, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("服务器不存在")
+			return nil, ErrServerNotFound
 		}
 		return nil, fmt.Errorf("查询服务器失败: %v", err)
 	}
 
 	// 切换启用状态
 	newEnabled := !server.IsEnabled
-	if err := s.db.Model(&server).Update("is_enabled", newEnabled).Error; err != nil {
+	ctx := models.WithAuditAction(s.db.Statement.Context, models.AuditActionToggle)
+	if err := s.db.WithContext(ctx).Model(&server).Update("is_enabled", newEnabled).Error; err != nil {
 		return nil, fmt.Errorf("更新启用状态失败: %v", err)
 	}
 
 	server.IsEnabled = newEnabled
+	s.publishStatus(&server)
+	server.Redact()
 	return &server, nil
 }
 
-// GetTags 获取所有标签
+// GetTags 获取当前工作区服务器使用中的标签名称
 func (s *MCPServerService) GetTags() ([]string, error) {
-	var servers []models.MCPServer
-	if err := s.db.Select("tags").Where("tags != ''").Find(&servers).Error; err != nil {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	err = s.db.Table("tags").
+		Distinct("tags.name").
+		Joins("JOIN mcp_server_tags ON mcp_server_tags.tag_id = tags.id").
+		Joins("JOIN mcp_servers ON mcp_servers.id = mcp_server_tags.mcp_server_id AND mcp_servers.deleted_at IS NULL").
+		Where("mcp_servers.workspace_id = ?", workspaceID).
+		Order("tags.name asc").
+		Pluck("tags.name", &result).Error
+	if err != nil {
 		return nil, fmt.Errorf("查询标签失败: %v", err)
 	}
+	return result, nil
+}
+
+// UpdateSamplingPolicy 更新服务器的采样策略
+func (s *MCPServerService) UpdateSamplingPolicy(id uint, policy string) error {
+	db, _, err := s.scoped()
+	if err != nil {
+		return err
+	}
 
-	tagSet := make(map[string]bool)
-	for _, server := range servers {
-		if server.Tags != "" {
-			tags := strings.Split(server.Tags, ",")
-			for _, tag := range tags {
-				tag = strings.TrimSpace(tag)
-				if tag != "" {
-					tagSet[tag] = true
-				}
-			}
+	var server models.MCPServer
+	if err := db.Select("id", "transport").First(&server, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrServerNotFound
 		}
+		return fmt.Errorf("查询服务器失败: %v", err)
+	}
+	// SSE 服务器无法发起采样请求，允许或询问策略不会生效
+	if policy != "" && policy != models.SamplingPolicyDeny && !server.AcceptsServerRequests() {
+		return validationError("sampling_unsupported_transport", "%s 传输方式不支持采样，请改用 streamable_http 或 stdio", server.Transport)
 	}
 
-	var result []string
-	for tag := range tagSet {
-		result = append(result, tag)
+	if err := db.Model(&server).Update("sampling_policy", policy).Error; err != nil {
+		return fmt.Errorf("更新采样策略失败: %v", err)
 	}
 
-	return result, nil
-}
\ No newline at end of file
+	return nil
+}
+
+// UpdateLogLevel 更新服务器的日志级别
+func (s *MCPServerService) UpdateLogLevel(id uint, level string) error {
+	db, _, err := s.scoped()
+	if err != nil {
+		return err
+	}
+
+	result := db.Model(&models.MCPServer{}).Where("id = ?", id).Update("log_level", level)
+	if result.Error != nil {
+		return fmt.Errorf("更新日志级别失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrServerNotFound
+	}
+
+	return nil
+}
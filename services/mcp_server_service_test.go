@@ -0,0 +1,56 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"desktop-ai-tools/models"
+)
+
+// TestUpdateServerKeepsConnection 测试更新服务器时未提交的启动命令、参数、环境变量和请求头保持原值
+func TestUpdateServerKeepsConnection(t *testing.T) {
+	db := newTestDB(t)
+	service := &MCPServerService{db: db}
+	workspaceID, err := activeWorkspaceID(db)
+	if err != nil {
+		t.Fatalf("获取当前工作区失败: %v", err)
+	}
+
+	stdio := models.MCPServer{WorkspaceID: workspaceID, Name: "stdio", Transport: "stdio", Command: "npx"}
+	stdio.SetConnection([]string{"-y", "server"}, map[string]string{"API_TOKEN": "tok"}, nil)
+	remote := models.MCPServer{WorkspaceID: workspaceID, Name: "remote", URL: "https://example.com/mcp", Transport: "streamable_http"}
+	remote.SetConnection(nil, map[string]string{"REGION": "cn"}, map[string]string{"Authorization": "Bearer abc"})
+	if err := db.Create(&[]*models.MCPServer{&stdio, &remote}).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+
+	// 与编辑表单一致，只提交基本信息和认证配置
+	if _, err := service.Update(stdio.ID, &models.MCPServerUpdateRequest{Name: "stdio-renamed", AuthType: "none"}); err != nil {
+		t.Fatalf("更新stdio服务器失败: %v", err)
+	}
+	if _, err := service.Update(remote.ID, &models.MCPServerUpdateRequest{Name: "remote", URL: remote.URL, AuthType: "none"}); err != nil {
+		t.Fatalf("更新HTTP服务器失败: %v", err)
+	}
+
+	var got models.MCPServer
+	db.First(&got, stdio.ID)
+	if got.Name != "stdio-renamed" || got.Command != "npx" || !reflect.DeepEqual(got.GetArgs(), []string{"-y", "server"}) || got.GetEnv()["API_TOKEN"] != "tok" {
+		t.Fatalf("未提交的启动命令、参数和环境变量应保持不变: %+v", got)
+	}
+	got = models.MCPServer{}
+	db.First(&got, remote.ID)
+	if got.GetEnv()["REGION"] != "cn" || got.GetHeaders()["Authorization"] != "Bearer abc" {
+		t.Fatalf("未提交的环境变量和请求头应保持不变: env=%v headers=%v", got.GetEnv(), got.GetHeaders())
+	}
+
+	// 提交空请求头时清空
+	empty := map[string]string{}
+	if _, err := service.Update(remote.ID, &models.MCPServerUpdateRequest{Name: "remote", URL: remote.URL, AuthType: "none", Headers: &empty}); err != nil {
+		t.Fatalf("更新HTTP服务器失败: %v", err)
+	}
+	got = models.MCPServer{}
+	db.First(&got, remote.ID)
+	if len(got.GetHeaders()) != 0 || got.GetEnv()["REGION"] != "cn" {
+		t.Fatalf("提交空请求头后应清空请求头且保留环境变量: env=%v headers=%v", got.GetEnv(), got.GetHeaders())
+	}
+}
@@ -3,10 +3,13 @@ package services
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
+	"sync"
 	"time"
 
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/events"
+	"desktop-ai-tools/metrics"
 	"desktop-ai-tools/models"
 
 	"gorm.io/gorm"
@@ -14,40 +17,163 @@ import (
 
 // MCPToolService MCP工具服务
 type MCPToolService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	cache    *ToolResultCache
+	clients  *MCPClientFactory
+	notifier EventNotifier
+	calls    *callTracker
+}
+
+// callTracker 记录正在执行的工具调用，应用退出时据此提示用户并等待调用结束
+type callTracker struct {
+	mu     sync.Mutex
+	active int
+	idle   chan struct{} // 没有正在执行的调用时关闭
+}
+
+func (t *callTracker) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.active++
+}
+
+func (t *callTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 {
+		close(t.idle)
+	}
+}
+
+func (t *callTracker) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active
+}
+
+// wait 等待所有调用结束，ctx 结束时返回其错误
+func (t *callTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.active == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // NewMCPToolService 创建新的MCP工具服务实例
-func NewMCPToolService(db *gorm.DB) *MCPToolService {
-	return &MCPToolService{db: db}
+func NewMCPToolService(db *gorm.DB, clients *MCPClientFactory) *MCPToolService {
+	return &MCPToolService{
+		db:      db,
+		cache:   NewToolResultCache(DefaultToolResultCacheConfig()),
+		clients: clients,
+		calls:   &callTracker{},
+	}
+}
+
+// WithContext 返回使用指定上下文的服务副本，与原服务共享结果缓存和客户端
+func (s *MCPToolService) WithContext(ctx context.Context) *MCPToolService {
+	clone := *s
+	clone.db = s.db.WithContext(ctx)
+	return &clone
+}
+
+// mcpContext 返回访问MCP服务器使用的上下文，只保留API请求ID用于关联流量记录，
+// 不随HTTP请求取消
+func (s *MCPToolService) mcpContext() context.Context {
+	ctx := context.Background()
+	if s.db != nil && s.db.Statement.Context != nil {
+		ctx = s.db.Statement.Context
+	}
+	return models.WithRequestID(context.Background(), models.RequestIDFrom(ctx))
+}
+
+// SetCacheConfig 更新工具结果缓存的默认有效期和最大条目数
+func (s *MCPToolService) SetCacheConfig(defaultTTL time.Duration, maxEntries int) {
+	config := DefaultToolResultCacheConfig()
+	config.DefaultTTL = defaultTTL
+	config.MaxEntries = maxEntries
+	s.cache.SetConfig(config)
+}
+
+// SetNotifier 设置事件发布回调，工具同步完成和工具调用开始、结束时发布事件
+func (s *MCPToolService) SetNotifier(notifier EventNotifier) {
+	s.notifier = notifier
+}
+
+// publish 发布事件，未设置回调时忽略
+func (s *MCPToolService) publish(topic events.Topic, data interface{}) {
+	if s.notifier != nil {
+		s.notifier(topic, data)
+	}
+}
+
+// ActiveCalls 返回正在执行的工具调用数量
+func (s *MCPToolService) ActiveCalls() int {
+	return s.calls.count()
+}
+
+// WaitCalls 等待正在执行的工具调用结束，ctx 结束时返回其错误
+func (s *MCPToolService) WaitCalls(ctx context.Context) error {
+	return s.calls.wait(ctx)
+}
+
+// workspaceServers 获取限定在当前工作区服务器的查询
+func (s *MCPToolService) workspaceServers() (*gorm.DB, error) {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+	return s.db.Scopes(inWorkspace(workspaceID)).Session(&gorm.Session{}), nil
+}
+
+// workspaceTools 获取限定在当前工作区工具的查询
+func (s *MCPToolService) workspaceTools() (*gorm.DB, error) {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+	return s.db.Scopes(toolsInWorkspace(workspaceID)).Session(&gorm.Session{}), nil
 }
 
 // DiscoverTools 从MCP服务器发现工具
 func (s *MCPToolService) DiscoverTools(serverID uint) (*models.MCPToolDiscoveryResponse, error) {
+	servers, err := s.workspaceServers()
+	if err != nil {
+		return nil, err
+	}
+
 	// 获取服务器信息
 	var server models.MCPServer
-	if err := s.db.First(&server, serverID).Error; err != nil {
-		return &models.MCPToolDiscoveryResponse{
-			Success: false,
-			Message: "服务器不存在",
-		}, err
+	if err := servers.First(&server, serverID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrServerNotFound
+		}
+		return nil, fmt.Errorf("查询服务器失败: %w", err)
 	}
 
 	// 检查服务器状态
 	if server.Status != "active" {
-		return &models.MCPToolDiscoveryResponse{
-			Success: false,
-			Message: "服务器未激活，无法发现工具",
-		}, nil
+		return nil, ErrServerInactive
 	}
 
 	// 连接MCP服务器获取工具列表
-	tools, err := s.fetchToolsFromMCPServer(server.URL, server.AuthType, server.AuthConfig)
+	started := time.Now()
+	tools, err := s.fetchToolsFromMCPServer(&server)
 	if err != nil {
-		return &models.MCPToolDiscoveryResponse{
-			Success: false,
-			Message: fmt.Sprintf("从MCP服务器获取工具列表失败:%s", err.Error()),
-		}, nil
+		return nil, err
 	}
 
 	// 保存或更新工具到数据库
@@ -70,6 +196,8 @@ func (s *MCPToolService) DiscoverTools(serverID uint) (*models.MCPToolDiscoveryR
 			existingTool.Description = tool.Description
 			existingTool.Category = tool.Category
 			existingTool.Parameters = tool.Parameters
+			existingTool.ReadOnlyHint = tool.ReadOnlyHint
+			existingTool.IdempotentHint = tool.IdempotentHint
 			existingTool.UpdatedAt = time.Now()
 
 			if err := s.db.Save(&existingTool).Error; err != nil {
@@ -79,6 +207,13 @@ func (s *MCPToolService) DiscoverTools(serverID uint) (*models.MCPToolDiscoveryR
 		}
 	}
 
+	s.publish(events.TopicToolsSynced, events.ToolsSynced{
+		ServerID:   serverID,
+		ToolsCount: len(savedTools),
+		Mode:       "discover",
+		Duration:   time.Since(started).Milliseconds(),
+	})
+
 	// 构建响应消息
 	message := fmt.Sprintf("成功发现 %d 个工具", len(savedTools))
 	if err != nil {
@@ -93,39 +228,40 @@ func (s *MCPToolService) DiscoverTools(serverID uint) (*models.MCPToolDiscoveryR
 }
 
 // fetchToolsFromMCPServer 从 MCP 服务器获取工具列表，使用 MCP SDK
-func (s *MCPToolService) fetchToolsFromMCPServer(url, authType, authConfig string) ([]models.MCPTool, error) {
-	log.Printf("开始使用 MCP SDK 从服务器获取工具: %s", url)
-	
-	// 创建 MCP 客户端
-	mcpClient := NewMCPClient(url)
+func (s *MCPToolService) fetchToolsFromMCPServer(server *models.MCPServer) (tools []models.MCPTool, err error) {
+	started := time.Now()
+	defer func() {
+		metrics.ObserveDiscovery(server.ID, server.Name, err, time.Since(started))
+	}()
+
+	url := server.URL
+	mcpLog.Debug("开始从服务器获取工具", "server_id", server.ID, "url", url)
 	
 	// 创建上下文
-	ctx := context.Background()
+	ctx := s.mcpContext()
 	
 	// 连接到 MCP 服务器
-	log.Printf("连接到 MCP 服务器: %s", url)
-	err := mcpClient.Connect(ctx)
+	mcpClient, err := s.clients.Connect(ctx, server)
 	if err != nil {
-		log.Printf("连接 MCP 服务器失败: %v", err)
-		return nil, fmt.Errorf("连接 MCP 服务器失败: %w", err)
+		mcpLog.Warn("连接 MCP 服务器失败", "server_id", server.ID, "url", url, "error", err)
+		return nil, apperrors.Upstream(codeMCPConnectFailed, "连接 MCP 服务器失败", err)
 	}
 	
 	// 确保在函数结束时关闭连接
 	defer func() {
 		if closeErr := mcpClient.Close(); closeErr != nil {
-			log.Printf("关闭 MCP 客户端连接时出错: %v", closeErr)
+			mcpLog.Warn("关闭 MCP 客户端连接时出错", "server_id", server.ID, "error", closeErr)
 		}
 	}()
 	
 	// 获取工具列表
-	log.Printf("获取工具列表")
-	tools, err := mcpClient.ListTools(ctx)
+	tools, err = mcpClient.ListTools(ctx)
 	if err != nil {
-		log.Printf("获取工具列表失败: %v", err)
-		return nil, fmt.Errorf("获取工具列表失败: %w", err)
+		mcpLog.Warn("获取工具列表失败", "server_id", server.ID, "error", err)
+		return nil, apperrors.Upstream(codeMCPRequestFailed, "MCP服务器请求失败", err)
 	}
 	
-	log.Printf("成功使用 MCP SDK 获取 %d 个工具", len(tools))
+	mcpLog.Debug("获取工具列表成功", "server_id", server.ID, "tools", len(tools))
 	return tools, nil
 }
 
@@ -210,7 +346,11 @@ func (s *MCPToolService) GetToolsByServer(req *models.MCPToolListRequest) (*mode
 	var tools []models.MCPTool
 	var total int64
 
-	query := s.db.Model(&models.MCPTool{}).Preload("Server")
+	scoped, err := s.workspaceTools()
+	if err != nil {
+		return nil, err
+	}
+	query := scoped.Model(&models.MCPTool{}).Preload("Server").Preload("Tags")
 
 	// 添加过滤条件
 	if req.ServerID > 0 {
@@ -230,6 +370,10 @@ func (s *MCPToolService) GetToolsByServer(req *models.MCPToolListRequest) (*mode
 			"%"+req.Search+"%", "%"+req.Search+"%")
 	}
 
+	if len(req.Tags) > 0 {
+		query = filterByTags(query, "mcp_tools", req.Tags, req.TagMode)
+	}
+
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
 		return nil, err
@@ -240,6 +384,9 @@ func (s *MCPToolService) GetToolsByServer(req *models.MCPToolListRequest) (*mode
 	if err := query.Offset(offset).Limit(req.Size).Find(&tools).Error; err != nil {
 		return nil, err
 	}
+	for i := range tools {
+		tools[i].Server.Redact()
+	}
 
 	return &models.MCPToolListResponse{
 		Total: total,
@@ -251,6 +398,11 @@ func (s *MCPToolService) GetToolsByServer(req *models.MCPToolListRequest) (*mode
 
 // UpdateTool 更新工具
 func (s *MCPToolService) UpdateTool(id uint, req *models.MCPToolUpdateRequest) error {
+	tools, err := s.workspaceTools()
+	if err != nil {
+		return err
+	}
+
 	updates := make(map[string]interface{})
 
 	if req.IsEnabled != nil {
@@ -261,9 +413,30 @@ func (s *MCPToolService) UpdateTool(id uint, req *models.MCPToolUpdateRequest) e
 		updates["category"] = req.Category
 	}
 
+	cacheChanged := false
+	if req.CacheEnabled != nil {
+		updates["cache_enabled"] = *req.CacheEnabled
+		cacheChanged = true
+	}
+
+	if req.CacheTTL != nil {
+		updates["cache_ttl"] = *req.CacheTTL
+		cacheChanged = true
+	}
+
 	if len(updates) > 0 {
 		updates["updated_at"] = time.Now()
-		return s.db.Model(&models.MCPTool{}).Where("id = ?", id).Updates(updates).Error
+		if err := tools.Model(&models.MCPTool{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+
+	// 缓存设置变更后清除该工具已有的缓存结果
+	if cacheChanged {
+		var tool models.MCPTool
+		if err := tools.First(&tool, id).Error; err == nil {
+			s.cache.PurgeTool(tool.ServerID, tool.Name)
+		}
 	}
 
 	return nil
@@ -271,6 +444,11 @@ func (s *MCPToolService) UpdateTool(id uint, req *models.MCPToolUpdateRequest) e
 
 // BatchUpdateTools 批量更新工具
 func (s *MCPToolService) BatchUpdateTools(req *models.MCPToolBatchUpdateRequest) error {
+	tools, err := s.workspaceTools()
+	if err != nil {
+		return err
+	}
+
 	updates := make(map[string]interface{})
 
 	if req.IsEnabled != nil {
@@ -283,7 +461,8 @@ func (s *MCPToolService) BatchUpdateTools(req *models.MCPToolBatchUpdateRequest)
 
 	if len(updates) > 0 {
 		updates["updated_at"] = time.Now()
-		return s.db.Model(&models.MCPTool{}).Where("id IN ?", req.ToolIDs).Updates(updates).Error
+		ctx := models.WithAuditAction(s.db.Statement.Context, models.AuditActionBatchUpdate)
+		return tools.WithContext(ctx).Model(&models.MCPTool{}).Where("id IN ?", req.ToolIDs).Updates(updates).Error
 	}
 
 	return nil
@@ -293,7 +472,11 @@ func (s *MCPToolService) BatchUpdateTools(req *models.MCPToolBatchUpdateRequest)
 func (s *MCPToolService) GetToolCategories(serverID uint) ([]string, error) {
 	var categories []string
 
-	query := s.db.Model(&models.MCPTool{}).Select("DISTINCT category").Where("category != ''")
+	tools, err := s.workspaceTools()
+	if err != nil {
+		return nil, err
+	}
+	query := tools.Model(&models.MCPTool{}).Select("DISTINCT category").Where("category != ''")
 	if serverID > 0 {
 		query = query.Where("server_id = ?", serverID)
 	}
@@ -308,70 +491,65 @@ func (s *MCPToolService) GetToolCategories(serverID uint) ([]string, error) {
 // RefreshAllTools 刷新指定服务器的所有工具
 // RefreshAllTools 刷新指定服务器的所有工具
 func (s *MCPToolService) RefreshAllTools(serverID uint) (*models.MCPToolDiscoveryResponse, error) {
-	log.Printf("开始刷新服务器 ID %d 的工具列表", serverID)
+	mcpLog.Info("开始刷新服务器的工具列表", "server_id", serverID)
 	
+	servers, err := s.workspaceServers()
+	if err != nil {
+		return nil, err
+	}
+
 	// 获取服务器信息
 	var server models.MCPServer
-	if err := s.db.First(&server, serverID).Error; err != nil {
-		log.Printf("获取服务器信息失败 (ID: %d): %v", serverID, err)
-		return &models.MCPToolDiscoveryResponse{
-			Success: false,
-			Message: "服务器不存在",
-		}, err
+	if err := servers.First(&server, serverID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrServerNotFound
+		}
+		return nil, fmt.Errorf("查询服务器失败: %w", err)
 	}
 
-	log.Printf("找到服务器: %s (URL: %s, 状态: %s)", server.Name, server.URL, server.Status)
-
 	// 检查服务器状态
 	if server.Status != "active" {
-		log.Printf("服务器 %s 未激活，状态: %s", server.Name, server.Status)
-		return &models.MCPToolDiscoveryResponse{
-			Success: false,
-			Message: "服务器未激活，无法刷新工具",
-		}, nil
+		mcpLog.Warn("服务器未激活，跳过刷新", "server_id", serverID, "status", server.Status)
+		return nil, ErrServerInactive
 	}
 
-	log.Printf("删除服务器 %d 的所有现有工具", serverID)
+	// 工具列表即将重建，清除该服务器的结果缓存
+	s.cache.PurgeServer(serverID)
+
 	// 删除该服务器的所有现有工具
 	if err := s.db.Where("server_id = ?", serverID).Delete(&models.MCPTool{}).Error; err != nil {
-		log.Printf("删除现有工具失败 (服务器 ID: %d): %v", serverID, err)
-		return &models.MCPToolDiscoveryResponse{
-			Success: false,
-			Message: "删除现有工具失败",
-		}, err
+		mcpLog.Error("删除现有工具失败", "server_id", serverID, "error", err)
+		return nil, fmt.Errorf("删除现有工具失败: %w", err)
 	}
 
-	log.Printf("开始从 MCP 服务器获取工具列表: %s", server.URL)
+	started := time.Now()
 	// 从MCP服务器获取最新的工具列表
-	tools, err := s.fetchToolsFromMCPServer(server.URL, server.AuthType, server.AuthConfig)
+	tools, err := s.fetchToolsFromMCPServer(&server)
 	if err != nil {
-		log.Printf("从 MCP 服务器 %s 获取工具列表失败: %v", server.URL, err)
-		return &models.MCPToolDiscoveryResponse{
-			Success: false,
-			Message: fmt.Sprintf("从MCP服务器获取工具列表失败: %s", err.Error()),
-		}, err
+		return nil, err
 	}
 
-	log.Printf("成功从 MCP 服务器 %s 获取 %d 个工具", server.URL, len(tools))
-
 	// 保存新的工具到数据库
 	var savedCount int
 	var failedCount int
 	for i, tool := range tools {
 		tool.ServerID = serverID // 确保设置正确的服务器ID
 		if err := s.db.Create(&tool).Error; err != nil {
-			log.Printf("保存工具失败 (索引 %d, 名称: %s): %v", i, tool.Name, err)
+			mcpLog.Error("保存工具失败", "server_id", serverID, "index", i, "tool", tool.Name, "error", err)
 			failedCount++
 		} else {
 			savedCount++
 		}
 	}
 
-	if failedCount > 0 {
-		log.Printf("工具保存完成: 成功 %d 个, 失败 %d 个", savedCount, failedCount)
-	} else {
-		log.Printf("成功保存所有 %d 个工具", savedCount)
-	}
+	mcpLog.Info("工具列表刷新完成", "server_id", serverID, "saved", savedCount, "failed", failedCount)
+
+	s.publish(events.TopicToolsSynced, events.ToolsSynced{
+		ServerID:   serverID,
+		ToolsCount: savedCount,
+		Mode:       "refresh",
+		Duration:   time.Since(started).Milliseconds(),
+	})
 
 	return &models.MCPToolDiscoveryResponse{
 		Success: true,
@@ -379,3 +557,127 @@ func (s *MCPToolService) RefreshAllTools(serverID uint) (*models.MCPToolDiscover
 		Tools:   tools,
 	}, nil
 }
+
+
+// CallTool 调用指定工具，对可缓存的工具优先返回缓存结果
+// 调用开始和结束时发布事件，调用ID同时作为进度令牌，用于关联服务器报告的进度
+func (s *MCPToolService) CallTool(toolID uint, req *models.MCPToolCallRequest) (response *models.MCPToolCallResponse, err error) {
+	tools, err := s.workspaceTools()
+	if err != nil {
+		return nil, err
+	}
+
+	var tool models.MCPTool
+	if err := tools.Preload("Server").First(&tool, toolID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrToolNotFound
+		}
+		return nil, fmt.Errorf("查询工具失败: %v", err)
+	}
+
+	if !tool.IsEnabled {
+		return nil, ErrToolDisabled
+	}
+	if !tool.Server.IsEnabled {
+		return nil, ErrServerDisabled
+	}
+
+	s.calls.begin()
+	defer s.calls.end()
+
+	callID := newRequestID()
+	started := time.Now()
+	s.publish(events.TopicToolCallStarted, events.ToolCallStarted{
+		CallID:   callID,
+		ServerID: tool.ServerID,
+		ToolID:   tool.ID,
+		ToolName: tool.Name,
+	})
+	defer func() {
+		completed := events.ToolCallCompleted{
+			CallID:   callID,
+			ServerID: tool.ServerID,
+			ToolID:   tool.ID,
+			ToolName: tool.Name,
+			Duration: time.Since(started).Milliseconds(),
+		}
+		result := metrics.ResultSuccess
+		if err != nil {
+			completed.Error = err.Error()
+			result = metrics.ResultError
+		} else {
+			completed.Cached = response.Cached
+			completed.IsError = response.Result != nil && response.Result.IsError
+			if completed.Cached {
+				result = metrics.ResultCached
+			} else if completed.IsError {
+				result = metrics.ResultToolError
+			}
+		}
+		s.publish(events.TopicToolCallCompleted, completed)
+		metrics.ObserveToolCall(tool.ServerID, tool.Server.Name, tool.Name, result, time.Since(started))
+	}()
+
+	response = &models.MCPToolCallResponse{
+		ToolID:   tool.ID,
+		ToolName: tool.Name,
+		ServerID: tool.ServerID,
+	}
+
+	// 计算缓存键
+	cacheable := tool.IsCacheable()
+	var cacheKey, canonicalArgs string
+	if cacheable {
+		key, args, err := s.cache.BuildKey(tool.ServerID, tool.Name, req.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		cacheKey, canonicalArgs = key, args
+
+		if !req.BypassCache {
+			if result, cachedAt, ok := s.cache.Get(tool.ServerID, cacheKey); ok {
+				response.Cached = true
+				response.CachedAt = &cachedAt
+				response.Result = result
+				return response, nil
+			}
+		}
+	}
+
+	// 连接MCP服务器并调用工具
+	ctx := s.mcpContext()
+	mcpClient, err := s.clients.Connect(ctx, &tool.Server)
+	if err != nil {
+		return nil, apperrors.Upstream(codeMCPConnectFailed, "连接 MCP 服务器失败", err)
+	}
+	defer func() {
+		if closeErr := mcpClient.Close(); closeErr != nil {
+			mcpLog.Warn("关闭 MCP 客户端连接时出错", "server_id", tool.ServerID, "error", closeErr)
+		}
+	}()
+
+	result, err := mcpClient.CallTool(ctx, tool.Name, req.Arguments, callID)
+	if err != nil {
+		return nil, apperrors.Upstream(codeMCPRequestFailed, "MCP服务器请求失败", err)
+	}
+
+	if cacheable {
+		s.cache.Set(tool.ServerID, tool.Name, cacheKey, canonicalArgs, result, time.Duration(tool.CacheTTL)*time.Second)
+	}
+
+	response.Result = result
+	return response, nil
+}
+
+// GetCacheStats 获取指定服务器的工具结果缓存统计
+func (s *MCPToolService) GetCacheStats(serverID uint) *models.ToolCacheStats {
+	return s.cache.Stats(serverID)
+}
+
+// PurgeCache 清除指定服务器的工具结果缓存，toolName不为空时只清除该工具
+func (s *MCPToolService) PurgeCache(serverID uint, toolName string) int {
+	if toolName != "" {
+		return s.cache.PurgeTool(serverID, toolName)
+	}
+	return s.cache.PurgeServer(serverID)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"desktop-ai-tools/models"
+)
+
+// recordingTransport 包装传输层，记录收发的每条JSON-RPC消息
+type recordingTransport struct {
+	transport.Interface
+	record    func(models.MCPTrafficEntry)
+	requestID string // 建立连接的API请求ID，服务器主动发送的消息据此关联
+}
+
+// recordingBidirectionalTransport 双向传输层的记录包装，额外记录服务器发起的请求和客户端的响应
+type recordingBidirectionalTransport struct {
+	*recordingTransport
+	inner transport.BidirectionalInterface
+}
+
+// newRecordingTransport 创建流量记录传输层，保持内层传输层是否支持双向通信
+func newRecordingTransport(inner transport.Interface, record func(models.MCPTrafficEntry), requestID string) transport.Interface {
+	t := &recordingTransport{Interface: inner, record: record, requestID: requestID}
+	if bidirectional, ok := inner.(transport.BidirectionalInterface); ok {
+		return &recordingBidirectionalTransport{recordingTransport: t, inner: bidirectional}
+	}
+	return t
+}
+
+// SendRequest 记录发出的请求和收到的响应
+func (t *recordingTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	requestID := t.requestIDFor(ctx)
+	rpcID := rpcIDString(request.ID)
+	t.record(models.MCPTrafficEntry{
+		Direction: models.TrafficOutgoing,
+		Kind:      models.TrafficRequest,
+		Method:    request.Method,
+		RPCID:     rpcID,
+		RequestID: requestID,
+		Time:      time.Now(),
+		Message:   marshalTraffic(request),
+	})
+
+	started := time.Now()
+	response, err := t.Interface.SendRequest(ctx, request)
+	entry := models.MCPTrafficEntry{
+		Direction: models.TrafficIncoming,
+		Kind:      models.TrafficResponse,
+		Method:    request.Method,
+		RPCID:     rpcID,
+		RequestID: requestID,
+		Time:      time.Now(),
+		Duration:  float64(time.Since(started).Microseconds()) / 1000,
+	}
+	if response != nil {
+		entry.Message = marshalTraffic(response)
+		if response.Error != nil {
+			entry.Error = response.Error.Message
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	t.record(entry)
+	return response, err
+}
+
+// SendNotification 记录发出的通知
+func (t *recordingTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	err := t.Interface.SendNotification(ctx, notification)
+	entry := models.MCPTrafficEntry{
+		Direction: models.TrafficOutgoing,
+		Kind:      models.TrafficNotification,
+		Method:    notification.Method,
+		RequestID: t.requestIDFor(ctx),
+		Time:      time.Now(),
+		Message:   marshalTraffic(notification),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	t.record(entry)
+	return err
+}
+
+// SetNotificationHandler 在通知交给 mcp-go 客户端之前记录
+func (t *recordingTransport) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	t.Interface.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		t.record(models.MCPTrafficEntry{
+			Direction: models.TrafficIncoming,
+			Kind:      models.TrafficNotification,
+			Method:    notification.Method,
+			RequestID: t.requestID,
+			Time:      time.Now(),
+			Message:   marshalTraffic(notification),
+		})
+		handler(notification)
+	})
+}
+
+// SetProtocolVersion 转发协议版本到HTTP传输层
+func (t *recordingTransport) SetProtocolVersion(version string) {
+	if conn, ok := t.Interface.(transport.HTTPConnection); ok {
+		conn.SetProtocolVersion(version)
+	}
+}
+
+// SetConnectionLostHandler 转发连接断开回调
+func (t *recordingTransport) SetConnectionLostHandler(handler func(error)) {
+	type connectionLostSetter interface {
+		SetConnectionLostHandler(func(error))
+	}
+	if setter, ok := t.Interface.(connectionLostSetter); ok {
+		setter.SetConnectionLostHandler(handler)
+	}
+}
+
+// requestIDFor 优先使用发送消息时上下文中的API请求ID
+func (t *recordingTransport) requestIDFor(ctx context.Context) string {
+	if id := models.RequestIDFrom(ctx); id != "" {
+		return id
+	}
+	return t.requestID
+}
+
+// SetRequestHandler 记录服务器发起的请求和客户端返回的响应
+func (t *recordingBidirectionalTransport) SetRequestHandler(handler transport.RequestHandler) {
+	t.inner.SetRequestHandler(func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+		rpcID := rpcIDString(request.ID)
+		t.record(models.MCPTrafficEntry{
+			Direction: models.TrafficIncoming,
+			Kind:      models.TrafficRequest,
+			Method:    request.Method,
+			RPCID:     rpcID,
+			RequestID: t.requestID,
+			Time:      time.Now(),
+			Message:   marshalTraffic(request),
+		})
+
+		started := time.Now()
+		response, err := handler(ctx, request)
+		entry := models.MCPTrafficEntry{
+			Direction: models.TrafficOutgoing,
+			Kind:      models.TrafficResponse,
+			Method:    request.Method,
+			RPCID:     rpcID,
+			RequestID: t.requestID,
+			Time:      time.Now(),
+			Duration:  float64(time.Since(started).Microseconds()) / 1000,
+		}
+		if response != nil {
+			entry.Message = marshalTraffic(response)
+			if response.Error != nil {
+				entry.Error = response.Error.Message
+			}
+		}
+		if err != nil {
+			// 传输层会把错误转换为JSON-RPC错误响应
+			entry.Error = err.Error()
+		}
+		t.record(entry)
+		return response, err
+	})
+}
+
+// rpcIDString 返回JSON-RPC请求ID的字符串形式
+func rpcIDString(id mcp.RequestId) string {
+	if id.IsNil() {
+		return ""
+	}
+	return fmt.Sprint(id.Value())
+}
+
+// marshalTraffic 序列化记录的消息，失败时记录错误说明
+func marshalTraffic(message interface{}) json.RawMessage {
+	data, err := json.Marshal(message)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("序列化消息失败: %v", err))
+	}
+	return data
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"desktop-ai-tools/models"
+)
+
+// fakeTransport 测试用的双向传输层，请求直接返回空结果
+type fakeTransport struct {
+	onNotification func(mcp.JSONRPCNotification)
+	onRequest      transport.RequestHandler
+}
+
+func (f *fakeTransport) Start(ctx context.Context) error { return nil }
+
+func (f *fakeTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	return transport.NewJSONRPCResultResponse(request.ID, []byte(`{}`)), nil
+}
+
+func (f *fakeTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return nil
+}
+
+func (f *fakeTransport) SetNotificationHandler(handler func(mcp.JSONRPCNotification)) {
+	f.onNotification = handler
+}
+
+func (f *fakeTransport) SetRequestHandler(handler transport.RequestHandler) {
+	f.onRequest = handler
+}
+
+func (f *fakeTransport) Close() error { return nil }
+
+func (f *fakeTransport) GetSessionId() string { return "" }
+
+// TestRecordingTransport 测试记录收发的JSON-RPC消息、关联API请求ID，以及缓冲区写满后覆盖最早的记录
+func TestRecordingTransport(t *testing.T) {
+	traffic := NewTrafficService(3)
+	traffic.SetCapture(1, true)
+
+	inner := &fakeTransport{}
+	wrapped := newRecordingTransport(inner, traffic.RecorderFor(1), "conn-1")
+	bidirectional, ok := wrapped.(transport.BidirectionalInterface)
+	if !ok {
+		t.Fatal("包装双向传输层后应仍支持双向通信")
+	}
+	bidirectional.SetNotificationHandler(func(mcp.JSONRPCNotification) {})
+	bidirectional.SetRequestHandler(func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+		return transport.NewJSONRPCResultResponse(request.ID, []byte(`{"roots":[]}`)), nil
+	})
+
+	ctx := models.WithRequestID(context.Background(), "req-1")
+	if _, err := bidirectional.SendRequest(ctx, transport.JSONRPCRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: mcp.NewRequestId(int64(1)), Method: "tools/call"}); err != nil {
+		t.Fatalf("发送请求失败: %v", err)
+	}
+	inner.onNotification(mcp.JSONRPCNotification{JSONRPC: mcp.JSONRPC_VERSION, Notification: mcp.Notification{Method: methodNotificationProgress}})
+	inner.onRequest(context.Background(), transport.JSONRPCRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: mcp.NewRequestId(int64(7)), Method: methodListRoots})
+
+	list := traffic.List(1, &models.MCPTrafficListRequest{})
+	if list.Count != 3 || list.Dropped != 2 {
+		t.Fatalf("缓冲区应保留3条并覆盖2条，实际保留 %d 条，覆盖 %d 条", list.Count, list.Dropped)
+	}
+	first := list.Entries[0]
+	if first.Kind != models.TrafficNotification || first.Direction != models.TrafficIncoming || first.RequestID != "conn-1" {
+		t.Fatalf("最早的记录应为收到的通知并关联到建立连接的请求: %+v", first)
+	}
+	last := list.Entries[2]
+	if last.Kind != models.TrafficResponse || last.Direction != models.TrafficOutgoing || last.RPCID != "7" || last.Method != methodListRoots {
+		t.Fatalf("最后的记录应为返回给服务器的响应: %+v", last)
+	}
+
+	// 发送请求时上下文中的请求ID优先于建立连接的请求ID
+	traffic.Clear(1)
+	bidirectional.SendRequest(ctx, transport.JSONRPCRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: mcp.NewRequestId(int64(2)), Method: "tools/list"})
+	list = traffic.List(1, &models.MCPTrafficListRequest{RequestID: "req-1", Kind: models.TrafficResponse})
+	if list.Total != 1 || list.Entries[0].RPCID != "2" {
+		t.Fatalf("按请求ID和类型过滤应返回1条响应，实际 %d 条", list.Total)
+	}
+
+	var out bytes.Buffer
+	if err := traffic.Export(1, &models.MCPTrafficListRequest{}, &out); err != nil {
+		t.Fatalf("导出流量记录失败: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 2 {
+		t.Fatalf("JSONL应包含2行，实际 %d 行", lines)
+	}
+
+	// 关闭抓取后不再记录
+	traffic.SetCapture(1, false)
+	bidirectional.SendRequest(ctx, transport.JSONRPCRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: mcp.NewRequestId(int64(3)), Method: "ping"})
+	if status := traffic.Status(1); status.Count != 2 {
+		t.Fatalf("关闭抓取后不应记录新消息，实际 %d 条", status.Count)
+	}
+}
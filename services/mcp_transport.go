@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// 客户端能力相关的MCP方法名
+const (
+	methodListRoots            = "roots/list"
+	methodRootsListChanged     = "notifications/roots/list_changed"
+	methodNotificationMessage  = "notifications/message"
+	methodNotificationProgress = "notifications/progress"
+)
+
+// newTransport 按服务器配置的传输方式创建传输层
+// stdio 启动子进程；streamable_http 持续监听GET流，以便接收服务器发起的请求；其他按SSE连接
+func (c *MCPClient) newTransport() (transport.Interface, error) {
+	switch c.transport {
+	case "stdio":
+		env := make([]string, 0, len(c.env))
+		for key, value := range c.env {
+			env = append(env, key+"="+value)
+		}
+		return transport.NewStdioWithOptions(c.command, env, c.args, transport.WithCommandFunc(c.startCommand)), nil
+	case "streamable_http":
+		return transport.NewStreamableHTTP(c.url,
+			transport.WithContinuousListening(),
+			transport.WithHTTPHeaders(c.headers),
+		)
+	default:
+		return transport.NewSSE(c.url, transport.WithHeaders(c.headers))
+	}
+}
+
+// RequestInterceptor 处理服务器发起的请求，返回 handled=false 时交给 mcp-go 客户端处理
+type RequestInterceptor func(ctx context.Context, request transport.JSONRPCRequest) (response *transport.JSONRPCResponse, handled bool, err error)
+
+// interceptingTransport 包装双向传输层，在 mcp-go 客户端之前处理服务器发起的请求
+// mcp-go 客户端只支持 sampling、elicitation 和 ping，roots/list 等请求需要在这里处理
+type interceptingTransport struct {
+	transport.BidirectionalInterface
+	interceptors []RequestInterceptor
+}
+
+// newInterceptingTransport 创建请求拦截传输层
+func newInterceptingTransport(inner transport.BidirectionalInterface, interceptors ...RequestInterceptor) *interceptingTransport {
+	return &interceptingTransport{
+		BidirectionalInterface: inner,
+		interceptors:           interceptors,
+	}
+}
+
+// SetRequestHandler 注册 mcp-go 客户端的请求处理器，并在其之前执行拦截器
+func (t *interceptingTransport) SetRequestHandler(handler transport.RequestHandler) {
+	t.BidirectionalInterface.SetRequestHandler(func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+		for _, interceptor := range t.interceptors {
+			response, handled, err := interceptor(ctx, request)
+			if handled {
+				return response, err
+			}
+		}
+		return handler(ctx, request)
+	})
+}
+
+// SetProtocolVersion 转发协议版本到HTTP传输层
+func (t *interceptingTransport) SetProtocolVersion(version string) {
+	if conn, ok := t.BidirectionalInterface.(transport.HTTPConnection); ok {
+		conn.SetProtocolVersion(version)
+	}
+}
+
+// SetConnectionLostHandler 转发连接断开回调
+func (t *interceptingTransport) SetConnectionLostHandler(handler func(error)) {
+	type connectionLostSetter interface {
+		SetConnectionLostHandler(func(error))
+	}
+	if setter, ok := t.BidirectionalInterface.(connectionLostSetter); ok {
+		setter.SetConnectionLostHandler(handler)
+	}
+}
+
+// rootsInterceptor 创建处理 roots/list 请求的拦截器
+func rootsInterceptor(provider func(ctx context.Context) (*mcp.ListRootsResult, error)) RequestInterceptor {
+	return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, bool, error) {
+		if request.Method != methodListRoots {
+			return nil, false, nil
+		}
+
+		result, err := provider(ctx)
+		if err != nil {
+			return nil, true, err
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, true, fmt.Errorf("序列化根目录列表失败: %w", err)
+		}
+		return transport.NewJSONRPCResultResponse(request.ID, data), true, nil
+	}
+}
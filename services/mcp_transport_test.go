@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestRootsInterceptor 测试 roots/list 请求拦截
+func TestRootsInterceptor(t *testing.T) {
+	interceptor := rootsInterceptor(func(ctx context.Context) (*mcp.ListRootsResult, error) {
+		return &mcp.ListRootsResult{
+			Roots: []mcp.Root{{URI: "file:///tmp/work", Name: "work"}},
+		}, nil
+	})
+
+	// roots/list 请求应由拦截器处理
+	request := transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  methodListRoots,
+	}
+	response, handled, err := interceptor(context.Background(), request)
+	if err != nil {
+		t.Fatalf("处理roots/list失败: %v", err)
+	}
+	if !handled {
+		t.Fatal("roots/list 请求未被处理")
+	}
+
+	var result mcp.ListRootsResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if len(result.Roots) != 1 || result.Roots[0].URI != "file:///tmp/work" {
+		t.Fatalf("根目录列表不正确: %+v", result.Roots)
+	}
+
+	// 其他请求应交给 mcp-go 客户端处理
+	request.Method = string(mcp.MethodSamplingCreateMessage)
+	if _, handled, _ := interceptor(context.Background(), request); handled {
+		t.Fatal("非roots/list请求不应被拦截")
+	}
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/models"
+)
+
+// RootsService 管理提供给MCP服务器的工作区根目录
+type RootsService struct {
+	db       *gorm.DB
+	mu       sync.RWMutex
+	onChange func(serverID *uint)
+}
+
+// NewRootsService 创建根目录服务实例
+func NewRootsService(db *gorm.DB) *RootsService {
+	return &RootsService{db: db}
+}
+
+// OnChange 设置根目录变更回调，serverID为空表示全局根目录发生变化
+func (s *RootsService) OnChange(handler func(serverID *uint)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = handler
+}
+
+// GetList 获取当前工作区的根目录列表，指定服务器时只返回该服务器专属的根目录，
+// 同时返回当前工作区中无法接收根目录的SSE服务器
+func (s *RootsService) GetList(req *models.WorkspaceRootListRequest) (*models.WorkspaceRootListResponse, error) {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []models.WorkspaceRoot
+	query := s.db.Model(&models.WorkspaceRoot{}).Where("workspace_id = ?", workspaceID)
+	if req.ServerID != nil {
+		query = query.Where("server_id = ?", *req.ServerID)
+	}
+
+	if err := query.Order("id asc").Find(&roots).Error; err != nil {
+		return nil, fmt.Errorf("查询根目录失败: %v", err)
+	}
+
+	servers := []models.WorkspaceRootServer{}
+	query = s.db.Model(&models.MCPServer{}).Scopes(inWorkspace(workspaceID)).
+		Where("transport NOT IN ?", []string{"stdio", "streamable_http"})
+	if req.ServerID != nil {
+		query = query.Where("id = ?", *req.ServerID)
+	}
+	if err := query.Order("id asc").Select("id", "name", "transport").Find(&servers).Error; err != nil {
+		return nil, fmt.Errorf("查询服务器失败: %v", err)
+	}
+
+	return &models.WorkspaceRootListResponse{Roots: roots, UnsupportedServers: servers}, nil
+}
+
+// GetForServer 获取对指定服务器生效的根目录（服务器所在工作区的全局根目录 + 服务器专属）
+func (s *RootsService) GetForServer(serverID uint) ([]models.WorkspaceRoot, error) {
+	servers := s.db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&models.MCPServer{}).Select("workspace_id").Where("id = ?", serverID)
+	var roots []models.WorkspaceRoot
+	if err := s.db.Where("workspace_id IN (?)", servers).Where("server_id IS NULL OR server_id = ?", serverID).Order("id asc").Find(&roots).Error; err != nil {
+		return nil, fmt.Errorf("查询根目录失败: %v", err)
+	}
+	return roots, nil
+}
+
+// ListRoots 生成 roots/list 的响应结果
+func (s *RootsService) ListRoots(serverID uint) (*mcp.ListRootsResult, error) {
+	roots, err := s.GetForServer(serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &mcp.ListRootsResult{Roots: make([]mcp.Root, 0, len(roots))}
+	for i := range roots {
+		result.Roots = append(result.Roots, mcp.Root{
+			URI:  roots[i].URI(),
+			Name: roots[i].Name,
+		})
+	}
+	return result, nil
+}
+
+// Create 创建根目录
+func (s *RootsService) Create(req *models.WorkspaceRootCreateRequest) (*models.WorkspaceRoot, error) {
+	path, err := validateRootPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+	if req.ServerID != nil {
+		var count int64
+		if err := s.db.Model(&models.MCPServer{}).Scopes(inWorkspace(workspaceID)).Where("id = ?", *req.ServerID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("查询服务器失败: %v", err)
+		}
+		if count == 0 {
+			return nil, ErrServerNotFound
+		}
+	}
+
+	root := &models.WorkspaceRoot{
+		WorkspaceID: workspaceID,
+		ServerID:    req.ServerID,
+		Name:        req.Name,
+		Path:        path,
+	}
+	if root.Name == "" {
+		root.Name = filepath.Base(path)
+	}
+
+	if err := s.db.Create(root).Error; err != nil {
+		return nil, fmt.Errorf("创建根目录失败: %v", err)
+	}
+
+	s.notifyChange(root.ServerID)
+	return root, nil
+}
+
+// Update 更新根目录
+func (s *RootsService) Update(id uint, req *models.WorkspaceRootUpdateRequest) (*models.WorkspaceRoot, error) {
+	root, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := validateRootPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	root.Path = path
+	root.Name = req.Name
+	if root.Name == "" {
+		root.Name = filepath.Base(path)
+	}
+
+	if err := s.db.Save(root).Error; err != nil {
+		return nil, fmt.Errorf("更新根目录失败: %v", err)
+	}
+
+	s.notifyChange(root.ServerID)
+	return root, nil
+}
+
+// Delete 删除根目录
+func (s *RootsService) Delete(id uint) error {
+	root, err := s.get(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(root).Error; err != nil {
+		return fmt.Errorf("删除根目录失败: %v", err)
+	}
+
+	s.notifyChange(root.ServerID)
+	return nil
+}
+
+// get 查询当前工作区的根目录
+func (s *RootsService) get(id uint) (*models.WorkspaceRoot, error) {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var root models.WorkspaceRoot
+	if err := s.db.Where("workspace_id = ?", workspaceID).First(&root, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRootNotFound
+		}
+		return nil, fmt.Errorf("查询根目录失败: %v", err)
+	}
+	return &root, nil
+}
+
+// notifyChange 通知根目录变更
+func (s *RootsService) notifyChange(serverID *uint) {
+	s.mu.RLock()
+	handler := s.onChange
+	s.mu.RUnlock()
+
+	if handler != nil {
+		handler(serverID)
+	}
+}
+
+// validateRootPath 校验根目录路径，必须是已存在的目录
+func validateRootPath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", validationError("root_path_not_absolute", "根目录必须是绝对路径")
+	}
+
+	path = filepath.Clean(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", validationError("root_path_not_found", "根目录不存在: %s", path)
+	}
+	if !info.IsDir() {
+		return "", validationError("root_path_not_directory", "根目录不是文件夹: %s", path)
+	}
+	return path, nil
+}
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/events"
+	"desktop-ai-tools/models"
+)
+
+// 采样决策结果
+const (
+	samplingDecisionApproved = "approved"
+	samplingDecisionDenied   = "denied"
+	samplingDecisionTimeout  = "timeout"
+	samplingDecisionError    = "error"
+)
+
+// EventNotifier 发布事件的回调，应用中为事件总线的 Publish
+type EventNotifier func(topic events.Topic, data interface{})
+
+// pendingSampling 等待用户确认的采样请求
+type pendingSampling struct {
+	info     models.SamplingPendingRequest
+	decision chan bool
+}
+
+// SamplingService 处理MCP服务器发起的采样请求
+type SamplingService struct {
+	db       *gorm.DB
+	mu       sync.Mutex
+	pending  map[string]*pendingSampling
+	notifier EventNotifier
+}
+
+// NewSamplingService 创建采样服务实例
+func NewSamplingService(db *gorm.DB) *SamplingService {
+	return &SamplingService{
+		db:      db,
+		pending: make(map[string]*pendingSampling),
+	}
+}
+
+// SetNotifier 设置事件推送回调，用于通知前端有待确认的采样请求
+func (s *SamplingService) SetNotifier(notifier EventNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = notifier
+}
+
+// GetConfig 获取采样配置，不存在时返回默认配置
+func (s *SamplingService) GetConfig() (*models.SamplingConfig, error) {
+	var config models.SamplingConfig
+	err := s.db.First(&config).Error
+	if err == gorm.ErrRecordNotFound {
+		return &models.SamplingConfig{
+			MaxTokens:     1024,
+			DefaultPolicy: models.SamplingPolicyAsk,
+			AskTimeout:    60,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询采样配置失败: %v", err)
+	}
+	return &config, nil
+}
+
+// UpdateConfig 更新采样配置
+func (s *SamplingService) UpdateConfig(req *models.SamplingConfigUpdateRequest) (*models.SamplingConfig, error) {
+	config, err := s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	config.Enabled = req.Enabled
+	config.BaseURL = req.BaseURL
+	// 未提交或提交掩码表示保持原有的API Key
+	if req.APIKey != "" && req.APIKey != models.SecretMask {
+		config.APIKey = models.EncryptedString(req.APIKey)
+	}
+	config.Model = req.Model
+	if req.MaxTokens > 0 {
+		config.MaxTokens = req.MaxTokens
+	}
+	if req.DefaultPolicy != "" {
+		config.DefaultPolicy = req.DefaultPolicy
+	}
+	if req.AskTimeout > 0 {
+		config.AskTimeout = req.AskTimeout
+	}
+
+	if config.Enabled && (config.BaseURL == "" || config.Model == "") {
+		return nil, validationError("sampling_config_incomplete", "启用采样时必须配置接口地址和模型")
+	}
+
+	if err := s.db.Save(config).Error; err != nil {
+		return nil, fmt.Errorf("保存采样配置失败: %v", err)
+	}
+	return config, nil
+}
+
+// HandlerFor 创建绑定到指定服务器的采样处理器
+func (s *SamplingService) HandlerFor(serverID uint) *SamplingHandler {
+	return &SamplingHandler{service: s, serverID: serverID}
+}
+
+// GetLogs 查询采样日志
+func (s *SamplingService) GetLogs(req *models.SamplingLogListRequest) (*models.SamplingLogListResponse, error) {
+	var logs []models.SamplingLog
+	var total int64
+
+	query := s.db.Model(&models.SamplingLog{})
+	if req.ServerID > 0 {
+		query = query.Where("server_id = ?", req.ServerID)
+	}
+	if req.Decision != "" {
+		query = query.Where("decision = ?", req.Decision)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("获取总数失败: %v", err)
+	}
+
+	offset := (req.Page - 1) * req.Size
+	if err := query.Order("created_at desc").Offset(offset).Limit(req.Size).Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("查询采样日志失败: %v", err)
+	}
+
+	return &models.SamplingLogListResponse{
+		Total: total,
+		Page:  req.Page,
+		Size:  req.Size,
+		Logs:  logs,
+	}, nil
+}
+
+// GetPending 获取所有等待用户确认的采样请求
+func (s *SamplingService) GetPending() []models.SamplingPendingRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]models.SamplingPendingRequest, 0, len(s.pending))
+	for _, p := range s.pending {
+		result = append(result, p.info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Decide 提交用户对采样请求的确认结果
+func (s *SamplingService) Decide(id string, approve bool) error {
+	s.mu.Lock()
+	p, ok := s.pending[id]
+	if ok {
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrSamplingRequestNotFound
+	}
+
+	p.decision <- approve
+	return nil
+}
+
+// createMessage 处理采样请求：检查策略、等待确认、调用LLM并记录日志
+func (s *SamplingService) createMessage(ctx context.Context, serverID uint, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	start := time.Now()
+	entry := &models.SamplingLog{ServerID: serverID}
+	if data, err := json.Marshal(request.CreateMessageParams); err == nil {
+		entry.Request = string(data)
+	}
+	defer func() {
+		entry.DurationMs = time.Since(start).Milliseconds()
+		if err := s.db.Create(entry).Error; err != nil {
+			mcpLog.Error("保存采样日志失败", "error", err)
+		}
+	}()
+
+	config, err := s.GetConfig()
+	if err != nil {
+		entry.Decision = samplingDecisionError
+		entry.Error = err.Error()
+		return nil, err
+	}
+
+	var server models.MCPServer
+	if err := s.db.First(&server, serverID).Error; err != nil {
+		entry.Decision = samplingDecisionError
+		entry.Error = "服务器不存在"
+		return nil, ErrServerNotFound
+	}
+
+	// 策略优先级：服务器 > 工作区 > 全局默认
+	policy := server.SamplingPolicy
+	if policy == "" {
+		var workspace models.Workspace
+		if err := s.db.Select("sampling_policy").First(&workspace, server.WorkspaceID).Error; err == nil {
+			policy = workspace.SamplingPolicy
+		}
+	}
+	if policy == "" {
+		policy = config.DefaultPolicy
+	}
+	entry.Policy = policy
+
+	if !config.Enabled {
+		entry.Decision = samplingDecisionDenied
+		entry.Error = "采样功能未启用"
+		return nil, fmt.Errorf("采样功能未启用")
+	}
+
+	switch policy {
+	case models.SamplingPolicyDeny:
+		entry.Decision = samplingDecisionDenied
+		entry.Error = "采样请求被策略拒绝"
+		return nil, fmt.Errorf("采样请求被策略拒绝")
+	case models.SamplingPolicyAsk:
+		approved, err := s.waitForApproval(ctx, &server, request, time.Duration(config.AskTimeout)*time.Second)
+		if err != nil {
+			entry.Decision = samplingDecisionTimeout
+			entry.Error = err.Error()
+			return nil, err
+		}
+		if !approved {
+			entry.Decision = samplingDecisionDenied
+			entry.Error = "用户拒绝了采样请求"
+			return nil, fmt.Errorf("用户拒绝了采样请求")
+		}
+	}
+
+	result, err := s.complete(ctx, config, request.CreateMessageParams)
+	if err != nil {
+		entry.Decision = samplingDecisionError
+		entry.Error = err.Error()
+		return nil, err
+	}
+
+	entry.Decision = samplingDecisionApproved
+	entry.Model = result.Model
+	if data, err := json.Marshal(result); err == nil {
+		entry.Response = string(data)
+	}
+	return result, nil
+}
+
+// waitForApproval 等待用户确认采样请求，超时视为拒绝
+func (s *SamplingService) waitForApproval(ctx context.Context, server *models.MCPServer, request mcp.CreateMessageRequest, timeout time.Duration) (bool, error) {
+	now := time.Now()
+	p := &pendingSampling{
+		info: models.SamplingPendingRequest{
+			ID:         newRequestID(),
+			ServerID:   server.ID,
+			ServerName: server.Name,
+			Request:    request.CreateMessageParams,
+			CreatedAt:  now,
+			ExpiresAt:  now.Add(timeout),
+		},
+		decision: make(chan bool, 1),
+	}
+
+	s.mu.Lock()
+	s.pending[p.info.ID] = p
+	notifier := s.notifier
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, p.info.ID)
+		s.mu.Unlock()
+	}()
+
+	if notifier != nil {
+		notifier(events.TopicSamplingPending, p.info)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case approved := <-p.decision:
+		return approved, nil
+	case <-timer.C:
+		return false, ErrSamplingTimeout
+	case <-ctx.Done():
+		return false, fmt.Errorf("采样请求已取消: %v", ctx.Err())
+	}
+}
+
+// complete 将采样请求转换为OpenAI兼容请求并调用LLM
+func (s *SamplingService) complete(ctx context.Context, config *models.SamplingConfig, params mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	messages := make([]ChatMessage, 0, len(params.Messages)+1)
+	if params.SystemPrompt != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: params.SystemPrompt})
+	}
+	for _, msg := range params.Messages {
+		content, err := convertSamplingContent(msg.Content)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, ChatMessage{Role: string(msg.Role), Content: content})
+	}
+
+	maxTokens := params.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = config.MaxTokens
+	}
+
+	req := &ChatCompletionRequest{
+		Model:     config.Model,
+		Messages:  messages,
+		MaxTokens: maxTokens,
+		Stop:      params.StopSequences,
+	}
+	if params.Temperature > 0 {
+		temperature := params.Temperature
+		req.Temperature = &temperature
+	}
+
+	resp, err := NewLLMClient(config.BaseURL, string(config.APIKey)).CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	choice := resp.Choices[0]
+	model := resp.Model
+	if model == "" {
+		model = config.Model
+	}
+
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{
+			Role:    mcp.RoleAssistant,
+			Content: mcp.NewTextContent(choice.Message.Content),
+		},
+		Model:      model,
+		StopReason: convertFinishReason(choice.FinishReason),
+	}, nil
+}
+
+// convertSamplingContent 将MCP消息内容转换为OpenAI兼容的消息内容
+func convertSamplingContent(content interface{}) (interface{}, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("解析采样消息失败: %v", err)
+	}
+
+	var parsed struct {
+		Type     string `json:"type"`
+		Text     string `json:"text"`
+		Data     string `json:"data"`
+		MimeType string `json:"mimeType"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("解析采样消息失败: %v", err)
+	}
+
+	switch parsed.Type {
+	case "text":
+		return parsed.Text, nil
+	case "image":
+		return []map[string]interface{}{
+			{
+				"type": "image_url",
+				"image_url": map[string]string{
+					"url": fmt.Sprintf("data:%s;base64,%s", parsed.MimeType, parsed.Data),
+				},
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的采样消息类型: %s", parsed.Type)
+	}
+}
+
+// convertFinishReason 将OpenAI的结束原因转换为MCP的停止原因
+func convertFinishReason(reason string) string {
+	switch reason {
+	case "stop":
+		return "endTurn"
+	case "length":
+		return "maxTokens"
+	default:
+		return reason
+	}
+}
+
+// SamplingHandler 绑定到单个服务器的采样处理器，实现 client.SamplingHandler 接口
+type SamplingHandler struct {
+	service  *SamplingService
+	serverID uint
+}
+
+// CreateMessage 处理服务器发起的 sampling/createMessage 请求
+func (h *SamplingHandler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return h.service.createMessage(ctx, h.serverID, request)
+}
+
+// newRequestID 生成随机请求ID
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
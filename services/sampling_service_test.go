@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/events"
+	"desktop-ai-tools/models"
+)
+
+// newFakeLLM 启动返回固定回复的OpenAI兼容接口，返回接口地址和收到的请求数
+func newFakeLLM(t *testing.T) (string, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"unauthorized"}}`))
+			return
+		}
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) != 2 || req.Messages[0].Role != "system" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"bad request"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"model":"fake-model","choices":[{"message":{"role":"assistant","content":"你好"},"finish_reason":"stop"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &calls
+}
+
+// newSamplingTest 创建已启用采样的服务和指定策略的服务器
+func newSamplingTest(t *testing.T, defaultPolicy, serverPolicy string) (*gorm.DB, *SamplingService, uint, *int32) {
+	t.Helper()
+
+	db := newTestDB(t)
+	baseURL, calls := newFakeLLM(t)
+	service := NewSamplingService(db)
+	_, err := service.UpdateConfig(&models.SamplingConfigUpdateRequest{
+		Enabled:       true,
+		BaseURL:       baseURL,
+		APIKey:        "sk-test",
+		Model:         "fake-model",
+		DefaultPolicy: defaultPolicy,
+		AskTimeout:    5,
+	})
+	if err != nil {
+		t.Fatalf("保存采样配置失败: %v", err)
+	}
+
+	workspaceID, err := activeWorkspaceID(db)
+	if err != nil {
+		t.Fatalf("获取当前工作区失败: %v", err)
+	}
+	server := models.MCPServer{WorkspaceID: workspaceID, Name: "sampler", URL: "https://example.com/mcp", Transport: "streamable_http", SamplingPolicy: serverPolicy}
+	if err := db.Create(&server).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	return db, service, server.ID, calls
+}
+
+// samplingRequest 构造包含系统提示和一条用户消息的采样请求
+func samplingRequest() mcp.CreateMessageRequest {
+	request := mcp.CreateMessageRequest{}
+	request.SystemPrompt = "你是助手"
+	request.Messages = []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("打个招呼")}}
+	request.MaxTokens = 100
+	return request
+}
+
+// lastSamplingLog 读取最新的采样日志
+func lastSamplingLog(t *testing.T, db *gorm.DB) models.SamplingLog {
+	t.Helper()
+
+	var entry models.SamplingLog
+	if err := db.Order("id desc").First(&entry).Error; err != nil {
+		t.Fatalf("查询采样日志失败: %v", err)
+	}
+	return entry
+}
+
+// TestSamplingPolicy 测试允许和拒绝策略、服务器策略覆盖全局默认策略，以及采样日志的记录
+func TestSamplingPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultPolicy string
+		serverPolicy  string
+		wantDecision  string
+	}{
+		{"全局允许", models.SamplingPolicyAllow, "", samplingDecisionApproved},
+		{"全局拒绝", models.SamplingPolicyDeny, "", samplingDecisionDenied},
+		{"服务器允许覆盖全局拒绝", models.SamplingPolicyDeny, models.SamplingPolicyAllow, samplingDecisionApproved},
+		{"服务器拒绝覆盖全局允许", models.SamplingPolicyAllow, models.SamplingPolicyDeny, samplingDecisionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, service, serverID, calls := newSamplingTest(t, tt.defaultPolicy, tt.serverPolicy)
+
+			result, err := service.HandlerFor(serverID).CreateMessage(context.Background(), samplingRequest())
+			entry := lastSamplingLog(t, db)
+			if entry.ServerID != serverID || entry.Decision != tt.wantDecision || entry.Request == "" {
+				t.Fatalf("采样日志不正确: %+v", entry)
+			}
+
+			if tt.wantDecision == samplingDecisionDenied {
+				if err == nil || atomic.LoadInt32(calls) != 0 {
+					t.Fatalf("拒绝的采样请求不应调用LLM: %v, 调用 %d 次", err, *calls)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("采样失败: %v", err)
+			}
+			text, ok := result.Content.(mcp.TextContent)
+			if !ok || text.Text != "你好" || result.Model != "fake-model" || result.StopReason != "endTurn" {
+				t.Fatalf("采样结果不正确: %+v", result)
+			}
+			if entry.Model != "fake-model" || entry.Response == "" {
+				t.Fatalf("采样日志应记录模型和响应: %+v", entry)
+			}
+		})
+	}
+}
+
+// TestSamplingAsk 测试询问策略：等待用户确认后调用LLM，用户拒绝或请求取消时不调用
+func TestSamplingAsk(t *testing.T) {
+	db, service, serverID, calls := newSamplingTest(t, models.SamplingPolicyAsk, "")
+
+	pending := make(chan string, 1)
+	service.SetNotifier(func(topic events.Topic, data interface{}) {
+		if topic == events.TopicSamplingPending {
+			pending <- data.(models.SamplingPendingRequest).ID
+		}
+	})
+
+	// 后台发起采样请求，返回请求ID和结果通道
+	start := func(ctx context.Context) (string, <-chan error) {
+		done := make(chan error, 1)
+		go func() {
+			_, err := service.HandlerFor(serverID).CreateMessage(ctx, samplingRequest())
+			done <- err
+		}()
+		select {
+		case id := <-pending:
+			return id, done
+		case <-time.After(time.Second):
+			t.Fatal("未收到待确认的采样请求事件")
+			return "", nil
+		}
+	}
+
+	id, done := start(context.Background())
+	if len(service.GetPending()) != 1 {
+		t.Fatal("等待确认的采样请求应出现在待确认列表中")
+	}
+	if err := service.Decide(id, true); err != nil {
+		t.Fatalf("确认采样请求失败: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("确认后采样失败: %v", err)
+	}
+	if entry := lastSamplingLog(t, db); entry.Decision != samplingDecisionApproved || entry.Policy != models.SamplingPolicyAsk {
+		t.Fatalf("采样日志不正确: %+v", entry)
+	}
+	if err := service.Decide(id, true); !errors.Is(err, ErrSamplingRequestNotFound) {
+		t.Fatalf("重复确认应返回请求不存在，实际: %v", err)
+	}
+
+	id, done = start(context.Background())
+	if err := service.Decide(id, false); err != nil {
+		t.Fatalf("拒绝采样请求失败: %v", err)
+	}
+	if err := <-done; err == nil {
+		t.Fatal("用户拒绝后应返回错误")
+	}
+	if entry := lastSamplingLog(t, db); entry.Decision != samplingDecisionDenied {
+		t.Fatalf("用户拒绝应记录为 denied: %+v", entry)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, done = start(ctx)
+	cancel()
+	if err := <-done; err == nil {
+		t.Fatal("请求取消后应返回错误")
+	}
+	if len(service.GetPending()) != 0 {
+		t.Fatal("结束的采样请求应从待确认列表中移除")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("只有确认的请求应调用LLM，实际调用 %d 次", got)
+	}
+}
+
+// TestSamplingConfigAPIKey 测试更新配置时未提交或提交掩码的API Key保持原值
+func TestSamplingConfigAPIKey(t *testing.T) {
+	db := newTestDB(t)
+	service := NewSamplingService(db)
+
+	update := models.SamplingConfigUpdateRequest{BaseURL: "https://api.example.com/v1", APIKey: "sk-original", Model: "m"}
+	if _, err := service.UpdateConfig(&update); err != nil {
+		t.Fatalf("保存采样配置失败: %v", err)
+	}
+
+	for _, key := range []string{"", models.SecretMask} {
+		update.APIKey = key
+		if _, err := service.UpdateConfig(&update); err != nil {
+			t.Fatalf("更新采样配置失败: %v", err)
+		}
+		config, err := service.GetConfig()
+		if err != nil {
+			t.Fatalf("读取采样配置失败: %v", err)
+		}
+		if config.APIKey != "sk-original" {
+			t.Fatalf("提交 %q 后API Key应保持不变，实际: %q", key, config.APIKey)
+		}
+	}
+
+	update.APIKey = "sk-new"
+	config, err := service.UpdateConfig(&update)
+	if err != nil {
+		t.Fatalf("更新采样配置失败: %v", err)
+	}
+	if config.APIKey != "sk-new" {
+		t.Fatalf("提交新的API Key后应更新，实际: %q", config.APIKey)
+	}
+}
+
+// TestUpdateSamplingPolicyTransport 测试SSE服务器不能设置允许或询问的采样策略
+func TestUpdateSamplingPolicyTransport(t *testing.T) {
+	db := newTestDB(t)
+	service := &MCPServerService{db: db}
+	workspaceID, err := activeWorkspaceID(db)
+	if err != nil {
+		t.Fatalf("获取当前工作区失败: %v", err)
+	}
+
+	sse := models.MCPServer{WorkspaceID: workspaceID, Name: "sse", URL: "https://example.com/sse", Transport: "sse"}
+	streamable := models.MCPServer{WorkspaceID: workspaceID, Name: "http", URL: "https://example.com/mcp", Transport: "streamable_http"}
+	if err := db.Create(&sse).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	if err := db.Create(&streamable).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+
+	err = service.UpdateSamplingPolicy(sse.ID, models.SamplingPolicyAllow)
+	if apperrors.From(err).Code != "sampling_unsupported_transport" {
+		t.Fatalf("SSE服务器设置允许策略应返回校验错误，实际: %v", err)
+	}
+	if err := service.UpdateSamplingPolicy(sse.ID, models.SamplingPolicyDeny); err != nil {
+		t.Fatalf("SSE服务器应可以设置拒绝策略: %v", err)
+	}
+	if err := service.UpdateSamplingPolicy(streamable.ID, models.SamplingPolicyAsk); err != nil {
+		t.Fatalf("streamable_http服务器应可以设置询问策略: %v", err)
+	}
+	if err := service.UpdateSamplingPolicy(9999, ""); !errors.Is(err, ErrServerNotFound) {
+		t.Fatalf("服务器不存在时应返回未找到，实际: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/models"
+	"desktop-ai-tools/security"
+)
+
+// encryptedColumns 加密存储的字段，新增加密字段时需同步登记
+var encryptedColumns = []struct {
+	table   string
+	columns []string
+}{
+	{table: "mcp_servers", columns: []string{"auth_config", "env", "headers"}},
+	{table: "sampling_configs", columns: []string{"api_key"}},
+	{table: "secrets", columns: []string{"value"}},
+}
+
+// SecretKeyService 管理敏感字段的主密钥：加密遗留的明文数据以及轮换密钥
+type SecretKeyService struct {
+	db *gorm.DB
+}
+
+// NewSecretKeyService 创建密钥管理服务实例
+func NewSecretKeyService(db *gorm.DB) *SecretKeyService {
+	return &SecretKeyService{db: db}
+}
+
+// EncryptPlaintext 加密升级前以明文保存的敏感字段，返回处理的记录数
+func (s *SecretKeyService) EncryptPlaintext() (int, error) {
+	var count int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		count, err = reencryptColumns(tx, true)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("加密明文数据失败: %v", err)
+	}
+	return count, nil
+}
+
+// Rotate 生成新的主密钥并用其重新加密所有敏感字段，完成后删除旧密钥
+func (s *SecretKeyService) Rotate() (*models.KeyRotationResult, error) {
+	keyring := security.Default()
+	if keyring == nil {
+		return nil, ErrMasterKeyNotInitialized
+	}
+
+	keyID, err := keyring.Rotate()
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		count, err = reencryptColumns(tx, false)
+		return err
+	})
+	if err != nil {
+		// 旧密钥仍然保留，已有数据可以正常解密
+		return nil, fmt.Errorf("重新加密数据失败: %v", err)
+	}
+
+	if err := keyring.Retire(); err != nil {
+		return nil, err
+	}
+	return &models.KeyRotationResult{KeyID: keyID, Reencrypted: count}, nil
+}
+
+// reencryptColumns 使用当前密钥重新加密敏感字段，onlyPlaintext 为true时只处理明文
+func reencryptColumns(tx *gorm.DB, onlyPlaintext bool) (int, error) {
+	count := 0
+	for _, target := range encryptedColumns {
+		var rows []map[string]interface{}
+		columns := append([]string{"id"}, target.columns...)
+		if err := tx.Table(target.table).Select(columns).Find(&rows).Error; err != nil {
+			return 0, err
+		}
+
+		for _, row := range rows {
+			updates := make(map[string]interface{})
+			for _, column := range target.columns {
+				raw := rawString(row[column])
+				if raw == "" || (onlyPlaintext && security.IsEncrypted(raw)) {
+					continue
+				}
+				plaintext, err := security.Decrypt(raw)
+				if err != nil {
+					return 0, fmt.Errorf("解密 %s.%s 失败: %v", target.table, column, err)
+				}
+				updates[column] = models.EncryptedString(plaintext)
+			}
+			if len(updates) == 0 {
+				continue
+			}
+			if err := tx.Table(target.table).Where("id = ?", row["id"]).UpdateColumns(updates).Error; err != nil {
+				return 0, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// rawString 将数据库返回的原始值转换为字符串
+func rawString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
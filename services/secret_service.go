@@ -0,0 +1,324 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/models"
+)
+
+// placeholderPattern 匹配 ${env:NAME} 和 ${secret:name} 占位符
+var placeholderPattern = regexp.MustCompile(`\$\{(env|secret):([A-Za-z0-9_.\-]+)\}`)
+
+// secretNamePattern 密钥名称只允许字母、数字、下划线、点和中划线
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// SecretService 管理命名密钥，并在连接时解析服务器配置中的占位符
+type SecretService struct {
+	db *gorm.DB
+}
+
+// NewSecretService 创建密钥服务实例
+func NewSecretService(db *gorm.DB) *SecretService {
+	return &SecretService{db: db}
+}
+
+// List 获取所有密钥，值以掩码返回
+func (s *SecretService) List() ([]models.Secret, error) {
+	var secrets []models.Secret
+	if err := s.db.Order("name asc").Find(&secrets).Error; err != nil {
+		return nil, fmt.Errorf("查询密钥失败: %v", err)
+	}
+
+	usage, err := s.references()
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		secrets[i].UsageCount = len(usage[secrets[i].Name])
+		secrets[i].Redact()
+	}
+	return secrets, nil
+}
+
+// Create 创建密钥
+func (s *SecretService) Create(req *models.SecretCreateRequest) (*models.Secret, error) {
+	if !secretNamePattern.MatchString(req.Name) {
+		return nil, validationError("invalid_secret_name", "密钥名称只能包含字母、数字、下划线、点和中划线")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Secret{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("检查名称重复失败: %v", err)
+	}
+	if count > 0 {
+		return nil, ErrSecretNameExists
+	}
+
+	secret := &models.Secret{
+		Name:        req.Name,
+		Description: req.Description,
+		Value:       models.EncryptedString(req.Value),
+	}
+	if err := s.db.Create(secret).Error; err != nil {
+		return nil, fmt.Errorf("创建密钥失败: %v", err)
+	}
+
+	secret.Redact()
+	return secret, nil
+}
+
+// Update 更新密钥的描述和值
+func (s *SecretService) Update(name string, req *models.SecretUpdateRequest) (*models.Secret, error) {
+	secret, err := s.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"description": req.Description,
+	}
+	if req.Value != "" && req.Value != models.SecretMask {
+		updates["value"] = models.EncryptedString(req.Value)
+	}
+	if err := s.db.Model(secret).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("更新密钥失败: %v", err)
+	}
+
+	secret, err = s.get(name)
+	if err != nil {
+		return nil, err
+	}
+	secret.Redact()
+	return secret, nil
+}
+
+// Delete 删除密钥，仍被服务器引用时需要 force 才能删除
+func (s *SecretService) Delete(name string, force bool) error {
+	secret, err := s.get(name)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		usage, err := s.Usage(name)
+		if err != nil {
+			return err
+		}
+		if len(usage.Servers) > 0 {
+			names := make([]string, 0, len(usage.Servers))
+			for _, ref := range usage.Servers {
+				names = append(names, ref.ServerName)
+			}
+			return apperrors.Newf(apperrors.KindConflict, "secret_in_use", "密钥仍被以下服务器引用: %s", strings.Join(names, ", "))
+		}
+	}
+
+	if err := s.db.Delete(secret).Error; err != nil {
+		return fmt.Errorf("删除密钥失败: %v", err)
+	}
+	return nil
+}
+
+// Usage 获取引用指定密钥的服务器
+func (s *SecretService) Usage(name string) (*models.SecretUsage, error) {
+	usage, err := s.references()
+	if err != nil {
+		return nil, err
+	}
+
+	servers := usage[name]
+	if servers == nil {
+		servers = []models.SecretReference{}
+	}
+	return &models.SecretUsage{Name: name, Servers: servers}, nil
+}
+
+// ResolveServer 返回解析了占位符的服务器配置副本，用于建立连接
+func (s *SecretService) ResolveServer(server *models.MCPServer) (*models.MCPServer, error) {
+	resolved := *server
+
+	url, err := s.Resolve(server.URL)
+	if err != nil {
+		return nil, err
+	}
+	resolved.URL = url
+
+	authConfig, err := s.resolveAuthConfig(string(server.AuthConfig))
+	if err != nil {
+		return nil, err
+	}
+	resolved.AuthConfig = models.EncryptedString(authConfig)
+
+	env, err := s.resolveMap(server.GetEnv())
+	if err != nil {
+		return nil, err
+	}
+	headers, err := s.resolveMap(server.GetHeaders())
+	if err != nil {
+		return nil, err
+	}
+	resolved.SetConnection(server.GetArgs(), env, headers)
+	return &resolved, nil
+}
+
+// Resolve 替换字符串中的 ${env:NAME} 和 ${secret:name} 占位符
+func (s *SecretService) Resolve(value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var resolveErr error
+	result := placeholderPattern.ReplaceAllStringFunc(value, func(match string) string {
+		parts := placeholderPattern.FindStringSubmatch(match)
+		kind, name := parts[1], parts[2]
+
+		if kind == "env" {
+			v, ok := os.LookupEnv(name)
+			if !ok && resolveErr == nil {
+				resolveErr = fmt.Errorf("环境变量 %s 未设置", name)
+			}
+			return v
+		}
+
+		secret, err := s.get(name)
+		if err != nil {
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("引用的密钥 %s 不存在", name)
+			}
+			return ""
+		}
+		return string(secret.Value)
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return result, nil
+}
+
+// resolveAuthConfig 逐个解析认证配置中字符串值的占位符，避免密钥中的特殊字符破坏JSON
+func (s *SecretService) resolveAuthConfig(value string) (string, error) {
+	if !hasPlaceholder(value) {
+		return value, nil
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return s.Resolve(value)
+	}
+	for k, v := range config {
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		resolved, err := s.Resolve(str)
+		if err != nil {
+			return "", fmt.Errorf("解析认证配置 %s 失败: %v", k, err)
+		}
+		config[k] = resolved
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("序列化认证配置失败: %v", err)
+	}
+	return string(data), nil
+}
+
+// resolveMap 解析映射中所有值的占位符
+func (s *SecretService) resolveMap(values map[string]string) (map[string]string, error) {
+	for k, v := range values {
+		resolved, err := s.Resolve(v)
+		if err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %v", k, err)
+		}
+		values[k] = resolved
+	}
+	return values, nil
+}
+
+// references 扫描所有服务器配置，返回密钥名称到引用服务器的映射
+func (s *SecretService) references() (map[string][]models.SecretReference, error) {
+	var servers []models.MCPServer
+	if err := s.db.Order("name asc").Find(&servers).Error; err != nil {
+		return nil, fmt.Errorf("查询服务器失败: %v", err)
+	}
+
+	usage := make(map[string][]models.SecretReference)
+	for _, server := range servers {
+		fields := map[string]string{
+			"url":         server.URL,
+			"auth_config": string(server.AuthConfig),
+			"headers":     string(server.Headers),
+			"env":         string(server.Env),
+		}
+
+		found := make(map[string][]string)
+		for _, field := range []string{"url", "auth_config", "headers", "env"} {
+			for _, name := range secretReferences(fields[field]) {
+				if !containsString(found[name], field) {
+					found[name] = append(found[name], field)
+				}
+			}
+		}
+
+		names := make([]string, 0, len(found))
+		for name := range found {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			usage[name] = append(usage[name], models.SecretReference{
+				ServerID:   server.ID,
+				ServerName: server.Name,
+				Fields:     found[name],
+			})
+		}
+	}
+	return usage, nil
+}
+
+// get 按名称查询密钥
+func (s *SecretService) get(name string) (*models.Secret, error) {
+	var secret models.Secret
+	if err := s.db.Where("name = ?", name).First(&secret).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSecretNotFound
+		}
+		return nil, fmt.Errorf("查询密钥失败: %v", err)
+	}
+	return &secret, nil
+}
+
+// secretReferences 提取字符串中引用的密钥名称
+func secretReferences(value string) []string {
+	var names []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+		if match[1] == "secret" {
+			names = append(names, match[2])
+		}
+	}
+	return names
+}
+
+// hasPlaceholder 判断字符串是否包含占位符
+func hasPlaceholder(value string) bool {
+	return placeholderPattern.MatchString(value)
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
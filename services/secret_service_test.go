@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"desktop-ai-tools/models"
+)
+
+// TestResolveServer 测试连接时解析环境变量和密钥占位符
+func TestResolveServer(t *testing.T) {
+	db := newTestDB(t)
+	service := NewSecretService(db)
+	t.Setenv("MCP_TEST_HOST", "mcp.example.com")
+
+	if _, err := service.Create(&models.SecretCreateRequest{Name: "gh-token", Value: `ghp_"quoted"`}); err != nil {
+		t.Fatalf("创建密钥失败: %v", err)
+	}
+
+	server := models.MCPServer{
+		Name:       "github",
+		URL:        "https://${env:MCP_TEST_HOST}/mcp",
+		AuthType:   "bearer",
+		AuthConfig: `{"token":"${secret:gh-token}"}`,
+	}
+	server.SetConnection(nil, map[string]string{"GITHUB_TOKEN": "${secret:gh-token}"}, nil)
+	if err := db.Create(&server).Error; err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+
+	resolved, err := service.ResolveServer(&server)
+	if err != nil {
+		t.Fatalf("解析占位符失败: %v", err)
+	}
+	if resolved.URL != "https://mcp.example.com/mcp" {
+		t.Fatalf("URL解析错误: %s", resolved.URL)
+	}
+	if resolved.GetEnv()["GITHUB_TOKEN"] != `ghp_"quoted"` {
+		t.Fatalf("环境变量解析错误: %v", resolved.GetEnv())
+	}
+	if headers := authHeaders(resolved); headers["Authorization"] != `Bearer ghp_"quoted"` {
+		t.Fatalf("认证配置解析错误: %v", headers)
+	}
+	// 原始配置保持不变
+	if server.GetEnv()["GITHUB_TOKEN"] != "${secret:gh-token}" {
+		t.Fatalf("原始配置被修改: %v", server.GetEnv())
+	}
+
+	usage, err := service.Usage("gh-token")
+	if err != nil || len(usage.Servers) != 1 || len(usage.Servers[0].Fields) != 2 {
+		t.Fatalf("引用情况错误: %+v, %v", usage, err)
+	}
+	if err := service.Delete("gh-token", false); err == nil {
+		t.Fatal("被引用的密钥不应直接删除")
+	}
+
+	if _, err := service.Resolve("${secret:missing}"); err == nil {
+		t.Fatal("引用不存在的密钥应返回错误")
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/models"
+)
+
+const (
+	// maxLogsPerServer 每个服务器保留的最大日志条数
+	maxLogsPerServer = 5000
+	// logPruneInterval 每写入多少条日志清理一次旧日志
+	logPruneInterval = 200
+)
+
+// serverLogSubscriber 日志实时订阅者
+type serverLogSubscriber struct {
+	minRank int
+	logger  string
+	ch      chan models.MCPServerLog
+}
+
+// ServerLogService 保存并推送MCP服务器发送的日志消息
+type ServerLogService struct {
+	db          *gorm.DB
+	mu          sync.Mutex
+	subscribers map[uint]map[*serverLogSubscriber]struct{}
+	writes      map[uint]int
+}
+
+// NewServerLogService 创建服务器日志服务实例
+func NewServerLogService(db *gorm.DB) *ServerLogService {
+	return &ServerLogService{
+		db:          db,
+		subscribers: make(map[uint]map[*serverLogSubscriber]struct{}),
+		writes:      make(map[uint]int),
+	}
+}
+
+// HandleNotification 处理服务器发送的 notifications/message 通知
+func (s *ServerLogService) HandleNotification(serverID uint, notification mcp.JSONRPCNotification) {
+	if notification.Method != methodNotificationMessage {
+		return
+	}
+
+	fields := notification.Params.AdditionalFields
+	level, _ := fields["level"].(string)
+	logger, _ := fields["logger"].(string)
+
+	data, err := json.Marshal(fields["data"])
+	if err != nil {
+		data = []byte(fmt.Sprintf("%q", fmt.Sprint(fields["data"])))
+	}
+
+	s.Record(serverID, level, logger, string(data))
+}
+
+// Record 保存一条服务器日志并推送给订阅者
+func (s *ServerLogService) Record(serverID uint, level, logger, data string) {
+	entry := models.MCPServerLog{
+		ServerID:  serverID,
+		Level:     level,
+		LevelRank: models.ServerLogLevelRank(level),
+		Logger:    logger,
+		Data:      data,
+	}
+	if err := s.db.Create(&entry).Error; err != nil {
+		mcpLog.Error("保存服务器日志失败", "server_id", serverID, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.writes[serverID]++
+	prune := s.writes[serverID]%logPruneInterval == 0
+	for sub := range s.subscribers[serverID] {
+		if entry.LevelRank < sub.minRank || (sub.logger != "" && sub.logger != entry.Logger) {
+			continue
+		}
+		// 订阅者处理不过来时丢弃，避免阻塞MCP连接
+		select {
+		case sub.ch <- entry:
+		default:
+		}
+	}
+	s.mu.Unlock()
+
+	if prune {
+		s.prune(serverID)
+	}
+}
+
+// GetLogs 查询服务器日志
+func (s *ServerLogService) GetLogs(serverID uint, req *models.MCPServerLogListRequest) (*models.MCPServerLogListResponse, error) {
+	var logs []models.MCPServerLog
+	var total int64
+
+	query := s.db.Model(&models.MCPServerLog{}).Where("server_id = ?", serverID)
+	if req.Level != "" {
+		query = query.Where("level_rank >= ?", models.ServerLogLevelRank(req.Level))
+	}
+	if req.Logger != "" {
+		query = query.Where("logger = ?", req.Logger)
+	}
+	if req.Search != "" {
+		query = query.Where("data LIKE ?", "%"+req.Search+"%")
+	}
+	if !req.Since.IsZero() {
+		query = query.Where("created_at >= ?", req.Since)
+	}
+	if !req.Until.IsZero() {
+		query = query.Where("created_at <= ?", req.Until)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("获取总数失败: %v", err)
+	}
+
+	offset := (req.Page - 1) * req.Size
+	if err := query.Order("id desc").Offset(offset).Limit(req.Size).Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("查询服务器日志失败: %v", err)
+	}
+
+	return &models.MCPServerLogListResponse{
+		Total: total,
+		Page:  req.Page,
+		Size:  req.Size,
+		Logs:  logs,
+	}, nil
+}
+
+// Subscribe 订阅服务器的实时日志，返回的取消函数必须调用以释放资源
+func (s *ServerLogService) Subscribe(serverID uint, minLevel, logger string) (<-chan models.MCPServerLog, func()) {
+	sub := &serverLogSubscriber{
+		minRank: models.ServerLogLevelRank(minLevel),
+		logger:  logger,
+		ch:      make(chan models.MCPServerLog, 100),
+	}
+
+	s.mu.Lock()
+	if s.subscribers[serverID] == nil {
+		s.subscribers[serverID] = make(map[*serverLogSubscriber]struct{})
+	}
+	s.subscribers[serverID][sub] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers[serverID], sub)
+			if len(s.subscribers[serverID]) == 0 {
+				delete(s.subscribers, serverID)
+			}
+			s.mu.Unlock()
+		})
+	}
+	return sub.ch, cancel
+}
+
+// Clear 清空服务器日志
+func (s *ServerLogService) Clear(serverID uint) error {
+	if err := s.db.Where("server_id = ?", serverID).Delete(&models.MCPServerLog{}).Error; err != nil {
+		return fmt.Errorf("清空服务器日志失败: %v", err)
+	}
+	return nil
+}
+
+// prune 清理超出保留条数的旧日志
+func (s *ServerLogService) prune(serverID uint) {
+	var cutoff models.MCPServerLog
+	err := s.db.Where("server_id = ?", serverID).Order("id desc").Offset(maxLogsPerServer).First(&cutoff).Error
+	if err != nil {
+		return
+	}
+	if err := s.db.Where("server_id = ? AND id <= ?", serverID, cutoff.ID).Delete(&models.MCPServerLog{}).Error; err != nil {
+		mcpLog.Warn("清理服务器旧日志失败", "server_id", serverID, "error", err)
+	}
+}
+
+// normalizeLogLevel 校验并规范化日志级别
+func normalizeLogLevel(level string) (mcp.LoggingLevel, error) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	for _, l := range models.ServerLogLevels {
+		if l == level {
+			return mcp.LoggingLevel(level), nil
+		}
+	}
+	return "", validationError("invalid_log_level", "无效的日志级别: %s", level)
+}
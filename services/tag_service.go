@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"desktop-ai-tools/apperrors"
+	"desktop-ai-tools/models"
+)
+
+// 标签限制
+const (
+	maxTagLength   = 50
+	maxTagsPerItem = 20
+)
+
+// tagLinkTables 标签关联表及其外键列
+var tagLinkTables = []struct {
+	table  string
+	column string
+	owner  string // 关联的主表，用于排除已软删除的记录
+}{
+	{"mcp_server_tags", "mcp_server_id", "mcp_servers"},
+	{"mcp_tool_tags", "mcp_tool_id", "mcp_tools"},
+}
+
+// TagService 标签管理服务
+type TagService struct {
+	db *gorm.DB
+}
+
+// NewTagService 创建标签服务实例
+func NewTagService(db *gorm.DB) *TagService {
+	return &TagService{db: db}
+}
+
+// WithContext 返回使用指定上下文的服务副本，上下文中的审计来源会记录到审计日志
+func (s *TagService) WithContext(ctx context.Context) *TagService {
+	return &TagService{db: s.db.WithContext(ctx)}
+}
+
+// List 获取当前工作区使用的标签和未使用的标签，使用数量只统计当前工作区
+func (s *TagService) List(req *models.TagListRequest) ([]models.Tag, error) {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Scopes(tagsInWorkspace(workspaceID)).Order("name asc")
+	if req.Search != "" {
+		query = query.Where("name LIKE ?", "%"+req.Search+"%")
+	}
+
+	var tags []models.Tag
+	if err := query.Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("查询标签失败: %v", err)
+	}
+	if err := s.fillCounts(workspaceID, tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Rename 重命名标签，新名称已被其他标签使用时需要改用合并。
+// 标签同时被其他工作区使用时，只将当前工作区的关联改到新名称的标签
+func (s *TagService) Rename(id uint, req *models.TagRenameRequest) (*models.Tag, error) {
+	names, err := normalizeTagNames([]string{req.Name})
+	if err != nil {
+		return nil, err
+	}
+	if len(names) != 1 || strings.Contains(req.Name, ",") {
+		return nil, validationError("invalid_tag_name", "无效的标签名称: %s", req.Name)
+	}
+
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.get(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Tag{}).Where("LOWER(name) = LOWER(?) AND id != ?", names[0], id).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("检查名称重复失败: %v", err)
+	}
+	if count > 0 {
+		return nil, apperrors.Newf(apperrors.KindConflict, "tag_name_exists", "标签 %s 已存在，请使用合并", names[0])
+	}
+
+	renamedID := id
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return auditTagged(tx, workspaceID, []uint{id}, func() error {
+			shared, err := tagUsedOutside(tx, workspaceID, id)
+			if err != nil {
+				return err
+			}
+			// 只修改大小写时仍是同一个标签，直接重命名
+			if !shared || strings.EqualFold(tag.Name, names[0]) {
+				if err := tx.Model(tag).Update("name", names[0]).Error; err != nil {
+					return fmt.Errorf("重命名标签失败: %v", err)
+				}
+				return nil
+			}
+
+			renamed := models.Tag{Name: names[0]}
+			if err := tx.Create(&renamed).Error; err != nil {
+				return fmt.Errorf("重命名标签失败: %v", err)
+			}
+			renamedID = renamed.ID
+			return moveTagLinks(tx, workspaceID, id, renamed.ID)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.withCounts(workspaceID, renamedID)
+}
+
+// Merge 将当前工作区中的源标签合并到目标标签，目标标签不存在时自动创建
+func (s *TagService) Merge(req *models.TagMergeRequest) (*models.Tag, error) {
+	names, err := normalizeTagNames([]string{req.Target})
+	if err != nil {
+		return nil, err
+	}
+	if len(names) != 1 || strings.Contains(req.Target, ",") {
+		return nil, validationError("invalid_tag_name", "无效的标签名称: %s", req.Target)
+	}
+
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var targetID uint
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var sources []models.Tag
+		if err := tx.Scopes(tagsInWorkspace(workspaceID)).Where("id IN ?", req.SourceIDs).Find(&sources).Error; err != nil {
+			return fmt.Errorf("查询标签失败: %v", err)
+		}
+		if len(sources) != len(uniqueIDs(req.SourceIDs)) {
+			return ErrSourceTagNotFound
+		}
+
+		targets, err := resolveTags(tx, names)
+		if err != nil {
+			return err
+		}
+		targetID = targets[0].ID
+
+		return auditTagged(tx, workspaceID, uniqueIDs(req.SourceIDs), func() error {
+			for _, source := range sources {
+				if source.ID == targetID {
+					continue
+				}
+				if err := moveTagLinks(tx, workspaceID, source.ID, targetID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.withCounts(workspaceID, targetID)
+}
+
+// Delete 移除当前工作区中服务器和工具与标签的关联，标签不再被使用时删除标签
+func (s *TagService) Delete(id uint) error {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return err
+	}
+	if _, err := s.get(workspaceID, id); err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return auditTagged(tx, workspaceID, []uint{id}, func() error {
+			return deleteTag(tx, workspaceID, id)
+		})
+	})
+}
+
+// SetToolTags 设置当前工作区中工具的标签
+func (s *TagService) SetToolTags(toolID uint, names []string) (*models.MCPTool, error) {
+	workspaceID, err := activeWorkspaceID(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var tool models.MCPTool
+	if err := s.db.Scopes(toolsInWorkspace(workspaceID)).First(&tool, toolID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrToolNotFound
+		}
+		return nil, fmt.Errorf("查询工具失败: %v", err)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return replaceTags(tx, &tool, names)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Tags").First(&tool, toolID).Error; err != nil {
+		return nil, fmt.Errorf("查询工具失败: %v", err)
+	}
+	return &tool, nil
+}
+
+// get 按ID查询当前工作区可见的标签
+func (s *TagService) get(workspaceID, id uint) (*models.Tag, error) {
+	var tag models.Tag
+	if err := s.db.Scopes(tagsInWorkspace(workspaceID)).First(&tag, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("查询标签失败: %v", err)
+	}
+	return &tag, nil
+}
+
+// withCounts 查询标签及其在当前工作区的使用数量
+func (s *TagService) withCounts(workspaceID, id uint) (*models.Tag, error) {
+	tag, err := s.get(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	tags := []models.Tag{*tag}
+	if err := s.fillCounts(workspaceID, tags); err != nil {
+		return nil, err
+	}
+	return &tags[0], nil
+}
+
+// fillCounts 统计每个标签关联的指定工作区中服务器和工具的数量，不包含已删除的记录
+func (s *TagService) fillCounts(workspaceID uint, tags []models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	counts := make([]map[uint]int64, len(tagLinkTables))
+	for i, link := range tagLinkTables {
+		var rows []struct {
+			TagID uint
+			Count int64
+		}
+		err := s.db.Table(link.table).
+			Select(link.table + ".tag_id AS tag_id, COUNT(*) AS count").
+			Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.%s AND %s.deleted_at IS NULL", link.owner, link.owner, link.table, link.column, link.owner)).
+			Where(link.table+"."+link.column+" IN (?)", workspaceOwners(s.db, link.owner, workspaceID)).
+			Group(link.table + ".tag_id").
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("统计标签使用数量失败: %v", err)
+		}
+		counts[i] = make(map[uint]int64, len(rows))
+		for _, row := range rows {
+			counts[i][row.TagID] = row.Count
+		}
+	}
+
+	for i := range tags {
+		tags[i].ServerCount = counts[0][tags[i].ID]
+		tags[i].ToolCount = counts[1][tags[i].ID]
+	}
+	return nil
+}
+
+// normalizeTagNames 规范化标签名称：拆分逗号、去除空白、忽略大小写去重
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var result []string
+	for _, name := range names {
+		for _, part := range strings.Split(name, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" || seen[strings.ToLower(part)] {
+				continue
+			}
+			if utf8.RuneCountInString(part) > maxTagLength {
+				return nil, validationError("tag_too_long", "标签 %s 超过%d个字符", part, maxTagLength)
+			}
+			seen[strings.ToLower(part)] = true
+			result = append(result, part)
+		}
+	}
+	if len(result) > maxTagsPerItem {
+		return nil, validationError("too_many_tags", "标签不能超过%d个", maxTagsPerItem)
+	}
+	return result, nil
+}
+
+// resolveTags 按名称查找标签（忽略大小写），不存在时创建
+func resolveTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		var tag models.Tag
+		err := tx.Where("LOWER(name) = LOWER(?)", name).First(&tag).Error
+		if err == gorm.ErrRecordNotFound {
+			tag = models.Tag{Name: name}
+			err = tx.Create(&tag).Error
+		}
+		if err != nil {
+			return nil, fmt.Errorf("保存标签 %s 失败: %v", name, err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// replaceTags 替换服务器或工具的全部标签
+func replaceTags(tx *gorm.DB, owner interface{}, names []string) error {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return err
+	}
+	tags, err := resolveTags(tx, names)
+	if err != nil {
+		return err
+	}
+
+	var serverIDs, toolIDs []uint
+	switch o := owner.(type) {
+	case *models.MCPServer:
+		serverIDs = []uint{o.ID}
+	case *models.MCPTool:
+		toolIDs = []uint{o.ID}
+	}
+	return models.AuditTagChanges(tx, serverIDs, toolIDs, func() error {
+		// Replace 会在移除旧关联之前触发主表的更新钩子，此时的快照不完整，由 AuditTagChanges 统一审计
+		if err := tx.Session(&gorm.Session{SkipHooks: true}).Model(owner).Association("Tags").Replace(models.TagList(tags)); err != nil {
+			return fmt.Errorf("更新标签失败: %v", err)
+		}
+		return nil
+	})
+}
+
+// auditTagged 审计对指定工作区中关联了指定标签的服务器和工具的变更
+func auditTagged(tx *gorm.DB, workspaceID uint, tagIDs []uint, change func() error) error {
+	owners := make([][]uint, len(tagLinkTables))
+	for i, link := range tagLinkTables {
+		if err := tx.Table(link.table).Where("tag_id IN ?", tagIDs).Where(link.column+" IN (?)", workspaceOwners(tx, link.owner, workspaceID)).
+			Distinct().Pluck(link.column, &owners[i]).Error; err != nil {
+			return fmt.Errorf("查询标签关联失败: %v", err)
+		}
+	}
+	return models.AuditTagChanges(tx, owners[0], owners[1], change)
+}
+
+// moveTagLinks 将指定工作区中服务器和工具与源标签的关联改为目标标签
+func moveTagLinks(tx *gorm.DB, workspaceID, from, to uint) error {
+	for _, link := range tagLinkTables {
+		// 关联表以 (外键, tag_id) 为主键，已关联目标标签的记录会被忽略
+		if err := tx.Exec(fmt.Sprintf("INSERT OR IGNORE INTO %s (%s, tag_id) SELECT %s, ? FROM %s WHERE tag_id = ? AND %s IN (?)",
+			link.table, link.column, link.column, link.table, link.column), to, from, workspaceOwners(tx, link.owner, workspaceID)).Error; err != nil {
+			return fmt.Errorf("合并标签失败: %v", err)
+		}
+	}
+	return deleteTag(tx, workspaceID, from)
+}
+
+// deleteTag 删除指定工作区中服务器和工具与标签的关联，标签不再被使用时删除标签
+func deleteTag(tx *gorm.DB, workspaceID, id uint) error {
+	var remaining int64
+	for _, link := range tagLinkTables {
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE tag_id = ? AND %s IN (?)", link.table, link.column), id, workspaceOwners(tx, link.owner, workspaceID)).Error; err != nil {
+			return fmt.Errorf("删除标签关联失败: %v", err)
+		}
+		var count int64
+		if err := tx.Table(link.table).Where("tag_id = ?", id).Count(&count).Error; err != nil {
+			return fmt.Errorf("查询标签关联失败: %v", err)
+		}
+		remaining += count
+	}
+	if remaining > 0 {
+		return nil
+	}
+	if err := tx.Delete(&models.Tag{}, id).Error; err != nil {
+		return fmt.Errorf("删除标签失败: %v", err)
+	}
+	return nil
+}
+
+// tagUsedOutside 判断标签是否被其他工作区的服务器或工具使用
+func tagUsedOutside(tx *gorm.DB, workspaceID, id uint) (bool, error) {
+	for _, link := range tagLinkTables {
+		var count int64
+		if err := tx.Table(link.table).Where("tag_id = ?", id).Where(link.column+" NOT IN (?)", workspaceOwners(tx, link.owner, workspaceID)).Count(&count).Error; err != nil {
+			return false, fmt.Errorf("查询标签关联失败: %v", err)
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// workspaceOwners 指定工作区中服务器或工具ID的子查询，包含已软删除的记录
+func workspaceOwners(db *gorm.DB, owner string, workspaceID uint) *gorm.DB {
+	servers := db.Session(&gorm.Session{NewDB: true}).Table("mcp_servers").Select("id").Where("workspace_id = ?", workspaceID)
+	if owner == "mcp_servers" {
+		return servers
+	}
+	return db.Session(&gorm.Session{NewDB: true}).Table("mcp_tools").Select("id").Where("server_id IN (?)", servers)
+}
+
+// tagsInWorkspace 将标签查询限定为指定工作区中服务器或工具使用的标签，以及未被任何服务器或工具使用的标签
+func tagsInWorkspace(workspaceID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		var used, linked []interface{}
+		for _, link := range tagLinkTables {
+			links := db.Session(&gorm.Session{NewDB: true}).Table(link.table).Select("tag_id")
+			used = append(used, links.Where(link.column+" IN (?)", workspaceOwners(db, link.owner, workspaceID)))
+			linked = append(linked, db.Session(&gorm.Session{NewDB: true}).Table(link.table).Select("tag_id"))
+		}
+		return db.Where("tags.id IN (?) OR tags.id IN (?) OR (tags.id NOT IN (?) AND tags.id NOT IN (?))", used[0], used[1], linked[0], linked[1])
+	}
+}
+
+// filterByTags 按标签过滤服务器或工具，mode 为 and 时要求包含全部标签
+func filterByTags(query *gorm.DB, ownerTable string, names []string, mode string) *gorm.DB {
+	var lowered []string
+	for _, name := range names {
+		for _, part := range strings.Split(name, ",") {
+			if part = strings.ToLower(strings.TrimSpace(part)); part != "" && !containsString(lowered, part) {
+				lowered = append(lowered, part)
+			}
+		}
+	}
+	if len(lowered) == 0 {
+		return query
+	}
+
+	var link string
+	var column string
+	for _, l := range tagLinkTables {
+		if l.owner == ownerTable {
+			link, column = l.table, l.column
+		}
+	}
+
+	sub := query.Session(&gorm.Session{NewDB: true}).
+		Table(link).
+		Select(link+"."+column).
+		Joins("JOIN tags ON tags.id = "+link+".tag_id").
+		Where("LOWER(tags.name) IN ?", lowered)
+	if mode == "and" {
+		sub = sub.Group(link+"."+column).Having("COUNT(DISTINCT tags.id) = ?", len(lowered))
+	}
+	return query.Where(ownerTable+".id IN (?)", sub)
+}
+
+// uniqueIDs 去除重复的ID
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"desktop-ai-tools/models"
+)
+
+// TestServerTagFilter 测试服务器标签的精确匹配和 AND/OR 过滤
+func TestServerTagFilter(t *testing.T) {
+	db := newTestDB(t)
+	servers := &MCPServerService{db: db}
+
+	for _, item := range []struct {
+		name string
+		tags []string
+	}{
+		{"a", []string{"dev", "db"}},
+		{"b", []string{"devops"}},
+		{"c", []string{"Dev", "web", "dev"}},
+	} {
+		if _, err := servers.Create(&models.MCPServerCreateRequest{Name: item.name, URL: "https://example.com/" + item.name, AuthType: "none", Tags: item.tags}); err != nil {
+			t.Fatalf("创建服务器失败: %v", err)
+		}
+	}
+
+	list := func(req models.MCPServerListRequest) string {
+		req.Page, req.Size, req.OrderBy, req.OrderDir = 1, 10, "name", "asc"
+		if req.TagMode == "" {
+			req.TagMode = "or"
+		}
+		resp, err := servers.GetList(&req)
+		if err != nil {
+			t.Fatalf("查询服务器失败: %v", err)
+		}
+		var names []string
+		for _, server := range resp.Servers {
+			names = append(names, server.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := list(models.MCPServerListRequest{Tags: []string{"dev"}}); got != "a,c" {
+		t.Fatalf("dev 不应匹配 devops，实际: %s", got)
+	}
+	if got := list(models.MCPServerListRequest{Tags: []string{"db,web"}}); got != "a,c" {
+		t.Fatalf("OR 过滤结果错误: %s", got)
+	}
+	if got := list(models.MCPServerListRequest{Tags: []string{"dev", "web"}, TagMode: "and"}); got != "c" {
+		t.Fatalf("AND 过滤结果错误: %s", got)
+	}
+	if got := list(models.MCPServerListRequest{Search: "dev"}); got != "a,c" {
+		t.Fatalf("搜索应按标签名称精确匹配，实际: %s", got)
+	}
+
+	tags, err := NewTagService(db).List(&models.TagListRequest{})
+	if err != nil {
+		t.Fatalf("查询标签失败: %v", err)
+	}
+	counts := map[string]int64{}
+	for _, tag := range tags {
+		counts[tag.Name] = tag.ServerCount
+	}
+	if len(tags) != 4 || counts["dev"] != 2 || counts["devops"] != 1 {
+		t.Fatalf("标签数量统计错误: %v", counts)
+	}
+}
+
+// TestRenameAndMergeTags 测试标签重命名与合并
+func TestRenameAndMergeTags(t *testing.T) {
+	db := newTestDB(t)
+	servers := &MCPServerService{db: db}
+	service := NewTagService(db)
+
+	a, err := servers.Create(&models.MCPServerCreateRequest{Name: "a", URL: "https://example.com/a", AuthType: "none", Tags: []string{"prod", "production"}})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	if _, err := servers.Create(&models.MCPServerCreateRequest{Name: "b", URL: "https://example.com/b", AuthType: "none", Tags: []string{"production"}}); err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	ids := map[string]uint{}
+	for _, tag := range a.Tags {
+		ids[tag.Name] = tag.ID
+	}
+
+	if _, err := service.Rename(ids["prod"], &models.TagRenameRequest{Name: "Production"}); err == nil {
+		t.Fatalf("重命名为已存在的标签应失败")
+	}
+
+	merged, err := service.Merge(&models.TagMergeRequest{SourceIDs: []uint{ids["prod"], ids["production"]}, Target: "live"})
+	if err != nil {
+		t.Fatalf("合并标签失败: %v", err)
+	}
+	if merged.Name != "live" || merged.ServerCount != 2 {
+		t.Fatalf("合并结果错误: %+v", merged)
+	}
+
+	server, err := servers.GetByID(a.ID)
+	if err != nil {
+		t.Fatalf("查询服务器失败: %v", err)
+	}
+	if got := strings.Join(server.GetTagList(), ","); got != "live" {
+		t.Fatalf("合并后服务器标签错误: %s", got)
+	}
+
+	renamed, err := service.Rename(merged.ID, &models.TagRenameRequest{Name: "prod"})
+	if err != nil || renamed.Name != "prod" {
+		t.Fatalf("重命名标签失败: %+v, %v", renamed, err)
+	}
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"desktop-ai-tools/models"
+	"desktop-ai-tools/utils"
+)
+
+// ToolResultCacheConfig 工具结果缓存配置
+type ToolResultCacheConfig struct {
+	// 默认缓存有效期（工具未单独设置TTL时使用）
+	DefaultTTL time.Duration
+	// 最大缓存条目数
+	MaxEntries int
+	// 单条结果的最大字节数，超过则不缓存
+	MaxEntryBytes int
+	// 缓存总字节数上限
+	MaxTotalBytes int
+}
+
+// DefaultToolResultCacheConfig 默认工具结果缓存配置
+func DefaultToolResultCacheConfig() ToolResultCacheConfig {
+	return ToolResultCacheConfig{
+		DefaultTTL:    5 * time.Minute,
+		MaxEntries:    500,
+		MaxEntryBytes: 1 << 20,  // 1MB
+		MaxTotalBytes: 32 << 20, // 32MB
+	}
+}
+
+// cacheEntry 缓存条目
+type cacheEntry struct {
+	key       string
+	serverID  uint
+	toolName  string
+	arguments string
+	result    []byte // 序列化后的结果，命中时解码为新的副本，调用方修改返回值不影响缓存
+	size      int
+	hits      int64
+	cachedAt  time.Time
+	expiresAt time.Time
+}
+
+// cacheCounter 按服务器统计的命中数据
+type cacheCounter struct {
+	hits   int64
+	misses int64
+}
+
+// ToolResultCache 工具调用结果缓存（LRU + TTL）
+type ToolResultCache struct {
+	mu         sync.Mutex
+	config     ToolResultCacheConfig
+	entries    map[string]*list.Element
+	lru        *list.List
+	totalBytes int
+	counters   map[uint]*cacheCounter
+}
+
+// NewToolResultCache 创建工具结果缓存
+func NewToolResultCache(config ToolResultCacheConfig) *ToolResultCache {
+	return &ToolResultCache{
+		config:   config,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		counters: make(map[uint]*cacheCounter),
+	}
+}
+
+// BuildKey 根据服务器、工具名称和规范化后的参数生成缓存键
+func (c *ToolResultCache) BuildKey(serverID uint, toolName string, arguments map[string]interface{}) (string, string, error) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	canonical, err := utils.CanonicalJSON(arguments)
+	if err != nil {
+		return "", "", fmt.Errorf("规范化调用参数失败: %v", err)
+	}
+	return fmt.Sprintf("%d:%s:%s", serverID, toolName, canonical), canonical, nil
+}
+
+// Get 获取缓存结果的副本，过期条目会被移除
+func (c *ToolResultCache) Get(serverID uint, key string) (*mcp.CallToolResult, time.Time, bool) {
+	data, cachedAt, ok := c.lookup(serverID, key)
+	if !ok {
+		return nil, time.Time{}, false
+	}
+
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, time.Time{}, false
+	}
+	return &result, cachedAt, true
+}
+
+// lookup 查找未过期的缓存条目并更新命中统计
+func (c *ToolResultCache) lookup(serverID uint, key string) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter := c.counter(serverID)
+	elem, ok := c.entries[key]
+	if !ok {
+		counter.misses++
+		return nil, time.Time{}, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		counter.misses++
+		return nil, time.Time{}, false
+	}
+
+	entry.hits++
+	counter.hits++
+	c.lru.MoveToFront(elem)
+	return entry.result, entry.cachedAt, true
+}
+
+// Set 写入缓存结果的序列化副本，ttl为0时使用默认有效期
+func (c *ToolResultCache) Set(serverID uint, toolName, key, arguments string, result *mcp.CallToolResult, ttl time.Duration) {
+	if result == nil || result.IsError {
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	size := len(data)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.config.MaxEntryBytes > 0 && size > c.config.MaxEntryBytes {
+		return
+	}
+	if ttl <= 0 {
+		ttl = c.config.DefaultTTL
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+
+	now := time.Now()
+	entry := &cacheEntry{
+		key:       key,
+		serverID:  serverID,
+		toolName:  toolName,
+		arguments: arguments,
+		result:    data,
+		size:      size,
+		cachedAt:  now,
+		expiresAt: now.Add(ttl),
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	c.totalBytes += size
+	c.evict()
+}
+
+// SetConfig 更新缓存配置，容量缩小时立即淘汰多余的条目
+func (c *ToolResultCache) SetConfig(config ToolResultCacheConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config = config
+	c.evict()
+}
+
+// evict 超出容量时淘汰最久未使用的条目，调用方需持有锁
+func (c *ToolResultCache) evict() {
+	for c.lru.Len() > 0 &&
+		((c.config.MaxEntries > 0 && c.lru.Len() > c.config.MaxEntries) ||
+			(c.config.MaxTotalBytes > 0 && c.totalBytes > c.config.MaxTotalBytes)) {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// PurgeServer 清除指定服务器的缓存，返回清除的条目数
+func (c *ToolResultCache) PurgeServer(serverID uint) int {
+	return c.purge(func(entry *cacheEntry) bool {
+		return entry.serverID == serverID
+	})
+}
+
+// PurgeTool 清除指定工具的缓存，返回清除的条目数
+func (c *ToolResultCache) PurgeTool(serverID uint, toolName string) int {
+	return c.purge(func(entry *cacheEntry) bool {
+		return entry.serverID == serverID && entry.toolName == toolName
+	})
+}
+
+// Stats 获取指定服务器的缓存统计和条目列表
+func (c *ToolResultCache) Stats(serverID uint) *models.ToolCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	stats := &models.ToolCacheStats{
+		ServerID: serverID,
+		Entries:  []models.ToolCacheEntry{},
+	}
+	if counter, ok := c.counters[serverID]; ok {
+		stats.Hits = counter.hits
+		stats.Misses = counter.misses
+	}
+
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*cacheEntry)
+		if entry.serverID != serverID || now.After(entry.expiresAt) {
+			continue
+		}
+		stats.EntryCount++
+		stats.SizeBytes += entry.size
+		stats.Entries = append(stats.Entries, models.ToolCacheEntry{
+			ToolName:  entry.toolName,
+			Arguments: entry.arguments,
+			SizeBytes: entry.size,
+			Hits:      entry.hits,
+			CachedAt:  entry.cachedAt,
+			ExpiresAt: entry.expiresAt,
+		})
+	}
+
+	return stats
+}
+
+// purge 按条件清除缓存条目
+func (c *ToolResultCache) purge(match func(entry *cacheEntry) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*cacheEntry)) {
+			c.removeElement(elem)
+			removed++
+		}
+		elem = next
+	}
+	return removed
+}
+
+// removeElement 移除缓存条目（调用方需持有锁）
+func (c *ToolResultCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, entry.key)
+	c.totalBytes -= entry.size
+}
+
+// counter 获取服务器的统计计数器（调用方需持有锁）
+func (c *ToolResultCache) counter(serverID uint) *cacheCounter {
+	counter, ok := c.counters[serverID]
+	if !ok {
+		counter = &cacheCounter{}
+		c.counters[serverID] = counter
+	}
+	return counter
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"desktop-ai-tools/models"
+)
+
+// cacheArguments 将JSON字符串解码为工具调用参数
+func cacheArguments(t *testing.T, data string) map[string]interface{} {
+	t.Helper()
+
+	var arguments map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &arguments); err != nil {
+		t.Fatalf("解析参数失败: %v", err)
+	}
+	return arguments
+}
+
+// setCached 以规范化的参数写入一条缓存
+func setCached(t *testing.T, cache *ToolResultCache, serverID uint, toolName, arguments string, ttl time.Duration) string {
+	t.Helper()
+
+	key, canonical, err := cache.BuildKey(serverID, toolName, cacheArguments(t, arguments))
+	if err != nil {
+		t.Fatalf("生成缓存键失败: %v", err)
+	}
+	cache.Set(serverID, toolName, key, canonical, mcp.NewToolResultText(arguments), ttl)
+	return key
+}
+
+// TestToolResultCacheKey 测试参数的键顺序不影响缓存键，参数值或工具不同时缓存键不同
+func TestToolResultCacheKey(t *testing.T) {
+	cache := NewToolResultCache(DefaultToolResultCacheConfig())
+
+	key, canonical, err := cache.BuildKey(1, "search", cacheArguments(t, `{"query":"go","options":{"limit":10,"lang":"zh"}}`))
+	if err != nil {
+		t.Fatalf("生成缓存键失败: %v", err)
+	}
+	if canonical != `{"options":{"lang":"zh","limit":10},"query":"go"}` {
+		t.Fatalf("规范化参数不正确: %s", canonical)
+	}
+	cache.Set(1, "search", key, canonical, mcp.NewToolResultText("ok"), 0)
+
+	reordered, _, err := cache.BuildKey(1, "search", cacheArguments(t, `{"options":{"lang":"zh","limit":10},"query":"go"}`))
+	if err != nil {
+		t.Fatalf("生成缓存键失败: %v", err)
+	}
+	if _, _, ok := cache.Get(1, reordered); !ok {
+		t.Fatalf("键顺序不同的参数应命中同一条缓存: %s != %s", reordered, key)
+	}
+
+	for _, other := range []struct {
+		serverID  uint
+		tool      string
+		arguments string
+	}{
+		{1, "search", `{"query":"go","options":{"limit":20,"lang":"zh"}}`},
+		{1, "fetch", `{"query":"go","options":{"limit":10,"lang":"zh"}}`},
+		{2, "search", `{"query":"go","options":{"limit":10,"lang":"zh"}}`},
+	} {
+		otherKey, _, err := cache.BuildKey(other.serverID, other.tool, cacheArguments(t, other.arguments))
+		if err != nil {
+			t.Fatalf("生成缓存键失败: %v", err)
+		}
+		if otherKey == key {
+			t.Fatalf("服务器、工具或参数不同时缓存键应不同: %s", otherKey)
+		}
+	}
+
+	empty, _, err := cache.BuildKey(1, "search", nil)
+	if err != nil || empty != "1:search:{}" {
+		t.Fatalf("无参数时应按空对象生成缓存键: %s %v", empty, err)
+	}
+}
+
+// TestToolResultCacheExpiry 测试过期条目不再命中，且不出现在统计中
+func TestToolResultCacheExpiry(t *testing.T) {
+	cache := NewToolResultCache(DefaultToolResultCacheConfig())
+	short := setCached(t, cache, 1, "now", `{"a":1}`, 20*time.Millisecond)
+	long := setCached(t, cache, 1, "now", `{"a":2}`, time.Minute)
+
+	if _, _, ok := cache.Get(1, short); !ok {
+		t.Fatal("未过期的条目应命中")
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	if _, _, ok := cache.Get(1, short); ok {
+		t.Fatal("过期的条目不应命中")
+	}
+	if _, _, ok := cache.Get(1, long); !ok {
+		t.Fatal("未过期的条目应命中")
+	}
+
+	stats := cache.Stats(1)
+	if stats.EntryCount != 1 || stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("缓存统计不正确: %+v", stats)
+	}
+}
+
+// TestToolResultCacheEviction 测试超出最大条目数时淘汰最久未使用的条目
+func TestToolResultCacheEviction(t *testing.T) {
+	config := DefaultToolResultCacheConfig()
+	config.MaxEntries = 2
+	cache := NewToolResultCache(config)
+
+	first := setCached(t, cache, 1, "tool", `{"n":1}`, 0)
+	second := setCached(t, cache, 1, "tool", `{"n":2}`, 0)
+	// 访问第一条，使第二条成为最久未使用的条目
+	if _, _, ok := cache.Get(1, first); !ok {
+		t.Fatal("第一条缓存应命中")
+	}
+	third := setCached(t, cache, 1, "tool", `{"n":3}`, 0)
+
+	if _, _, ok := cache.Get(1, second); ok {
+		t.Fatal("最久未使用的条目应被淘汰")
+	}
+	for _, key := range []string{first, third} {
+		if _, _, ok := cache.Get(1, key); !ok {
+			t.Fatalf("条目不应被淘汰: %s", key)
+		}
+	}
+
+	// 缩小容量时立即淘汰
+	config.MaxEntries = 1
+	cache.SetConfig(config)
+	if stats := cache.Stats(1); stats.EntryCount != 1 || stats.Entries[0].Arguments != `{"n":3}` {
+		t.Fatalf("缩小容量后应只保留最近使用的条目: %+v", stats.Entries)
+	}
+}
+
+// TestToolResultCachePurge 测试按服务器和按工具清除缓存，不影响其他服务器
+func TestToolResultCachePurge(t *testing.T) {
+	cache := NewToolResultCache(DefaultToolResultCacheConfig())
+	setCached(t, cache, 1, "a", `{"n":1}`, 0)
+	setCached(t, cache, 1, "a", `{"n":2}`, 0)
+	setCached(t, cache, 1, "b", `{"n":1}`, 0)
+	other := setCached(t, cache, 2, "a", `{"n":1}`, 0)
+
+	if removed := cache.PurgeTool(1, "a"); removed != 2 {
+		t.Fatalf("应清除工具的2条缓存，实际 %d 条", removed)
+	}
+	if stats := cache.Stats(1); stats.EntryCount != 1 || stats.Entries[0].ToolName != "b" {
+		t.Fatalf("应只保留其他工具的缓存: %+v", stats.Entries)
+	}
+
+	if removed := cache.PurgeServer(1); removed != 1 {
+		t.Fatalf("应清除服务器剩余的1条缓存，实际 %d 条", removed)
+	}
+	if stats := cache.Stats(1); stats.EntryCount != 0 {
+		t.Fatalf("服务器的缓存应全部清除: %+v", stats.Entries)
+	}
+	if _, _, ok := cache.Get(2, other); !ok {
+		t.Fatal("清除缓存不应影响其他服务器")
+	}
+}
+
+// TestToolResultCacheCopy 测试修改写入的结果或命中返回的结果不影响之后的命中
+func TestToolResultCacheCopy(t *testing.T) {
+	cache := NewToolResultCache(DefaultToolResultCacheConfig())
+	key, canonical, err := cache.BuildKey(1, "tool", nil)
+	if err != nil {
+		t.Fatalf("生成缓存键失败: %v", err)
+	}
+	stored := mcp.NewToolResultText("original")
+	cache.Set(1, "tool", key, canonical, stored, 0)
+	stored.Content = append(stored.Content, mcp.NewTextContent("after set"))
+
+	hit, _, ok := cache.Get(1, key)
+	if !ok {
+		t.Fatal("缓存应命中")
+	}
+	hit.Content[0] = mcp.NewTextContent("changed")
+	hit.Content = append(hit.Content, mcp.NewTextContent("after get"))
+	hit.StructuredContent = map[string]interface{}{"x": 1}
+
+	again, _, ok := cache.Get(1, key)
+	if !ok {
+		t.Fatal("缓存应命中")
+	}
+	if len(again.Content) != 1 || again.Content[0].(mcp.TextContent).Text != "original" || again.StructuredContent != nil {
+		t.Fatalf("缓存结果被调用方修改: %+v", again)
+	}
+}
+
+// TestCallToolBypassCache 测试可缓存工具的第二次调用使用缓存，bypass_cache 时重新请求服务器并刷新缓存
+func TestCallToolBypassCache(t *testing.T) {
+	var calls int32
+	mcpServer := server.NewMCPServer("cache-test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("counter"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(fmt.Sprint(atomic.AddInt32(&calls, 1))), nil
+	})
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(httpServer.Close)
+
+	db := newTestDB(t)
+	workspaceID, err := activeWorkspaceID(db)
+	if err != nil {
+		t.Fatalf("获取当前工作区失败: %v", err)
+	}
+	enabled := true
+	tool := models.MCPTool{
+		Name:         "counter",
+		IsEnabled:    true,
+		CacheEnabled: &enabled,
+		Server:       models.MCPServer{WorkspaceID: workspaceID, Name: "cache", URL: httpServer.URL + "/mcp", Transport: "streamable_http", IsEnabled: true},
+	}
+	if err := db.Create(&tool).Error; err != nil {
+		t.Fatalf("创建工具失败: %v", err)
+	}
+	service := NewMCPToolService(db, NewMCPClientFactory(nil, nil, nil, nil, nil, nil))
+
+	// 依次调用并检查是否使用缓存和返回的计数
+	call := func(bypass bool, wantCached bool, want string) {
+		t.Helper()
+		response, err := service.CallTool(tool.ID, &models.MCPToolCallRequest{Arguments: map[string]interface{}{"x": 1}, BypassCache: bypass})
+		if err != nil {
+			t.Fatalf("调用工具失败: %v", err)
+		}
+		text := response.Result.Content[0].(mcp.TextContent).Text
+		if response.Cached != wantCached || text != want {
+			t.Fatalf("调用结果不正确: cached=%v text=%s，期望 cached=%v text=%s", response.Cached, text, wantCached, want)
+		}
+	}
+
+	call(false, false, "1")
+	call(false, true, "1")
+	call(true, false, "2")
+	// 跳过缓存的调用结果会写入缓存
+	call(false, true, "2")
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("服务器应只被调用2次，实际 %d 次", got)
+	}
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"desktop-ai-tools/models"
+)
+
+const (
+	// defaultTrafficCapacity 每个服务器默认保留的最大流量记录数
+	defaultTrafficCapacity = 1000
+	// maxTrafficMessageSize 单条消息保留的最大字节数，超过后截断
+	maxTrafficMessageSize = 64 << 10
+)
+
+// trafficBuffer 单个服务器的环形缓冲区，写满后覆盖最早的记录
+type trafficBuffer struct {
+	entries []models.MCPTrafficEntry
+	next    int // 下一条记录的写入位置
+	dropped uint64
+}
+
+func (b *trafficBuffer) add(entry models.MCPTrafficEntry, capacity int) {
+	if len(b.entries) < capacity {
+		b.entries = append(b.entries, entry)
+		return
+	}
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % capacity
+	b.dropped++
+}
+
+// each 按时间从旧到新遍历记录
+func (b *trafficBuffer) each(fn func(*models.MCPTrafficEntry)) {
+	for i := range b.entries {
+		fn(&b.entries[(b.next+i)%len(b.entries)])
+	}
+}
+
+// trafficSubscriber 流量记录实时订阅者
+type trafficSubscriber struct {
+	filter models.MCPTrafficListRequest
+	ch     chan models.MCPTrafficEntry
+}
+
+// TrafficService 抓取MCP会话中收发的JSON-RPC消息，类似 MCP Inspector 的历史面板
+// 抓取需按服务器手动开启，记录只保存在内存中
+type TrafficService struct {
+	mu          sync.Mutex
+	capacity    int
+	lastID      uint64
+	sessions    uint64
+	enabled     map[uint]bool
+	buffers     map[uint]*trafficBuffer
+	subscribers map[uint]map[*trafficSubscriber]struct{}
+}
+
+// NewTrafficService 创建流量抓取服务，capacity 为每个服务器保留的最大记录数
+func NewTrafficService(capacity int) *TrafficService {
+	if capacity <= 0 {
+		capacity = defaultTrafficCapacity
+	}
+	return &TrafficService{
+		capacity:    capacity,
+		enabled:     make(map[uint]bool),
+		buffers:     make(map[uint]*trafficBuffer),
+		subscribers: make(map[uint]map[*trafficSubscriber]struct{}),
+	}
+}
+
+// SetCapture 开启或关闭服务器的流量抓取，已连接的会话立即生效，已抓取的记录保留到清空为止
+func (s *TrafficService) SetCapture(serverID uint, enabled bool) *models.MCPTrafficStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if enabled {
+		s.enabled[serverID] = true
+	} else {
+		delete(s.enabled, serverID)
+	}
+	mcpLog.Info("设置服务器流量抓取", "server_id", serverID, "enabled", enabled)
+	status := s.status(serverID)
+	return &status
+}
+
+// Capturing 判断服务器是否开启了流量抓取
+func (s *TrafficService) Capturing(serverID uint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enabled[serverID]
+}
+
+// Status 获取服务器的流量抓取状态
+func (s *TrafficService) Status(serverID uint) *models.MCPTrafficStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status(serverID)
+	return &status
+}
+
+func (s *TrafficService) status(serverID uint) models.MCPTrafficStatus {
+	status := models.MCPTrafficStatus{
+		ServerID: serverID,
+		Enabled:  s.enabled[serverID],
+		Capacity: s.capacity,
+	}
+	if buffer := s.buffers[serverID]; buffer != nil {
+		status.Count = len(buffer.entries)
+		status.Dropped = buffer.dropped
+	}
+	return status
+}
+
+// RecorderFor 为服务器的一个新连接创建记录函数，关闭抓取后记录函数不再保存消息
+func (s *TrafficService) RecorderFor(serverID uint) func(models.MCPTrafficEntry) {
+	s.mu.Lock()
+	s.sessions++
+	session := s.sessions
+	s.mu.Unlock()
+
+	return func(entry models.MCPTrafficEntry) {
+		entry.ServerID = serverID
+		entry.Session = session
+		s.Record(entry)
+	}
+}
+
+// Record 保存一条流量记录并推送给订阅者，未开启抓取的服务器忽略
+func (s *TrafficService) Record(entry models.MCPTrafficEntry) {
+	if len(entry.Message) > maxTrafficMessageSize {
+		// 截断后不再是合法的JSON，以字符串形式保留开头部分
+		truncated, _ := json.Marshal(string(entry.Message[:maxTrafficMessageSize]))
+		entry.Message = truncated
+		entry.Truncated = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.enabled[entry.ServerID] {
+		return
+	}
+	s.lastID++
+	entry.ID = s.lastID
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	buffer := s.buffers[entry.ServerID]
+	if buffer == nil {
+		buffer = &trafficBuffer{}
+		s.buffers[entry.ServerID] = buffer
+	}
+	buffer.add(entry, s.capacity)
+
+	for sub := range s.subscribers[entry.ServerID] {
+		if !sub.filter.Match(&entry) {
+			continue
+		}
+		// 订阅者处理不过来时丢弃，避免阻塞MCP连接
+		select {
+		case sub.ch <- entry:
+		default:
+		}
+	}
+}
+
+// List 查询服务器的流量记录，按时间从旧到新返回满足条件的最近 limit 条
+func (s *TrafficService) List(serverID uint, req *models.MCPTrafficListRequest) *models.MCPTrafficListResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := &models.MCPTrafficListResponse{
+		MCPTrafficStatus: s.status(serverID),
+		Entries:          []models.MCPTrafficEntry{},
+	}
+	buffer := s.buffers[serverID]
+	if buffer == nil {
+		return response
+	}
+	buffer.each(func(entry *models.MCPTrafficEntry) {
+		if !req.Match(entry) {
+			return
+		}
+		response.Total++
+		response.Entries = append(response.Entries, *entry)
+	})
+	if req.Limit > 0 && len(response.Entries) > req.Limit {
+		response.Entries = response.Entries[len(response.Entries)-req.Limit:]
+	}
+	return response
+}
+
+// Export 以JSONL格式导出服务器满足条件的全部流量记录，每行一条，按时间从旧到新排列
+func (s *TrafficService) Export(serverID uint, req *models.MCPTrafficListRequest, w io.Writer) error {
+	filter := *req
+	filter.Limit = 0
+	entries := s.List(serverID, &filter).Entries
+
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	for i := range entries {
+		if err := encoder.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("导出流量记录失败: %v", err)
+		}
+	}
+	return out.Flush()
+}
+
+// Clear 清空服务器的流量记录，返回删除的条数
+func (s *TrafficService) Clear(serverID uint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buffer := s.buffers[serverID]
+	if buffer == nil {
+		return 0
+	}
+	delete(s.buffers, serverID)
+	return len(buffer.entries)
+}
+
+// Subscribe 订阅服务器新的流量记录，返回的取消函数必须调用以释放资源
+func (s *TrafficService) Subscribe(serverID uint, filter models.MCPTrafficListRequest) (<-chan models.MCPTrafficEntry, func()) {
+	sub := &trafficSubscriber{filter: filter, ch: make(chan models.MCPTrafficEntry, 100)}
+
+	s.mu.Lock()
+	if s.subscribers[serverID] == nil {
+		s.subscribers[serverID] = make(map[*trafficSubscriber]struct{})
+	}
+	s.subscribers[serverID][sub] = struct{}{}
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers[serverID], sub)
+		if len(s.subscribers[serverID]) == 0 {
+			delete(s.subscribers, serverID)
+		}
+	}
+	return sub.ch, cancel
+}
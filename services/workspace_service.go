@@ -0,0 +1,318 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"desktop-ai-tools/models"
+)
+
+// WorkspaceService 工作区管理服务
+type WorkspaceService struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	listeners []func(workspace *models.Workspace)
+}
+
+// NewWorkspaceService 创建工作区服务实例
+func NewWorkspaceService(db *gorm.DB) *WorkspaceService {
+	return &WorkspaceService{db: db}
+}
+
+// OnSwitch 注册切换工作区后的回调
+func (s *WorkspaceService) OnSwitch(fn func(workspace *models.Workspace)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// List 获取所有工作区及其服务器数量
+func (s *WorkspaceService) List() ([]models.Workspace, error) {
+	var workspaces []models.Workspace
+	if err := s.db.Order("id asc").Find(&workspaces).Error; err != nil {
+		return nil, fmt.Errorf("查询工作区失败: %v", err)
+	}
+
+	var rows []struct {
+		WorkspaceID uint
+		Count       int64
+	}
+	if err := s.db.Model(&models.MCPServer{}).Select("workspace_id, COUNT(*) AS count").Group("workspace_id").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("统计工作区服务器数量失败: %v", err)
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.WorkspaceID] = row.Count
+	}
+	for i := range workspaces {
+		workspaces[i].ServerCount = counts[workspaces[i].ID]
+	}
+	return workspaces, nil
+}
+
+// Get 根据ID获取工作区
+func (s *WorkspaceService) Get(id uint) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := s.db.First(&workspace, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, fmt.Errorf("查询工作区失败: %v", err)
+	}
+	return &workspace, nil
+}
+
+// Active 获取当前激活的工作区
+func (s *WorkspaceService) Active() (*models.Workspace, error) {
+	return activeWorkspace(s.db)
+}
+
+// Create 创建工作区
+func (s *WorkspaceService) Create(req *models.WorkspaceCreateRequest) (*models.Workspace, error) {
+	if err := s.checkName(req.Name, 0); err != nil {
+		return nil, err
+	}
+
+	workspace := &models.Workspace{
+		Name:           req.Name,
+		Description:    req.Description,
+		SamplingPolicy: req.SamplingPolicy,
+	}
+	if err := s.db.Create(workspace).Error; err != nil {
+		return nil, fmt.Errorf("创建工作区失败: %v", err)
+	}
+	return workspace, nil
+}
+
+// Update 更新工作区名称、描述和工作区级配置
+func (s *WorkspaceService) Update(id uint, req *models.WorkspaceUpdateRequest) (*models.Workspace, error) {
+	workspace, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkName(req.Name, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(workspace).Updates(map[string]interface{}{
+		"name":            req.Name,
+		"description":     req.Description,
+		"sampling_policy": req.SamplingPolicy,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("更新工作区失败: %v", err)
+	}
+	return s.Get(id)
+}
+
+// Delete 删除工作区及其中的服务器和工具，不能删除当前激活的工作区
+func (s *WorkspaceService) Delete(id uint) error {
+	workspace, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if workspace.IsActive {
+		return ErrWorkspaceActive
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		servers := tx.Model(&models.MCPServer{}).Select("id").Where("workspace_id = ?", id)
+		if err := tx.Where("server_id IN (?)", servers).Delete(&models.MCPTool{}).Error; err != nil {
+			return fmt.Errorf("删除工作区工具失败: %v", err)
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.WorkspaceRoot{}).Error; err != nil {
+			return fmt.Errorf("删除工作区根目录失败: %v", err)
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.MCPServer{}).Error; err != nil {
+			return fmt.Errorf("删除工作区服务器失败: %v", err)
+		}
+		if err := tx.Delete(workspace).Error; err != nil {
+			return fmt.Errorf("删除工作区失败: %v", err)
+		}
+		return nil
+	})
+}
+
+// Switch 切换当前激活的工作区
+func (s *WorkspaceService) Switch(id uint) (*models.Workspace, error) {
+	if _, err := s.Get(id); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Workspace{}).Where("is_active = ? AND id != ?", true, id).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Workspace{}).Where("id = ?", id).Update("is_active", true).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("切换工作区失败: %v", err)
+	}
+
+	workspace, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	listeners := append([]func(*models.Workspace){}, s.listeners...)
+	s.mu.Unlock()
+	for _, fn := range listeners {
+		fn(workspace)
+	}
+	return workspace, nil
+}
+
+// Clone 复制工作区，包括服务器、工具的启用状态和分类、标签以及根目录
+func (s *WorkspaceService) Clone(id uint, req *models.WorkspaceCloneRequest) (*models.Workspace, error) {
+	source, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkName(req.Name, 0); err != nil {
+		return nil, err
+	}
+
+	clone := &models.Workspace{
+		Name:           req.Name,
+		Description:    req.Description,
+		SamplingPolicy: source.SamplingPolicy,
+	}
+	if clone.Description == "" {
+		clone.Description = source.Description
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(clone).Error; err != nil {
+			return fmt.Errorf("创建工作区失败: %v", err)
+		}
+
+		var servers []models.MCPServer
+		if err := tx.Preload("Tools.Tags").Preload("Tags").Where("workspace_id = ?", id).Order("id").Find(&servers).Error; err != nil {
+			return fmt.Errorf("查询服务器失败: %v", err)
+		}
+		for _, server := range servers {
+			if err := cloneServer(tx, server, clone.ID); err != nil {
+				return err
+			}
+		}
+
+		// 对工作区内所有服务器生效的根目录
+		var roots []models.WorkspaceRoot
+		if err := tx.Where("workspace_id = ? AND server_id IS NULL", id).Find(&roots).Error; err != nil {
+			return fmt.Errorf("查询根目录失败: %v", err)
+		}
+		for _, root := range roots {
+			root.ID = 0
+			root.WorkspaceID = clone.ID
+			if err := tx.Create(&root).Error; err != nil {
+				return fmt.Errorf("复制根目录失败: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Activate {
+		return s.Switch(clone.ID)
+	}
+	return s.Get(clone.ID)
+}
+
+// checkName 检查工作区名称是否重复
+func (s *WorkspaceService) checkName(name string, excludeID uint) error {
+	var count int64
+	if err := s.db.Model(&models.Workspace{}).Where("name = ? AND id != ?", name, excludeID).Count(&count).Error; err != nil {
+		return fmt.Errorf("检查名称重复失败: %v", err)
+	}
+	if count > 0 {
+		return ErrWorkspaceNameExists
+	}
+	return nil
+}
+
+// cloneServer 将服务器及其工具、标签和根目录复制到指定工作区
+func cloneServer(tx *gorm.DB, server models.MCPServer, workspaceID uint) error {
+	oldID := server.ID
+	tools := server.Tools
+	tags := server.GetTagList()
+
+	server.ID = 0
+	server.WorkspaceID = workspaceID
+	server.Tools = nil
+	server.Tags = nil
+	if err := tx.Omit(clause.Associations).Create(&server).Error; err != nil {
+		return fmt.Errorf("复制服务器 %s 失败: %v", server.Name, err)
+	}
+	if err := replaceTags(tx, &server, tags); err != nil {
+		return err
+	}
+
+	for _, tool := range tools {
+		toolTags := tool.Tags.Names()
+		tool.ID = 0
+		tool.ServerID = server.ID
+		tool.Tags = nil
+		if err := tx.Omit(clause.Associations).Create(&tool).Error; err != nil {
+			return fmt.Errorf("复制工具 %s 失败: %v", tool.Name, err)
+		}
+		if err := replaceTags(tx, &tool, toolTags); err != nil {
+			return err
+		}
+	}
+
+	var roots []models.WorkspaceRoot
+	if err := tx.Where("server_id = ?", oldID).Find(&roots).Error; err != nil {
+		return fmt.Errorf("查询根目录失败: %v", err)
+	}
+	for _, root := range roots {
+		root.ID = 0
+		root.WorkspaceID = workspaceID
+		root.ServerID = &server.ID
+		if err := tx.Create(&root).Error; err != nil {
+			return fmt.Errorf("复制根目录失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// activeWorkspace 获取当前激活的工作区
+func activeWorkspace(db *gorm.DB) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := db.Where("is_active = ?", true).First(&workspace).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNoActiveWorkspace
+		}
+		return nil, fmt.Errorf("查询当前工作区失败: %v", err)
+	}
+	return &workspace, nil
+}
+
+// activeWorkspaceID 获取当前激活的工作区ID
+func activeWorkspaceID(db *gorm.DB) (uint, error) {
+	workspace, err := activeWorkspace(db)
+	if err != nil {
+		return 0, err
+	}
+	return workspace.ID, nil
+}
+
+// inWorkspace 将服务器查询限定在指定工作区
+func inWorkspace(workspaceID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("mcp_servers.workspace_id = ?", workspaceID)
+	}
+}
+
+// toolsInWorkspace 将工具查询限定在指定工作区的服务器
+func toolsInWorkspace(workspaceID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		servers := db.Session(&gorm.Session{NewDB: true}).Model(&models.MCPServer{}).Select("id").Where("workspace_id = ?", workspaceID)
+		return db.Where("mcp_tools.server_id IN (?)", servers)
+	}
+}
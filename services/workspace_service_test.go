@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"desktop-ai-tools/models"
+)
+
+// TestWorkspaceScopeAndClone 测试服务器和工具查询限定在当前工作区，以及复制工作区
+func TestWorkspaceScopeAndClone(t *testing.T) {
+	db := newTestDB(t)
+	workspaces := NewWorkspaceService(db)
+	servers := &MCPServerService{db: db}
+	tools := &MCPToolService{db: db}
+
+	defaultWorkspace, err := workspaces.Active()
+	if err != nil {
+		t.Fatalf("查询默认工作区失败: %v", err)
+	}
+	server, err := servers.Create(&models.MCPServerCreateRequest{Name: "github", URL: "https://example.com/mcp", AuthType: "none", Tags: []string{"dev"}})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	tool := models.MCPTool{ServerID: server.ID, Name: "search", Category: "自定义"}
+	if err := db.Create(&tool).Error; err != nil {
+		t.Fatalf("创建工具失败: %v", err)
+	}
+
+	clone, err := workspaces.Clone(defaultWorkspace.ID, &models.WorkspaceCloneRequest{Name: "副本", Activate: true})
+	if err != nil {
+		t.Fatalf("复制工作区失败: %v", err)
+	}
+	if !clone.IsActive {
+		t.Fatalf("复制后应切换到新工作区")
+	}
+
+	// 新工作区中的服务器是独立的副本
+	resp, err := servers.GetList(&models.MCPServerListRequest{Page: 1, Size: 10, OrderBy: "name", OrderDir: "asc", TagMode: "or"})
+	if err != nil {
+		t.Fatalf("查询服务器失败: %v", err)
+	}
+	if resp.Total != 1 || resp.Servers[0].ID == server.ID || resp.Servers[0].WorkspaceID != clone.ID {
+		t.Fatalf("新工作区的服务器不正确: %+v", resp.Servers)
+	}
+	if names := resp.Servers[0].GetTagList(); len(names) != 1 || names[0] != "dev" {
+		t.Fatalf("服务器标签未复制: %v", names)
+	}
+	if _, err := servers.GetByID(server.ID); err == nil {
+		t.Fatalf("不应能访问其他工作区的服务器")
+	}
+
+	toolResp, err := tools.GetToolsByServer(&models.MCPToolListRequest{Page: 1, Size: 10})
+	if err != nil {
+		t.Fatalf("查询工具失败: %v", err)
+	}
+	if toolResp.Total != 1 || toolResp.Tools[0].ServerID != resp.Servers[0].ID || toolResp.Tools[0].Category != "自定义" {
+		t.Fatalf("新工作区的工具不正确: %+v", toolResp.Tools)
+	}
+	if err := tools.UpdateTool(tool.ID, &models.MCPToolUpdateRequest{Category: "已修改"}); err != nil {
+		t.Fatalf("更新工具失败: %v", err)
+	}
+	db.First(&tool, tool.ID)
+	if tool.Category != "自定义" {
+		t.Fatalf("不应能修改其他工作区的工具")
+	}
+
+	// 同名服务器只在同一工作区内冲突
+	if _, err := servers.Create(&models.MCPServerCreateRequest{Name: "github", URL: "https://example.com/mcp", AuthType: "none"}); err == nil {
+		t.Fatalf("同一工作区内服务器名称应唯一")
+	}
+	if err := workspaces.Delete(clone.ID); err == nil {
+		t.Fatalf("不应能删除当前激活的工作区")
+	}
+
+	if _, err := workspaces.Switch(defaultWorkspace.ID); err != nil {
+		t.Fatalf("切换工作区失败: %v", err)
+	}
+	if err := workspaces.Delete(clone.ID); err != nil {
+		t.Fatalf("删除工作区失败: %v", err)
+	}
+	if _, err := servers.GetByID(server.ID); err != nil {
+		t.Fatalf("删除其他工作区不应影响当前工作区: %v", err)
+	}
+}
+
+// TestTagsAndRootsWorkspaceScope 测试标签和根目录限定在当前工作区，不能查看或修改其他工作区的标签、工具和根目录
+func TestTagsAndRootsWorkspaceScope(t *testing.T) {
+	db := newTestDB(t)
+	workspaces := NewWorkspaceService(db)
+	servers := &MCPServerService{db: db}
+	tags := NewTagService(db)
+	roots := NewRootsService(db)
+
+	// 默认工作区中的服务器、工具和根目录
+	first, err := workspaces.Active()
+	if err != nil {
+		t.Fatalf("查询默认工作区失败: %v", err)
+	}
+	a, err := servers.Create(&models.MCPServerCreateRequest{Name: "a", URL: "https://example.com/a", AuthType: "none", Tags: []string{"shared", "only-a"}})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	tool := models.MCPTool{ServerID: a.ID, Name: "search"}
+	if err := db.Create(&tool).Error; err != nil {
+		t.Fatalf("创建工具失败: %v", err)
+	}
+	global, err := roots.Create(&models.WorkspaceRootCreateRequest{Name: "global", Path: t.TempDir()})
+	if err != nil {
+		t.Fatalf("创建根目录失败: %v", err)
+	}
+	tagIDs := map[string]uint{}
+	for _, tag := range a.Tags {
+		tagIDs[tag.Name] = tag.ID
+	}
+
+	second, err := workspaces.Create(&models.WorkspaceCreateRequest{Name: "second"})
+	if err != nil {
+		t.Fatalf("创建工作区失败: %v", err)
+	}
+	if _, err := workspaces.Switch(second.ID); err != nil {
+		t.Fatalf("切换工作区失败: %v", err)
+	}
+	b, err := servers.Create(&models.MCPServerCreateRequest{Name: "b", URL: "https://example.com/b", AuthType: "none", Tags: []string{"shared"}})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+
+	// 标签：其他工作区的工具和标签不可修改，列表和数量只包含当前工作区
+	if _, err := tags.SetToolTags(tool.ID, []string{"hacked"}); !errors.Is(err, ErrToolNotFound) {
+		t.Fatalf("不应能设置其他工作区工具的标签，实际: %v", err)
+	}
+	list, err := tags.List(&models.TagListRequest{})
+	if err != nil {
+		t.Fatalf("查询标签失败: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "shared" || list[0].ServerCount != 1 {
+		t.Fatalf("应只列出当前工作区的标签和数量: %+v", list)
+	}
+	if err := tags.Delete(tagIDs["only-a"]); !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("不应能删除只在其他工作区使用的标签，实际: %v", err)
+	}
+	renamed, err := tags.Rename(tagIDs["shared"], &models.TagRenameRequest{Name: "common"})
+	if err != nil || renamed.Name != "common" || renamed.ServerCount != 1 {
+		t.Fatalf("重命名标签失败: %+v, %v", renamed, err)
+	}
+	var other models.MCPServer
+	db.Preload("Tags").First(&other, a.ID)
+	names := other.GetTagList()
+	sort.Strings(names)
+	if strings.Join(names, ",") != "only-a,shared" {
+		t.Fatalf("重命名不应影响其他工作区的服务器: %v", names)
+	}
+	if err := tags.Delete(renamed.ID); err != nil {
+		t.Fatalf("删除标签失败: %v", err)
+	}
+	db.Preload("Tags").First(&other, a.ID)
+	if len(other.Tags) != 2 {
+		t.Fatalf("删除标签不应影响其他工作区的服务器: %v", other.GetTagList())
+	}
+
+	// 根目录：不能为其他工作区的服务器创建，不能查看或删除其他工作区的根目录
+	if _, err := roots.Create(&models.WorkspaceRootCreateRequest{ServerID: &a.ID, Path: t.TempDir()}); !errors.Is(err, ErrServerNotFound) {
+		t.Fatalf("不应能为其他工作区的服务器创建根目录，实际: %v", err)
+	}
+	rootList, err := roots.GetList(&models.WorkspaceRootListRequest{})
+	if err != nil || len(rootList.Roots) != 0 {
+		t.Fatalf("不应列出其他工作区的根目录: %+v, %v", rootList, err)
+	}
+	if err := roots.Delete(global.ID); !errors.Is(err, ErrRootNotFound) {
+		t.Fatalf("不应能删除其他工作区的根目录，实际: %v", err)
+	}
+	if forB, _ := roots.GetForServer(b.ID); len(forB) != 0 {
+		t.Fatalf("其他工作区的全局根目录不应对该服务器生效: %+v", forB)
+	}
+	if forA, _ := roots.GetForServer(a.ID); len(forA) != 1 || forA[0].WorkspaceID != first.ID {
+		t.Fatalf("全局根目录应对所在工作区的服务器生效: %+v", forA)
+	}
+}
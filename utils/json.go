@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 )
@@ -67,4 +68,85 @@ func PrintJSONCompact(data interface{}, prefix ...string) {
 	} else {
 		fmt.Println(jsonStr)
 	}
-}
\ No newline at end of file
+}
+
+// CanonicalJSON 将数据转换为规范化的JSON字符串
+// 对象的键按字典序排列，数字保持原始精度，可用作缓存键
+// 参数:
+//   - data: 要转换的数据结构
+// 返回值:
+//   - string: 规范化的JSON字符串
+//   - error: 转换过程中的错误
+func CanonicalJSON(data interface{}) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("JSON序列化失败: %v", err)
+	}
+
+	// 重新解码为通用结构，encoding/json 在编码 map 时会对键排序
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var normalized interface{}
+	if err := decoder.Decode(&normalized); err != nil {
+		return "", fmt.Errorf("JSON解析失败: %v", err)
+	}
+
+	jsonBytes, err := json.Marshal(normalized)
+	if err != nil {
+		return "", fmt.Errorf("JSON序列化失败: %v", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// StripJSONComments 去除JSONC内容中的注释和尾随逗号，使其可被标准库解析
+// VS Code 等客户端的配置文件允许使用 // 和 /* */ 注释
+// 参数:
+//   - data: JSONC格式的内容
+// 返回值:
+//   - []byte: 标准JSON内容
+func StripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+		if inString {
+			out = append(out, ch)
+			if ch == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case ch == '"':
+			inString = true
+			out = append(out, ch)
+		case ch == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case ch == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case ch == '}' || ch == ']':
+			// 移除紧邻的尾随逗号
+			trimmed := bytes.TrimRight(out, " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				out = append(trimmed[:len(trimmed)-1], out[len(trimmed):]...)
+			}
+			out = append(out, ch)
+		default:
+			out = append(out, ch)
+		}
+	}
+	return out
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -65,4 +66,61 @@ func TestPrintJSON(t *testing.T) {
 	// 测试不带前缀的打印
 	t.Log("测试PrintJSON函数（不带前缀）:")
 	PrintJSON(testData)
-}
\ No newline at end of file
+}
+
+// TestCanonicalJSON 测试CanonicalJSON函数
+func TestCanonicalJSON(t *testing.T) {
+	// 键顺序不同但内容相同的两个对象
+	a := map[string]interface{}{
+		"path":  "/tmp",
+		"limit": 10,
+		"options": map[string]interface{}{
+			"recursive": true,
+			"depth":     2,
+		},
+	}
+	b := []byte(`{"options":{"depth":2,"recursive":true},"limit":10,"path":"/tmp"}`)
+
+	keyA, err := CanonicalJSON(a)
+	if err != nil {
+		t.Fatalf("CanonicalJSON失败: %v", err)
+	}
+	keyB, err := CanonicalJSON(json.RawMessage(b))
+	if err != nil {
+		t.Fatalf("CanonicalJSON失败: %v", err)
+	}
+
+	if keyA != keyB {
+		t.Fatalf("规范化结果不一致: %s != %s", keyA, keyB)
+	}
+
+	// 大整数不应丢失精度
+	big, err := CanonicalJSON(json.RawMessage(`{"id":12345678901234567890}`))
+	if err != nil {
+		t.Fatalf("CanonicalJSON失败: %v", err)
+	}
+	if big != `{"id":12345678901234567890}` {
+		t.Fatalf("大整数精度丢失: %s", big)
+	}
+}
+
+// TestStripJSONComments 测试StripJSONComments函数
+func TestStripJSONComments(t *testing.T) {
+	input := []byte(`{
+  // 行注释
+  "url": "http://localhost/sse", /* 块注释 */
+  "note": "包含 // 和 /* 的字符串",
+  "args": ["a", "b",],
+}`)
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(StripJSONComments(input), &parsed); err != nil {
+		t.Fatalf("解析去除注释后的内容失败: %v", err)
+	}
+	if parsed["note"] != "包含 // 和 /* 的字符串" {
+		t.Fatalf("字符串内容被错误修改: %v", parsed["note"])
+	}
+	if args, ok := parsed["args"].([]interface{}); !ok || len(args) != 2 {
+		t.Fatalf("尾随逗号处理错误: %v", parsed["args"])
+	}
+}
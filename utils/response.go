@@ -5,11 +5,14 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"desktop-ai-tools/i18n"
 )
 
 // APIResponse 统一API响应结构
 type APIResponse struct {
 	Success   bool        `json:"success"`
+	Code      string      `json:"code,omitempty"` // 错误码，仅失败时返回
 	Message   string      `json:"message,omitempty"`
 	Data      interface{} `json:"data,omitempty"`
 	Error     string      `json:"error,omitempty"`
@@ -31,60 +34,38 @@ func SuccessResponse(c *gin.Context, data interface{}, message ...string) {
 	c.JSON(http.StatusOK, response)
 }
 
-// ErrorResponse 错误响应
-func ErrorResponse(c *gin.Context, statusCode int, err error, message ...string) {
+// CreatedResponse 创建成功响应
+func CreatedResponse(c *gin.Context, data interface{}, message ...string) {
 	response := APIResponse{
-		Success:   false,
-		Error:     err.Error(),
+		Success:   true,
+		Data:      data,
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 	
 	if len(message) > 0 {
 		response.Message = message[0]
-	} else {
-		// 根据状态码设置默认消息
-		switch statusCode {
-		case http.StatusBadRequest:
-			response.Message = "请求参数错误"
-		case http.StatusNotFound:
-			response.Message = "资源未找到"
-		case http.StatusInternalServerError:
-			response.Message = "服务器内部错误"
-		default:
-			response.Message = "请求失败"
-		}
 	}
 	
-	// 使用Gin的Error方法记录错误，这样中间件可以捕获到
-	c.Error(err)
-	c.JSON(statusCode, response)
-}
-
-// BadRequestResponse 400错误响应
-func BadRequestResponse(c *gin.Context, err error, message ...string) {
-	ErrorResponse(c, http.StatusBadRequest, err, message...)
+	c.JSON(http.StatusCreated, response)
 }
 
-// NotFoundResponse 404错误响应
-func NotFoundResponse(c *gin.Context, err error, message ...string) {
-	ErrorResponse(c, http.StatusNotFound, err, message...)
-}
-
-// InternalServerErrorResponse 500错误响应
-func InternalServerErrorResponse(c *gin.Context, err error, message ...string) {
-	ErrorResponse(c, http.StatusInternalServerError, err, message...)
-}
-
-// ValidationErrorResponse 参数验证错误响应
-func ValidationErrorResponse(c *gin.Context, err error) {
-	BadRequestResponse(c, err, "请求参数验证失败")
+// ErrorResponse 错误响应，错误处理中间件统一调用
+func ErrorResponse(c *gin.Context, statusCode int, code, message, detail string) {
+	c.AbortWithStatusJSON(statusCode, APIResponse{
+		Success:   false,
+		Code:      code,
+		Message:   message,
+		Error:     detail,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
 }
 
 // PanicResponse 处理panic的响应
 func PanicResponse(c *gin.Context, recovered interface{}) {
 	response := APIResponse{
 		Success:   false,
-		Message:   "服务器内部错误",
+		Code:      "internal_error",
+		Message:   i18n.T(c, "internal_error"),
 		Error:     "系统发生了意外错误，请稍后重试",
 		Timestamp: time.Now().Format(time.RFC3339),
 	}